@@ -0,0 +1,36 @@
+/*
+Copyright 2016 The Kubernetes Authors All rights reserved.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package cmd
+
+import (
+	"github.com/spf13/cobra"
+	"k8s.io/minikube/pkg/localkube"
+)
+
+// logTagsCmd tags the output of localkube with the components logging it, see localkube.TagLogs.
+var logTagsCmd = &cobra.Command{
+	Use:    localkube.LogTaggerCommand,
+	Short:  "Tags the log lines of localkube with their component",
+	Hidden: true,
+	Run: func(command *cobra.Command, args []string) {
+		localkube.RunLogTagger()
+	},
+}
+
+func init() {
+	RootCmd.AddCommand(logTagsCmd)
+}
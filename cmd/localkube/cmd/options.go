@@ -17,9 +17,17 @@ limitations under the License.
 package cmd
 
 import (
+	"io/ioutil"
 	"net"
+	"os"
+	"path"
+	"strings"
+	"time"
 
 	flag "github.com/spf13/pflag"
+	"k8s.io/apiserver/pkg/storage/storagebackend"
+	"k8s.io/kubernetes/pkg/apis/componentconfig"
+	authzmodes "k8s.io/kubernetes/pkg/kubeapiserver/authorizer/modes"
 
 	"k8s.io/minikube/pkg/localkube"
 	"k8s.io/minikube/pkg/minikube/constants"
@@ -28,7 +36,8 @@ import (
 
 func NewLocalkubeServer() *localkube.LocalkubeServer {
 	// net.ParseCIDR returns multiple values. Use the IPNet return value
-	_, defaultServiceClusterIPRange, _ := net.ParseCIDR(util.DefaultServiceClusterIP + "/24")
+	_, defaultServiceClusterIPRange, _ := net.ParseCIDR(util.DefaultServiceCIDR)
+	_, defaultPodCIDR, _ := net.ParseCIDR(util.DefaultPodCIDR)
 
 	return &localkube.LocalkubeServer{
 		Containerized:            false,
@@ -36,6 +45,7 @@ func NewLocalkubeServer() *localkube.LocalkubeServer {
 		DNSIP:                    net.ParseIP(util.DefaultDNSIP),
 		LocalkubeDirectory:       util.DefaultLocalkubeDirectory,
 		ServiceClusterIPRange:    *defaultServiceClusterIPRange,
+		PodCIDR:                  *defaultPodCIDR,
 		APIServerAddress:         net.ParseIP("0.0.0.0"),
 		APIServerPort:            util.APIServerPort,
 		APIServerInsecureAddress: net.ParseIP("127.0.0.1"),
@@ -45,32 +55,26 @@ func NewLocalkubeServer() *localkube.LocalkubeServer {
 		ShowVersion:              false,
 		RuntimeConfig:            map[string]string{"api/all": "true"},
 		ExtraConfig:              util.ExtraOptionSlice{},
+		ReadyTimeout:             5 * time.Minute,
+		ComponentReadyTimeouts:   map[string]string{},
+		StatusAddress:            util.DefaultLocalkubeStatusAddress,
+		ConfigFile:               path.Join(util.DefaultLocalkubeDirectory, "localkube.conf"),
+		ShutdownGracePeriod:      30 * time.Second,
+		StorageBackend:           storagebackend.StorageTypeETCD2,
+		AdmissionControl:         util.DefaultAdmissionControl,
+		AuthorizationMode:        authzmodes.ModeAlwaysAllow,
+		AuditLogMaxAge:           7,
+		AuditLogMaxBackups:       3,
+		AuditLogMaxSize:          100,
+		HostPathProvisionerDir:   util.DefaultHostPathProvisionerDir,
+		StorageClassDirs:         map[string]string{},
+		ProxyMode:                string(componentconfig.ProxyModeIPTables),
 	}
 }
 
 // AddFlags adds flags for a specific LocalkubeServer
 func AddFlags(s *localkube.LocalkubeServer) {
-	flag.BoolVar(&s.Containerized, "containerized", s.Containerized, "If kubelet should run in containerized mode")
-	flag.BoolVar(&s.EnableDNS, "enable-dns", s.EnableDNS, "DEPRECATED: Please run kube-dns as a cluster addon")
-	flag.StringVar(&s.DNSDomain, "dns-domain", s.DNSDomain, "The cluster dns domain")
-	flag.IPVar(&s.DNSIP, "dns-ip", s.DNSIP, "The cluster dns IP")
-	flag.StringVar(&s.LocalkubeDirectory, "localkube-directory", s.LocalkubeDirectory, "The directory localkube will store files in")
-	flag.IPNetVar(&s.ServiceClusterIPRange, "service-cluster-ip-range", s.ServiceClusterIPRange, "The service-cluster-ip-range for the apiserver")
-	flag.IPVar(&s.APIServerAddress, "apiserver-address", s.APIServerAddress, "The address the apiserver will listen securely on")
-	flag.IntVar(&s.APIServerPort, "apiserver-port", s.APIServerPort, "The port the apiserver will listen securely on")
-	flag.IPVar(&s.APIServerInsecureAddress, "apiserver-insecure-address", s.APIServerInsecureAddress, "The address the apiserver will listen insecurely on")
-	flag.IntVar(&s.APIServerInsecurePort, "apiserver-insecure-port", s.APIServerInsecurePort, "The port the apiserver will listen insecurely on")
-	flag.StringVar(&s.APIServerName, "apiserver-name", s.APIServerName, "The apiserver name which is used in the generated certificate for localkube/kubernetes.  This can be used if you want to make the API server available from outside the machine")
-
-	flag.BoolVar(&s.ShouldGenerateCerts, "generate-certs", s.ShouldGenerateCerts, "If localkube should generate it's own certificates")
-	flag.BoolVar(&s.ShowVersion, "show-version", s.ShowVersion, "If localkube should just print the version and exit.")
-	flag.BoolVar(&s.ShowHostIP, "host-ip", s.ShowHostIP, "If localkube should just print the host IP and exit.")
-	flag.Var(&s.RuntimeConfig, "runtime-config", "A set of key=value pairs that describe runtime configuration that may be passed to apiserver. apis/<groupVersion> key can be used to turn on/off specific api versions. apis/<groupVersion>/<resource> can be used to turn on/off specific resources. api/all and api/legacy are special keys to control all and legacy api versions respectively.")
-	flag.IPVar(&s.NodeIP, "node-ip", s.NodeIP, "IP address of the node. If set, kubelet will use this IP address for the node.")
-	flag.StringVar(&s.ContainerRuntime, "container-runtime", "", "The container runtime to be used")
-	flag.StringVar(&s.NetworkPlugin, "network-plugin", "", "The name of the network plugin")
-	flag.StringVar(&s.FeatureGates, "feature-gates", "", "A set of key=value pairs that describe feature gates for alpha/experimental features.")
-	flag.Var(&s.ExtraConfig, "extra-config", "A set of key=value pairs that describe configuration that may be passed to different components. The key should be '.' separated, and the first part before the dot is the component to apply the configuration to.")
+	addFlags(flag.CommandLine, s)
 
 	// These two come from vendor/ packages that use flags. We should hide them
 	flag.CommandLine.MarkHidden("google-json-key")
@@ -79,3 +83,105 @@ func AddFlags(s *localkube.LocalkubeServer) {
 	// Parse them
 	flag.Parse()
 }
+
+func addFlags(fs *flag.FlagSet, s *localkube.LocalkubeServer) {
+	fs.BoolVar(&s.Containerized, "containerized", s.Containerized, "If kubelet should run in containerized mode")
+	fs.BoolVar(&s.EnableDNS, "enable-dns", s.EnableDNS, "DEPRECATED: Please run kube-dns as a cluster addon")
+	fs.StringVar(&s.DNSDomain, "dns-domain", s.DNSDomain, "The cluster dns domain")
+	fs.IPVar(&s.DNSIP, "dns-ip", s.DNSIP, "The cluster dns IP")
+	fs.StringVar(&s.LocalkubeDirectory, "localkube-directory", s.LocalkubeDirectory, "The directory localkube will store files in")
+	fs.IPNetVar(&s.ServiceClusterIPRange, "service-cluster-ip-range", s.ServiceClusterIPRange, "The service-cluster-ip-range for the apiserver")
+	fs.IPNetVar(&s.PodCIDR, "pod-cidr", s.PodCIDR, "The CIDR the kubelet assigns pod IPs from")
+	fs.IPVar(&s.APIServerAddress, "apiserver-address", s.APIServerAddress, "The address the apiserver will listen securely on")
+	fs.IntVar(&s.APIServerPort, "apiserver-port", s.APIServerPort, "The port the apiserver will listen securely on")
+	fs.IPVar(&s.APIServerInsecureAddress, "apiserver-insecure-address", s.APIServerInsecureAddress, "The address the apiserver will listen insecurely on")
+	fs.IntVar(&s.APIServerInsecurePort, "apiserver-insecure-port", s.APIServerInsecurePort, "The port the apiserver will listen insecurely on")
+	fs.StringVar(&s.APIServerName, "apiserver-name", s.APIServerName, "The apiserver name which is used in the generated certificate for localkube/kubernetes.  This can be used if you want to make the API server available from outside the machine")
+	fs.StringSliceVar(&s.APIServerExtraSANs, "apiserver-extra-sans", s.APIServerExtraSANs, "Extra hostnames and IPs the generated apiserver certificate is valid for, e.g. localhost,127.0.0.1,minikube.example.com")
+
+	fs.BoolVar(&s.ShouldGenerateCerts, "generate-certs", s.ShouldGenerateCerts, "If localkube should generate it's own certificates")
+	fs.BoolVar(&s.ShowVersion, "show-version", s.ShowVersion, "If localkube should just print the version and exit.")
+	fs.BoolVar(&s.ShowHostIP, "host-ip", s.ShowHostIP, "If localkube should just print the host IP and exit.")
+	fs.Var(&s.RuntimeConfig, "runtime-config", "A set of key=value pairs that describe runtime configuration that may be passed to apiserver. apis/<groupVersion> key can be used to turn on/off specific api versions. apis/<groupVersion>/<resource> can be used to turn on/off specific resources. api/all and api/legacy are special keys to control all and legacy api versions respectively.")
+	fs.IPVar(&s.NodeIP, "node-ip", s.NodeIP, "IP address of the node. If set, kubelet will use this IP address for the node.")
+	fs.StringVar(&s.ContainerRuntime, "container-runtime", s.ContainerRuntime, "The container runtime to be used")
+	fs.StringVar(&s.NetworkPlugin, "network-plugin", s.NetworkPlugin, "The name of the network plugin")
+	fs.StringVar(&s.FeatureGates, "feature-gates", s.FeatureGates, "A set of key=value pairs that describe feature gates for alpha/experimental features.")
+	fs.Var(&s.ExtraConfig, "extra-config", "A set of key=value pairs that describe configuration that may be passed to different components. The key should be '.' separated, and the first part before the dot is the component to apply the configuration to.")
+	fs.DurationVar(&s.ReadyTimeout, "ready-timeout", s.ReadyTimeout, "How long each component is given to become ready before localkube exits with an error")
+	fs.Var(&s.ComponentReadyTimeouts, "component-ready-timeouts", "A set of component=duration pairs that override --ready-timeout for specific components, e.g. apiserver=10m,kubelet=2m")
+	fs.StringVar(&s.StatusAddress, "status-address", s.StatusAddress, "The address localkube serves the status of its components on, either host:port or unix:///path/to/socket")
+	fs.StringVar(&s.ConfigFile, "config-file", s.ConfigFile, "A file of additional flags, one per line, applied on top of the command line flags. It is re-read when localkube receives SIGHUP")
+	fs.DurationVar(&s.ShutdownGracePeriod, "shutdown-grace-period", s.ShutdownGracePeriod, "How long localkube waits for its components and etcd to stop before exiting")
+	fs.StringVar(&s.StorageBackend, "storage-backend", s.StorageBackend, "The storage backend the apiserver uses, etcd2 or etcd3. With etcd3 the embedded etcd also serves the v3 gRPC API, and existing etcd2 data is copied to the v3 keyspace the first time")
+	fs.StringSliceVar(&s.EtcdServers, "etcd-servers", s.EtcdServers, "A comma separated list of external etcd servers the apiserver uses instead of the embedded etcd, e.g. https://10.0.0.5:2379. The embedded etcd is not started when this is set")
+	fs.StringVar(&s.EtcdCAFile, "etcd-cafile", s.EtcdCAFile, "The CA file used to verify the external etcd servers")
+	fs.StringVar(&s.EtcdCertFile, "etcd-certfile", s.EtcdCertFile, "The client certificate file used to authenticate to the external etcd servers")
+	fs.StringVar(&s.EtcdKeyFile, "etcd-keyfile", s.EtcdKeyFile, "The client key file used to authenticate to the external etcd servers")
+	fs.StringSliceVar(&s.AdmissionControl, "admission-control", s.AdmissionControl, "The admission plugins the apiserver runs, in order")
+	fs.StringVar(&s.AuthorizationMode, "authorization-mode", s.AuthorizationMode, "A comma separated list of the authorization modes of the apiserver, in order: "+strings.Join(authzmodes.AuthorizationModeChoices, ", ")+". With RBAC, the minikube client certificate and the kube-system default service account are bound to cluster-admin")
+	fs.StringVar(&s.AuthorizationWebhookFile, "authorization-webhook-config-file", s.AuthorizationWebhookFile, "The kubeconfig file of the webhook used with --authorization-mode=Webhook")
+	fs.StringVar(&s.AuditLogPath, "audit-log-path", s.AuditLogPath, "The file the apiserver writes its audit log to. Auditing is disabled if this is empty")
+	fs.IntVar(&s.AuditLogMaxAge, "audit-log-maxage", s.AuditLogMaxAge, "The maximum number of days to retain rotated audit log files")
+	fs.IntVar(&s.AuditLogMaxBackups, "audit-log-maxbackup", s.AuditLogMaxBackups, "The maximum number of rotated audit log files to retain")
+	fs.IntVar(&s.AuditLogMaxSize, "audit-log-maxsize", s.AuditLogMaxSize, "The maximum size in megabytes of the audit log before it is rotated")
+	fs.StringVar(&s.HostPathProvisionerDir, "hostpath-provisioner-dir", s.HostPathProvisionerDir, "The directory the storage provisioner creates volumes in, e.g. /data/hostpath-provisioner to keep them on the persistent disk")
+	fs.BoolVar(&s.HostPathProvisionerEnforceSize, "hostpath-provisioner-enforce-size", s.HostPathProvisionerEnforceSize, "If the storage provisioner backs each volume with a loop mounted ext4 image of the requested size, so volumes cannot grow past it")
+	fs.Var(&s.StorageClassDirs, "storage-class-dirs", "A set of class=directory pairs the storage provisioner creates the volumes of specific storage classes in, e.g. fast=/data/fast,slow=/data/slow. Other classes use --hostpath-provisioner-dir")
+	fs.StringVar(&s.ProxyMode, "proxy-mode", s.ProxyMode, "The mode of the proxy, iptables or userspace")
+	fs.BoolVar(&s.MasqueradeAll, "masquerade-all", s.MasqueradeAll, "If the proxy SNATs all traffic sent via service cluster IPs, rather than only traffic from outside the cluster CIDR")
+	fs.StringVar(&s.ClusterCIDR, "cluster-cidr", s.ClusterCIDR, "The CIDR of the pods in the cluster, which the proxy uses to tell traffic from outside the cluster, and masquerades. Defaults to --pod-cidr")
+	fs.StringVar(&s.AuditPolicyFile, "audit-policy-file", s.AuditPolicyFile, "The audit policy the apiserver logs requests with. Without it, a default policy that logs the metadata of requests, except for health checks, events and leader election, is used")
+	fs.StringVar(&s.JoinAPIServer, "join", s.JoinAPIServer, "The URL of the apiserver of the cluster to join as a worker node, e.g. https://192.168.99.100:8443. A worker only runs the kubelet and proxy, and authenticates with node.crt and node.key in the certificate directory")
+}
+
+// newFlagSet returns localkube's flags bound to a copy of s, whose values tell which settings
+// differ between two configurations.
+func newFlagSet(s localkube.LocalkubeServer) *flag.FlagSet {
+	fs := flag.NewFlagSet("localkube", flag.ContinueOnError)
+	addFlags(fs, &s)
+	return fs
+}
+
+// applyConfigFile parses the flags in the config file at path into fs. Blank lines and lines
+// starting with # are ignored. A missing config file is not an error.
+func applyConfigFile(fs *flag.FlagSet, path string) error {
+	if path == "" {
+		return nil
+	}
+	contents, err := ioutil.ReadFile(path)
+	if os.IsNotExist(err) {
+		return nil
+	}
+	if err != nil {
+		return err
+	}
+
+	args := []string{}
+	for _, line := range strings.Split(string(contents), "\n") {
+		line = strings.TrimSpace(line)
+		if line == "" || strings.HasPrefix(line, "#") {
+			continue
+		}
+		args = append(args, line)
+	}
+	return fs.Parse(args)
+}
+
+// LoadServer creates a new LocalkubeServer from the command line localkube was started with and
+// the current contents of its config file.
+func LoadServer() (*localkube.LocalkubeServer, error) {
+	s := NewLocalkubeServer()
+	fs := flag.NewFlagSet("localkube", flag.ContinueOnError)
+	addFlags(fs, s)
+	// Accept the flags registered by vendored packages, they don't affect the server.
+	fs.AddFlagSet(flag.CommandLine)
+
+	if err := fs.Parse(os.Args[1:]); err != nil {
+		return nil, err
+	}
+	if err := applyConfigFile(fs, s.ConfigFile); err != nil {
+		return nil, err
+	}
+	return s, nil
+}
@@ -20,19 +20,29 @@ import (
 	"fmt"
 	"os"
 	"os/signal"
+	"strings"
+	"syscall"
+	"time"
 
 	"github.com/golang/glog"
+	flag "github.com/spf13/pflag"
+	"k8s.io/apiserver/pkg/storage/storagebackend"
 	"k8s.io/apiserver/pkg/util/feature"
 
 	"k8s.io/kubernetes/pkg/capabilities"
+	kubeapioptions "k8s.io/kubernetes/pkg/kubeapiserver/options"
 	"k8s.io/kubernetes/pkg/kubelet/types"
 	"k8s.io/minikube/pkg/localkube"
+	"k8s.io/minikube/pkg/util"
 	"k8s.io/minikube/pkg/version"
 )
 
 // The main instance of the current localkube server that is started
 var Server *localkube.LocalkubeServer
 
+// certsFingerprint is the fingerprint of the certificates localkube was started with.
+var certsFingerprint string
+
 func StartLocalkube() {
 
 	if Server.ShowVersion {
@@ -46,56 +56,208 @@ func StartLocalkube() {
 		os.Exit(0)
 	}
 
+	if err := localkube.TagLogs(); err != nil {
+		fmt.Printf("Error tagging the log lines with their components, logging them untagged: %s\n", err)
+	}
+
 	// TODO: Require root
 
-	SetupServer(Server)
-	Server.StartAll()
+	if err := applyConfigFile(flag.CommandLine, Server.ConfigFile); err != nil {
+		fmt.Printf("Error reading config file %s: %s\n", Server.ConfigFile, err)
+		os.Exit(1)
+	}
+
+	if err := validateServer(Server); err != nil {
+		fmt.Println(err)
+		os.Exit(1)
+	}
+
+	if component := os.Getenv(localkube.ComponentEnv); component != "" {
+		runComponent(Server, component)
+		return
+	}
+
+	etcd := SetupServer(Server)
+	certsFingerprint = Server.GetCertsFingerprint()
+	go func() {
+		if err := Server.ServeStatus(etcd); err != nil {
+			glog.Errorf("Error serving localkube status: %s", err)
+		}
+	}()
+	if err := Server.StartAll(Server.GetReadyTimeout); err != nil {
+		fmt.Printf("Error starting localkube:\n%s\n", err)
+		shutdown(etcd)
+		os.Exit(1)
+	}
+
+	signals := make(chan os.Signal, 1)
+	signal.Notify(signals, os.Interrupt, syscall.SIGTERM, syscall.SIGHUP)
+
+	for sig := range signals {
+		if sig == syscall.SIGHUP {
+			reload(etcd)
+			continue
+		}
+		fmt.Println("Shutting down...")
+		shutdown(etcd)
+		return
+	}
+}
+
+// validateServer checks the configuration s, returning the error to report if it is invalid.
+func validateServer(s *localkube.LocalkubeServer) error {
+	if s.StorageBackend != storagebackend.StorageTypeETCD2 && s.StorageBackend != storagebackend.StorageTypeETCD3 {
+		return fmt.Errorf("Invalid storage backend %q, must be %s or %s", s.StorageBackend, storagebackend.StorageTypeETCD2, storagebackend.StorageTypeETCD3)
+	}
+	if err := util.ValidateEtcdConfig(s.EtcdServers, s.EtcdCAFile, s.EtcdCertFile, s.EtcdKeyFile); err != nil {
+		return fmt.Errorf("Invalid etcd configuration: %s", err)
+	}
+	if err := s.ValidateAuthorization(); err != nil {
+		return fmt.Errorf("Invalid authorization configuration: %s", err)
+	}
+	if err := s.ValidateWorkerConfig(); err != nil {
+		return fmt.Errorf("Invalid worker configuration: %s", err)
+	}
+	if err := util.ValidateProxyConfig(s.ProxyMode, s.ClusterCIDR); err != nil {
+		return fmt.Errorf("Invalid proxy configuration: %s", err)
+	}
+	if err := util.ValidateNetworkRanges(s.PodCIDR, s.ServiceClusterIPRange, s.DNSIP); err != nil {
+		return fmt.Errorf("Invalid network configuration: %s", err)
+	}
+	if _, _, err := util.ParseSANs(s.APIServerExtraSANs); err != nil {
+		return fmt.Errorf("Invalid apiserver certificate configuration: %s", err)
+	}
+	return nil
+}
+
+// runComponent runs the named component in the foreground, in the process localkube started for
+// it, and exits when the component does.
+func runComponent(s *localkube.LocalkubeServer, name string) {
+	setupProcess(s)
+	if err := s.RunComponent(name); err != nil {
+		fmt.Printf("Error running %s: %s\n", name, err)
+		os.Exit(1)
+	}
+}
+
+// shutdown stops all servers and then the embedded etcd, if it runs, giving up after the
+// shutdown grace period.
+func shutdown(etcd *localkube.EtcdServer) {
+	done := make(chan struct{})
+	go func() {
+		Server.StopAll()
+		if etcd != nil {
+			fmt.Println("Stopping etcd...")
+			etcd.Stop()
+		}
+		close(done)
+	}()
+
+	select {
+	case <-done:
+	case <-time.After(Server.ShutdownGracePeriod):
+		fmt.Printf("Components did not stop within %s, exiting anyway\n", Server.ShutdownGracePeriod)
+	}
+}
+
+// reload re-reads localkube's flags and config file. When only settings of single components
+// changed, just the servers of those components are restarted. When a setting shared by several
+// components changed, such as the names in the certificates or the etcd servers, an etcd snapshot
+// is waiting to be restored, or the certificates were replaced, localkube shuts down every
+// component and etcd cleanly and re-executes itself.
+func reload(etcd *localkube.EtcdServer) {
+	fmt.Println("Reloading configuration...")
+	s, err := LoadServer()
+	if err != nil {
+		fmt.Printf("Error reloading configuration, keeping the current one: %s\n", err)
+		return
+	}
+	if err := validateServer(s); err != nil {
+		fmt.Printf("%s, keeping the current configuration\n", err)
+		return
+	}
 
-	defer Server.StopAll()
+	changed := localkube.ChangedFlags(newFlagSet(*Server), newFlagSet(*s))
+	restart, full := localkube.PlanReload(*Server, *s, changed)
+	switch {
+	case full:
+		fmt.Printf("Settings changed: --%s, restarting localkube...\n", strings.Join(changed, ", --"))
+	case s.EtcdRestorePending() && !s.UseExternalEtcd():
+		fmt.Println("Restarting to restore an etcd snapshot...")
+	case s.GetCertsFingerprint() != certsFingerprint:
+		fmt.Println("Certificates changed, restarting...")
+	case len(changed) == 0:
+		fmt.Println("Configuration is unchanged.")
+		return
+	default:
+		fmt.Printf("Settings changed: --%s\n", strings.Join(changed, ", --"))
+		restartServers(s, restart)
+		return
+	}
 
-	interruptChan := make(chan os.Signal, 1)
-	signal.Notify(interruptChan, os.Interrupt)
+	executable, err := os.Executable()
+	if err != nil {
+		fmt.Printf("Error finding the localkube executable, keeping the current configuration: %s\n", err)
+		return
+	}
+	shutdown(etcd)
+	if err := syscall.Exec(executable, os.Args, os.Environ()); err != nil {
+		glog.Fatalf("Error restarting localkube: %s", err)
+	}
+}
 
-	<-interruptChan
-	fmt.Println("Shutting down...")
+// restartServers makes s the configuration of localkube, and replaces the named servers with new
+// ones created from it. The other servers and etcd keep running.
+func restartServers(s *localkube.LocalkubeServer, names []string) {
+	s.Servers = Server.Servers
+	*Server = *s
+	for _, name := range names {
+		// A worker doesn't run the servers of the apiserver's node
+		if _, err := Server.Get(name); err != nil {
+			continue
+		}
+		fmt.Printf("Restarting %s...\n", name)
+		if err := Server.Replace(Server.NewServer(name), Server.GetReadyTimeout(name)); err != nil {
+			fmt.Printf("Error restarting %s: %s\n", name, err)
+		}
+	}
 }
 
-func SetupServer(s *localkube.LocalkubeServer) {
-	if s.ShouldGenerateCerts {
+// SetupServer creates the servers localkube runs and starts the embedded etcd, which is returned.
+// When an external etcd is used, or localkube is a worker, the embedded etcd is not started and
+// nil is returned.
+func SetupServer(s *localkube.LocalkubeServer) *localkube.EtcdServer {
+	// A worker is given its certificates, it doesn't serve the apiserver
+	if s.ShouldGenerateCerts && !s.IsWorker() {
 		if err := s.GenerateCerts(); err != nil {
 			fmt.Println("Failed to create certificates!")
 			panic(err)
 		}
 	}
 
-	// Set feature gates
-	if s.FeatureGates != "" {
-		glog.Infof("Setting Feature Gates: %s", s.FeatureGates)
-		err := feature.DefaultFeatureGate.Set(s.FeatureGates)
-		if err != nil {
-			fmt.Printf("Error setting feature gates: %s", err)
-		}
+	setupProcess(s)
+
+	if err := s.SetupAudit(); err != nil {
+		fmt.Printf("Error setting up audit logging: %s\n", err)
 	}
 
-	// Setup capabilities. This can only be done once per binary.
-	allSources, _ := types.GetValidatedSources([]string{types.AllSource})
-	c := capabilities.Capabilities{
-		AllowPrivileged: true,
-		PrivilegedSources: capabilities.PrivilegedSources{
-			HostNetworkSources: allSources,
-			HostIPCSources:     allSources,
-			HostPIDSources:     allSources,
-		},
+	if s.IsWorker() {
+		setupWorker(s)
+		return nil
 	}
-	capabilities.Initialize(c)
 
-	// setup etcd
-	etcd, err := s.NewEtcd(localkube.KubeEtcdClientURLs, localkube.KubeEtcdPeerURLs, "kubeetcd", s.GetEtcdDataDirectory())
-	if err != nil {
-		panic(err)
+	var etcd *localkube.EtcdServer
+	if s.UseExternalEtcd() {
+		fmt.Printf("Using external etcd %s\n", strings.Join(s.EtcdServers, ","))
+		if s.EtcdRestorePending() {
+			fmt.Println("Ignoring etcd snapshot to restore, snapshots can only be restored into the embedded etcd")
+			if err := os.Remove(s.GetEtcdRestorePath()); err != nil {
+				fmt.Printf("Error removing etcd snapshot: %s\n", err)
+			}
+		}
+	} else {
+		etcd = setupEtcd(s)
 	}
-	// Start etcd first
-	etcd.Start()
 
 	// setup access to etcd
 	netIP, _ := s.GetHostIP()
@@ -123,4 +285,96 @@ func SetupServer(s *localkube.LocalkubeServer) {
 
 	storageProvisioner := s.NewStorageProvisionerServer()
 	s.AddServer(storageProvisioner)
+
+	// setup the cluster role binding for minikube's client certificate and addons
+	if s.RBACEnabled() {
+		rbacBootstrap := s.NewRBACBootstrapServer()
+		s.AddServer(rbacBootstrap)
+	}
+
+	return etcd
+}
+
+// setupProcess sets up the process-wide state of the Kubernetes components: the feature gates and
+// the capabilities of the kubelet.
+func setupProcess(s *localkube.LocalkubeServer) {
+	if s.FeatureGates != "" {
+		glog.Infof("Setting Feature Gates: %s", s.FeatureGates)
+		err := feature.DefaultFeatureGate.Set(s.FeatureGates)
+		if err != nil {
+			fmt.Printf("Error setting feature gates: %s", err)
+		}
+	}
+
+	// Setup capabilities. This can only be done once per binary.
+	allSources, _ := types.GetValidatedSources([]string{types.AllSource})
+	c := capabilities.Capabilities{
+		AllowPrivileged: true,
+		PrivilegedSources: capabilities.PrivilegedSources{
+			HostNetworkSources: allSources,
+			HostIPCSources:     allSources,
+			HostPIDSources:     allSources,
+		},
+	}
+	capabilities.Initialize(c)
+}
+
+// setupWorker creates the servers of a worker node, which connect to the joined apiserver.
+func setupWorker(s *localkube.LocalkubeServer) {
+	fmt.Printf("Joining apiserver %s as a worker\n", s.JoinAPIServer)
+	if err := s.WriteWorkerKubeconfig(); err != nil {
+		panic(err)
+	}
+
+	// setup kubelet
+	kubelet := s.NewKubeletServer()
+	s.AddServer(kubelet)
+
+	// setup proxy
+	proxy := s.NewProxyServer()
+	s.AddServer(proxy)
+}
+
+// setupEtcd starts the embedded etcd, restoring a snapshot into it or migrating its v2 data to
+// the v3 keyspace when needed.
+func setupEtcd(s *localkube.LocalkubeServer) *localkube.EtcdServer {
+	etcd, err := s.NewEtcd(localkube.KubeEtcdClientURLs, localkube.KubeEtcdPeerURLs, "kubeetcd", s.GetEtcdDataDirectory())
+	if err != nil {
+		panic(err)
+	}
+	restore, err := s.LoadEtcdRestore()
+	if err != nil {
+		fmt.Printf("Error loading etcd snapshot to restore: %s\n", err)
+		os.Exit(1)
+	}
+
+	// Start etcd first
+	etcd.Start()
+
+	if restore != nil {
+		err := etcd.RestoreSnapshot(restore)
+		if err != nil {
+			fmt.Printf("Error restoring etcd snapshot, keeping the current data: %s\n", err)
+			etcd.Stop()
+		}
+		if finishErr := s.FinishEtcdRestore(err); finishErr != nil {
+			fmt.Printf("Error cleaning up after restoring etcd snapshot: %s\n", finishErr)
+		}
+		if err != nil {
+			os.Exit(1)
+		}
+		fmt.Printf("Restored etcd snapshot taken at %s\n", restore.Time)
+	}
+
+	if s.StorageBackend == storagebackend.StorageTypeETCD3 {
+		n, err := etcd.MigrateV2Data(kubeapioptions.DefaultEtcdPathPrefix)
+		if err != nil {
+			fmt.Printf("Error migrating etcd v2 data to v3: %s\n", err)
+			os.Exit(1)
+		}
+		if n > 0 {
+			fmt.Printf("Migrated %d etcd v2 keys to the v3 keyspace\n", n)
+		}
+	}
+	return etcd
 }
@@ -0,0 +1,183 @@
+/*
+Copyright 2016 The Kubernetes Authors All rights reserved.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package cmd
+
+import (
+	"net/url"
+	"os"
+	"regexp"
+	"strconv"
+	"strings"
+	"text/template"
+
+	"github.com/golang/glog"
+	"github.com/spf13/cobra"
+	cmdUtil "k8s.io/minikube/cmd/util"
+	"k8s.io/minikube/pkg/minikube/cluster"
+	"k8s.io/minikube/pkg/minikube/constants"
+)
+
+var (
+	auditFormat    string
+	auditUser      string
+	auditVerb      string
+	auditResource  string
+	auditNamespace string
+)
+
+// auditFieldRegexp matches the key="value" fields of an audit log line.
+var auditFieldRegexp = regexp.MustCompile(`(\w+)="((?:[^"\\]|\\.)*)"`)
+
+// AuditEventTemplate holds the values available to the audit format.
+type AuditEventTemplate struct {
+	Time      string
+	ID        string
+	Stage     string
+	IP        string
+	Verb      string
+	User      string
+	Groups    string
+	Namespace string
+	Resource  string
+	URI       string
+	Response  string
+}
+
+// auditCmd represents the audit command
+var auditCmd = &cobra.Command{
+	Use:   "audit",
+	Short: "Prints the apiserver audit log",
+	Long: `Prints the requests recorded in the apiserver audit log, including rotated logs, oldest
+first. Auditing is enabled with minikube start --audit-log-path. The log is read from the VM, or
+from the host with the none driver. Requests can be filtered by user, verb, resource and namespace.`,
+	Run: func(cmd *cobra.Command, args []string) {
+		api := loadRunningAPI()
+		defer api.Close()
+
+		auditLog, err := cluster.GetAuditLog(api)
+		if err != nil {
+			glog.Errorln("Error getting audit log:", err)
+			cmdUtil.MaybeReportErrorAndExit(err)
+		}
+		tmpl, err := template.New("audit").Parse(auditFormat)
+		if err != nil {
+			glog.Errorln("Error creating audit template:", err)
+			os.Exit(1)
+		}
+		filter := AuditEventTemplate{User: auditUser, Verb: auditVerb, Resource: auditResource, Namespace: auditNamespace}
+		for _, e := range parseAuditLog(auditLog) {
+			if !matchesAuditFilter(e, filter) {
+				continue
+			}
+			if err := tmpl.Execute(os.Stdout, e); err != nil {
+				glog.Errorln("Error executing audit template:", err)
+				os.Exit(1)
+			}
+		}
+	},
+}
+
+// parseAuditLog returns the requests in an audit log. The RequestReceived stage of a request is
+// skipped, as the later stages hold the same information along with the response.
+func parseAuditLog(auditLog string) []AuditEventTemplate {
+	events := []AuditEventTemplate{}
+	for _, line := range strings.Split(auditLog, "\n") {
+		e, ok := parseAuditLine(line)
+		if !ok || e.Stage == "RequestReceived" {
+			continue
+		}
+		events = append(events, e)
+	}
+	return events
+}
+
+func parseAuditLine(line string) (AuditEventTemplate, bool) {
+	parts := strings.SplitN(line, " AUDIT: ", 2)
+	if len(parts) != 2 {
+		return AuditEventTemplate{}, false
+	}
+	fields := map[string]string{}
+	for _, m := range auditFieldRegexp.FindAllStringSubmatch(parts[1], -1) {
+		v, err := strconv.Unquote(`"` + m[2] + `"`)
+		if err != nil {
+			v = m[2]
+		}
+		fields[m[1]] = v
+	}
+	// The legacy audit log writes the response of a request on a separate line without a method
+	if fields["method"] == "" {
+		return AuditEventTemplate{}, false
+	}
+	return AuditEventTemplate{
+		Time:      parts[0],
+		ID:        fields["id"],
+		Stage:     fields["stage"],
+		IP:        fields["ip"],
+		Verb:      fields["method"],
+		User:      fields["user"],
+		Groups:    fields["groups"],
+		Namespace: fields["namespace"],
+		Resource:  getAuditResource(fields["uri"]),
+		URI:       fields["uri"],
+		Response:  fields["response"],
+	}, true
+}
+
+// getAuditResource returns the resource a request URI refers to, e.g. pods for
+// /api/v1/namespaces/default/pods/nginx, or <none> for non-resource URIs like /healthz.
+func getAuditResource(uri string) string {
+	u, err := url.Parse(uri)
+	if err != nil {
+		return "<none>"
+	}
+	segments := strings.Split(strings.Trim(u.Path, "/"), "/")
+	switch {
+	case len(segments) >= 3 && segments[0] == "api":
+		segments = segments[2:]
+	case len(segments) >= 4 && segments[0] == "apis":
+		segments = segments[3:]
+	default:
+		return "<none>"
+	}
+	if segments[0] == "watch" && len(segments) > 1 {
+		segments = segments[1:]
+	}
+	if segments[0] == "namespaces" && len(segments) > 2 {
+		return segments[2]
+	}
+	return segments[0]
+}
+
+// matchesAuditFilter returns whether e matches every field set in filter. Verbs are compared
+// case-insensitively, as the legacy audit log records HTTP methods.
+func matchesAuditFilter(e, filter AuditEventTemplate) bool {
+	return (filter.User == "" || e.User == filter.User) &&
+		(filter.Verb == "" || strings.EqualFold(e.Verb, filter.Verb)) &&
+		(filter.Resource == "" || e.Resource == filter.Resource) &&
+		(filter.Namespace == "" || e.Namespace == filter.Namespace)
+}
+
+func init() {
+	auditCmd.Flags().StringVar(&auditFormat, "format", constants.DefaultAuditFormat,
+		`Go template format string for the audit output.  The format for Go templates can be found here: https://golang.org/pkg/text/template/
+For the list of accessible variables for the template, see the struct values here: https://godoc.org/k8s.io/minikube/cmd/minikube/cmd#AuditEventTemplate`)
+	auditCmd.Flags().StringVar(&auditUser, "user", "", "Only print requests made by this user")
+	auditCmd.Flags().StringVar(&auditVerb, "verb", "", "Only print requests with this verb, e.g. create")
+	auditCmd.Flags().StringVar(&auditResource, "resource", "", "Only print requests for this resource, e.g. pods")
+	auditCmd.Flags().StringVar(&auditNamespace, "namespace", "", "Only print requests in this namespace")
+	RootCmd.AddCommand(auditCmd)
+}
@@ -0,0 +1,111 @@
+/*
+Copyright 2016 The Kubernetes Authors All rights reserved.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package cmd
+
+import (
+	"testing"
+)
+
+const testAuditLog = `2017-06-01T10:00:00.1Z AUDIT: id="1" stage="RequestReceived" ip="127.0.0.1" method="create" user="minikube" groups="\"system:masters\",\"system:authenticated\"" as="<self>" asgroups="<lookup>" namespace="default" uri="/api/v1/namespaces/default/pods" response="<deferred>"
+2017-06-01T10:00:00.2Z AUDIT: id="1" stage="ResponseComplete" ip="127.0.0.1" method="create" user="minikube" groups="\"system:masters\",\"system:authenticated\"" as="<self>" asgroups="<lookup>" namespace="default" uri="/api/v1/namespaces/default/pods" response="201"
+2017-06-01T10:00:01Z AUDIT: id="2" stage="ResponseComplete" ip="127.0.0.1" method="update" user="<none>" groups="<none>" as="<self>" asgroups="<lookup>" namespace="kube-system" uri="/apis/extensions/v1beta1/namespaces/kube-system/deployments/kube-dns/status" response="200"
+2017-06-01T10:00:02Z AUDIT: id="3" ip="127.0.0.1" method="GET" user="minikube" groups="\"system:masters\"" as="<self>" asgroups="<lookup>" namespace="<none>" uri="/api/v1/nodes?watch=true"
+2017-06-01T10:00:02Z AUDIT: id="3" response="200"
+`
+
+func TestParseAuditLog(t *testing.T) {
+	events := parseAuditLog(testAuditLog)
+	if len(events) != 3 {
+		t.Fatalf("Expected 3 events, got %d: %+v", len(events), events)
+	}
+
+	expected := AuditEventTemplate{
+		Time:      "2017-06-01T10:00:00.2Z",
+		ID:        "1",
+		Stage:     "ResponseComplete",
+		IP:        "127.0.0.1",
+		Verb:      "create",
+		User:      "minikube",
+		Groups:    `"system:masters","system:authenticated"`,
+		Namespace: "default",
+		Resource:  "pods",
+		URI:       "/api/v1/namespaces/default/pods",
+		Response:  "201",
+	}
+	if events[0] != expected {
+		t.Errorf("Expected %+v, got %+v", expected, events[0])
+	}
+	if events[1].Resource != "deployments" {
+		t.Errorf("Expected resource deployments, got %s", events[1].Resource)
+	}
+	if events[2].Resource != "nodes" || events[2].Verb != "GET" {
+		t.Errorf("Unexpected legacy event %+v", events[2])
+	}
+}
+
+func TestGetAuditResource(t *testing.T) {
+	var tests = []struct {
+		uri      string
+		resource string
+	}{
+		{"/api/v1/pods", "pods"},
+		{"/api/v1/namespaces", "namespaces"},
+		{"/api/v1/namespaces/default", "namespaces"},
+		{"/api/v1/namespaces/default/services/kubernetes", "services"},
+		{"/api/v1/watch/namespaces/default/pods?resourceVersion=1", "pods"},
+		{"/apis/rbac.authorization.k8s.io/v1beta1/clusterrolebindings/minikube-rbac", "clusterrolebindings"},
+		{"/healthz", "<none>"},
+		{"/apis/extensions", "<none>"},
+	}
+
+	for _, test := range tests {
+		if resource := getAuditResource(test.uri); resource != test.resource {
+			t.Errorf("Expected resource %s for %s, got %s", test.resource, test.uri, resource)
+		}
+	}
+}
+
+func TestMatchesAuditFilter(t *testing.T) {
+	events := parseAuditLog(testAuditLog)
+
+	var tests = []struct {
+		description string
+		filter      AuditEventTemplate
+		matches     int
+	}{
+		{"no filter", AuditEventTemplate{}, 3},
+		{"user", AuditEventTemplate{User: "minikube"}, 2},
+		{"verb", AuditEventTemplate{Verb: "get"}, 1},
+		{"resource", AuditEventTemplate{Resource: "pods"}, 1},
+		{"namespace", AuditEventTemplate{Namespace: "kube-system"}, 1},
+		{"user and verb", AuditEventTemplate{User: "minikube", Verb: "update"}, 0},
+	}
+
+	for _, test := range tests {
+		t.Run(test.description, func(t *testing.T) {
+			matches := 0
+			for _, e := range events {
+				if matchesAuditFilter(e, test.filter) {
+					matches++
+				}
+			}
+			if matches != test.matches {
+				t.Errorf("Expected %d matches, got %d", test.matches, matches)
+			}
+		})
+	}
+}
@@ -0,0 +1,108 @@
+/*
+Copyright 2016 The Kubernetes Authors All rights reserved.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package cmd
+
+import (
+	"fmt"
+	"net"
+	"os"
+	"path/filepath"
+	"sort"
+	"strconv"
+	"time"
+
+	"github.com/golang/glog"
+	"github.com/spf13/cobra"
+	cmdUtil "k8s.io/minikube/cmd/util"
+	"k8s.io/minikube/pkg/minikube/cluster"
+	"k8s.io/minikube/pkg/minikube/config"
+	"k8s.io/minikube/pkg/minikube/constants"
+	pkgutil "k8s.io/minikube/pkg/util"
+	"k8s.io/minikube/pkg/util/kubeconfig"
+)
+
+var certsRotateCA bool
+
+// certsCmd represents the certs command
+var certsCmd = &cobra.Command{
+	Use:   "certs",
+	Short: "Manages the certificates of the cluster",
+	Long:  `Manages the CA and apiserver certificates in $MINIKUBE_HOME, which the VM and the kubeconfig use.`,
+	Run: func(cmd *cobra.Command, args []string) {
+		cmd.Help()
+	},
+}
+
+var certsRotateCmd = &cobra.Command{
+	Use:   "rotate",
+	Short: "Renews the apiserver and client certificate, and optionally the CA",
+	Long: `Renews the apiserver certificate, which is also the client certificate of the kubeconfig, for the
+same IPs and names. With --ca the CA is replaced first, after which pods that talk to the apiserver
+need to be restarted to trust it. The certificates are copied into the VM, localkube restarts to load
+them and the kubeconfig is updated.`,
+	Run: func(cmd *cobra.Command, args []string) {
+		api := loadUnpausedAPI()
+		defer api.Close()
+
+		if err := cluster.RotateCerts(api, certsRotateCA); err != nil {
+			glog.Errorln("Error rotating certificates:", err)
+			cmdUtil.MaybeReportErrorAndExit(err)
+		}
+
+		ip, err := cluster.GetHostDriverIP(api)
+		if err != nil {
+			glog.Errorln("Error getting VM IP address:", err)
+			cmdUtil.MaybeReportErrorAndExit(err)
+		}
+		kubeCfgSetup := &kubeconfig.KubeConfigSetup{
+			ClusterName:          config.GetMachineName(),
+			ClusterServerAddress: "https://" + net.JoinHostPort(ip.String(), strconv.Itoa(pkgutil.APIServerPort)),
+			ClientCertificate:    constants.MakeMiniPath("apiserver.crt"),
+			ClientKey:            constants.MakeMiniPath("apiserver.key"),
+			CertificateAuthority: constants.MakeMiniPath("ca.crt"),
+			KeepContext:          true,
+		}
+		kubeCfgSetup.SetKubeConfigFile(cmdUtil.GetKubeConfigPath())
+		if err := kubeconfig.SetupKubeConfig(kubeCfgSetup); err != nil {
+			glog.Errorln("Error setting up kubeconfig:", err)
+			cmdUtil.MaybeReportErrorAndExit(err)
+		}
+		fmt.Println("Rotated certificates. Localkube is restarting, use minikube status to follow its progress.")
+	},
+}
+
+// warnExpiringCerts prints a warning for each certificate that expires soon, by path.
+func warnExpiringCerts(expiring map[string]time.Time) {
+	paths := []string{}
+	for p := range expiring {
+		paths = append(paths, p)
+	}
+	sort.Strings(paths)
+	for _, p := range paths {
+		rotate := "minikube certs rotate"
+		if filepath.Base(p) == "ca.crt" {
+			rotate += " --ca"
+		}
+		fmt.Fprintf(os.Stderr, "WARNING: The certificate %s expires on %s. Run %s to renew it.\n", p, expiring[p].Format(time.RFC1123), rotate)
+	}
+}
+
+func init() {
+	certsRotateCmd.Flags().BoolVar(&certsRotateCA, "ca", false, "Also replace the CA, which invalidates every certificate it signed")
+	certsCmd.AddCommand(certsRotateCmd)
+	RootCmd.AddCommand(certsCmd)
+}
@@ -84,6 +84,30 @@ var settings = []Setting{
 		name: "kubernetes-version",
 		set:  SetString,
 	},
+	{
+		name:        "pod-network-cidr",
+		set:         SetString,
+		validations: []setFn{IsValidCIDR},
+		callbacks:   []setFn{RequiresRestartMsg},
+	},
+	{
+		name:        "service-cluster-ip-range",
+		set:         SetString,
+		validations: []setFn{IsValidCIDR},
+		callbacks:   []setFn{RequiresRestartMsg},
+	},
+	{
+		name:        "dns-ip",
+		set:         SetString,
+		validations: []setFn{IsValidIP},
+		callbacks:   []setFn{RequiresRestartMsg},
+	},
+	{
+		name:        "apiserver-extra-sans",
+		set:         SetString,
+		validations: []setFn{IsValidSANs},
+		callbacks:   []setFn{RequiresRestartMsg},
+	},
 	{
 		name:        "iso-url",
 		set:         SetString,
@@ -135,7 +159,7 @@ var settings = []Setting{
 		name:        "kube-dns",
 		set:         SetBool,
 		validations: []setFn{IsValidAddon},
-		callbacks:   []setFn{EnableOrDisableAddon},
+		callbacks:   []setFn{EnableOrDisableKubeDNS},
 	},
 	{
 		name:        "heapster",
@@ -167,6 +191,18 @@ var settings = []Setting{
 		validations: []setFn{IsValidAddon},
 		callbacks:   []setFn{EnableOrDisableDefaultStorageClass},
 	},
+	{
+		name:        constants.CustomStorageClassesAddon,
+		set:         SetBool,
+		validations: []setFn{IsValidAddon},
+		callbacks:   []setFn{EnableOrDisableCustomStorageClasses},
+	},
+	{
+		name:        constants.StorageClassesConfig,
+		set:         SetString,
+		validations: []setFn{IsValidStorageClasses},
+		callbacks:   []setFn{RequiresStartMsg},
+	},
 	{
 		name: "hyperv-virtual-switch",
 		set:  SetString,
@@ -26,10 +26,13 @@ import (
 	"github.com/pkg/errors"
 	"k8s.io/minikube/pkg/minikube/assets"
 	"k8s.io/minikube/pkg/minikube/cluster"
+	"k8s.io/minikube/pkg/minikube/clusterspec"
 	"k8s.io/minikube/pkg/minikube/config"
+	"k8s.io/minikube/pkg/minikube/constants"
 	"k8s.io/minikube/pkg/minikube/machine"
 	"k8s.io/minikube/pkg/minikube/sshutil"
 	"k8s.io/minikube/pkg/minikube/storageclass"
+	"k8s.io/minikube/pkg/util"
 )
 
 // Runs all the validation or callback functions and collects errors
@@ -175,6 +178,54 @@ func EnableOrDisableDefaultStorageClass(name, val string) error {
 	return EnableOrDisableAddon(name, val)
 }
 
+// EnableOrDisableCustomStorageClasses installs or removes the storage classes declared in the
+// storage-classes setting.
+func EnableOrDisableCustomStorageClasses(name, val string) error {
+	enable, err := strconv.ParseBool(val)
+	if err != nil {
+		return errors.Wrap(err, "Error parsing boolean")
+	}
+
+	// The setting is not in the config file when no storage classes were declared
+	spec, _ := config.Get(constants.StorageClassesConfig)
+	classes, err := storageclass.ParseStorageClasses(spec)
+	if err != nil {
+		return errors.Wrapf(err, "Error parsing %s", constants.StorageClassesConfig)
+	}
+	if enable && len(classes) == 0 {
+		return errors.Errorf("No storage classes are declared, set them with minikube config set %s", constants.StorageClassesConfig)
+	}
+	if err := storageclass.LoadAddon(classes); err != nil {
+		return errors.Wrap(err, "Error loading storage classes")
+	}
+
+	// The addon-manager and kubectl apply cannot delete storageclasses
+	if !enable {
+		if err := storageclass.DeleteStorageClasses(classes); err != nil {
+			return errors.Wrap(err, "Error deleting storage classes")
+		}
+	}
+	return EnableOrDisableAddon(name, val)
+}
+
+// EnableOrDisableKubeDNS renders the kube-dns service with the DNS IP the cluster was last started
+// with before enabling or disabling the kube-dns addon.
+func EnableOrDisableKubeDNS(name, val string) error {
+	spec, err := clusterspec.Load(config.GetMachineName())
+	if err != nil {
+		return errors.Wrap(err, "Error loading the saved cluster config")
+	}
+	// No spec is saved until the cluster is started
+	dnsIP := util.DefaultDNSIP
+	if spec != nil && spec.Kubernetes.DNSIP != "" {
+		dnsIP = spec.Kubernetes.DNSIP
+	}
+	if err := assets.LoadKubeDNSAddon(dnsIP); err != nil {
+		return errors.Wrap(err, "Error loading kube-dns addon")
+	}
+	return EnableOrDisableAddon(name, val)
+}
+
 func transferAddon(addon *assets.Addon, d drivers.Driver) error {
 	if d.DriverName() == "none" {
 		if err := transferAddonLocal(addon, d); err != nil {
@@ -22,11 +22,14 @@ import (
 	"net/url"
 	"os"
 	"strconv"
+	"strings"
 
 	units "github.com/docker/go-units"
 	"github.com/pkg/errors"
 	"k8s.io/minikube/pkg/minikube/assets"
 	"k8s.io/minikube/pkg/minikube/constants"
+	"k8s.io/minikube/pkg/minikube/storageclass"
+	"k8s.io/minikube/pkg/util"
 )
 
 func IsValidDriver(string, driver string) error {
@@ -43,6 +46,11 @@ func RequiresRestartMsg(string, string) error {
 	return nil
 }
 
+func RequiresStartMsg(string, string) error {
+	fmt.Fprintln(os.Stdout, "These changes will take effect upon a minikube start")
+	return nil
+}
+
 func IsValidDiskSize(name string, disksize string) error {
 	_, err := units.FromHumanSize(disksize)
 	if err != nil {
@@ -78,6 +86,18 @@ func IsValidCIDR(name string, cidr string) error {
 	return nil
 }
 
+func IsValidIP(name string, ip string) error {
+	if net.ParseIP(ip) == nil {
+		return fmt.Errorf("Error parsing IP: %s", ip)
+	}
+	return nil
+}
+
+func IsValidSANs(name string, sans string) error {
+	_, _, err := util.ParseSANs(strings.Split(sans, ","))
+	return err
+}
+
 func IsValidPath(name string, path string) error {
 	_, err := os.Stat(path)
 	if err != nil {
@@ -92,3 +112,8 @@ func IsValidAddon(name string, val string) error {
 	}
 	return errors.Errorf("Cannot enable/disable invalid addon %s", name)
 }
+
+func IsValidStorageClasses(name string, spec string) error {
+	_, err := storageclass.ParseStorageClasses(spec)
+	return err
+}
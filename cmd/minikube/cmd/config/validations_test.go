@@ -94,3 +94,68 @@ func TestValidCIDR(t *testing.T) {
 
 	runValidations(t, tests, "cidr", IsValidCIDR)
 }
+
+func TestValidIP(t *testing.T) {
+	var tests = []validationTest{
+		{
+			value:     "10.0.0.10",
+			shouldErr: false,
+		},
+		{
+			value:     "10.0.0.0/24",
+			shouldErr: true,
+		},
+		{
+			value:     "dns",
+			shouldErr: true,
+		},
+	}
+
+	runValidations(t, tests, "dns-ip", IsValidIP)
+}
+
+func TestValidSANs(t *testing.T) {
+	var tests = []validationTest{
+		{
+			value:     "localhost,127.0.0.1,minikube.example.com",
+			shouldErr: false,
+		},
+		{
+			value:     "",
+			shouldErr: false,
+		},
+		{
+			value:     "localhost,10.0.0.0/24",
+			shouldErr: true,
+		},
+	}
+
+	runValidations(t, tests, "apiserver-extra-sans", IsValidSANs)
+}
+
+func TestValidStorageClasses(t *testing.T) {
+	var tests = []validationTest{
+		{
+			value:     "fast:dir=/data/fast,reclaimPolicy=Retain,default=true;slow",
+			shouldErr: false,
+		},
+		{
+			value:     "",
+			shouldErr: false,
+		},
+		{
+			value:     "standard",
+			shouldErr: true,
+		},
+		{
+			value:     "fast:dir=data/fast",
+			shouldErr: true,
+		},
+		{
+			value:     "fast:default=true;slow:default=true",
+			shouldErr: true,
+		},
+	}
+
+	runValidations(t, tests, "storage-classes", IsValidStorageClasses)
+}
@@ -23,6 +23,8 @@ import (
 	"github.com/spf13/cobra"
 	cmdUtil "k8s.io/minikube/cmd/util"
 	"k8s.io/minikube/pkg/minikube/cluster"
+	"k8s.io/minikube/pkg/minikube/clusterspec"
+	"k8s.io/minikube/pkg/minikube/config"
 	"k8s.io/minikube/pkg/minikube/machine"
 )
 
@@ -46,12 +48,26 @@ associated files.`,
 		}
 		defer api.Close()
 
+		nodes, err := cluster.GetNodes()
+		if err != nil {
+			fmt.Println("Errors occurred getting nodes: ", err)
+		}
+		for _, n := range nodes {
+			if err := cluster.RemoveNode(api, n.Name); err != nil {
+				fmt.Printf("Errors occurred deleting node %s: %s\n", n.Name, err)
+			}
+		}
+
 		if err = cluster.DeleteHost(api); err != nil {
 			fmt.Println("Errors occurred deleting machine: ", err)
 			os.Exit(1)
 		}
 		fmt.Println("Machine deleted.")
 
+		if err := clusterspec.Delete(config.GetMachineName()); err != nil {
+			fmt.Println("Errors occurred deleting the saved cluster config: ", err)
+		}
+
 		if err := cmdUtil.KillMountProcess(); err != nil {
 			fmt.Println("Errors occurred deleting mount process: ", err)
 		}
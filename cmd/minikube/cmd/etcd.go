@@ -0,0 +1,279 @@
+/*
+Copyright 2016 The Kubernetes Authors All rights reserved.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package cmd
+
+import (
+	"fmt"
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"sort"
+	"strings"
+	"text/template"
+	"time"
+
+	units "github.com/docker/go-units"
+	"github.com/docker/machine/libmachine"
+	"github.com/docker/machine/libmachine/state"
+	"github.com/golang/glog"
+	"github.com/pkg/errors"
+	"github.com/spf13/cobra"
+	cmdUtil "k8s.io/minikube/cmd/util"
+	"k8s.io/minikube/pkg/minikube/cluster"
+	"k8s.io/minikube/pkg/minikube/config"
+	"k8s.io/minikube/pkg/minikube/constants"
+	"k8s.io/minikube/pkg/minikube/machine"
+)
+
+const (
+	snapshotExtension  = ".json"
+	snapshotNameFormat = "20060102-150405"
+)
+
+var snapshotListFormat string
+
+// SnapshotListTemplate holds the values available to the snapshot list format.
+type SnapshotListTemplate struct {
+	Name string
+	Time string
+	Size string
+}
+
+// etcdCmd represents the etcd command
+var etcdCmd = &cobra.Command{
+	Use:   "etcd",
+	Short: "Manages the etcd that stores the cluster state",
+	Long:  `Manages the etcd embedded in localkube, which stores the state of the cluster.`,
+	Run: func(cmd *cobra.Command, args []string) {
+		cmd.Help()
+	},
+}
+
+var etcdSnapshotCmd = &cobra.Command{
+	Use:   "snapshot",
+	Short: "Saves, lists and restores snapshots of the cluster state",
+	Long: `Saves, lists and restores snapshots of the cluster state stored in etcd.
+Snapshots are kept on the host in $MINIKUBE_HOME/snapshots/<profile>.`,
+	Run: func(cmd *cobra.Command, args []string) {
+		cmd.Help()
+	},
+}
+
+var etcdSnapshotSaveCmd = &cobra.Command{
+	Use:   "save [NAME]",
+	Short: "Saves a snapshot of the cluster state",
+	Long:  `Saves a snapshot of the cluster state. Without a name the snapshot is named after the current time.`,
+	Run: func(cmd *cobra.Command, args []string) {
+		if len(args) > 1 {
+			fmt.Fprintln(os.Stderr, "usage: minikube etcd snapshot save [NAME]")
+			os.Exit(1)
+		}
+		name := time.Now().Format(snapshotNameFormat)
+		if len(args) == 1 {
+			name = args[0]
+		}
+		path, err := getSnapshotPath(name)
+		if err != nil {
+			fmt.Fprintln(os.Stderr, err)
+			os.Exit(1)
+		}
+
+		api := loadUnpausedAPI()
+		defer api.Close()
+
+		snapshot, err := cluster.GetEtcdSnapshot(api)
+		if err != nil {
+			glog.Errorln("Error getting etcd snapshot:", err)
+			cmdUtil.MaybeReportErrorAndExit(err)
+		}
+		if err := os.MkdirAll(filepath.Dir(path), 0700); err != nil {
+			glog.Errorln("Error creating snapshot directory:", err)
+			os.Exit(1)
+		}
+		if err := ioutil.WriteFile(path, snapshot, 0600); err != nil {
+			glog.Errorln("Error writing snapshot:", err)
+			os.Exit(1)
+		}
+		fmt.Printf("Saved snapshot %s to %s\n", name, path)
+	},
+}
+
+var etcdSnapshotListCmd = &cobra.Command{
+	Use:   "list",
+	Short: "Lists the saved snapshots of the cluster state",
+	Long:  `Lists the saved snapshots of the cluster state, oldest first.`,
+	Run: func(cmd *cobra.Command, args []string) {
+		snapshots, err := listSnapshots(getSnapshotDir())
+		if err != nil {
+			glog.Errorln("Error listing snapshots:", err)
+			os.Exit(1)
+		}
+		tmpl, err := template.New("list").Parse(snapshotListFormat)
+		if err != nil {
+			glog.Errorln("Error creating list template:", err)
+			os.Exit(1)
+		}
+		for _, s := range snapshots {
+			if err := tmpl.Execute(os.Stdout, s); err != nil {
+				glog.Errorln("Error executing list template:", err)
+				os.Exit(1)
+			}
+		}
+	},
+}
+
+var etcdSnapshotRestoreCmd = &cobra.Command{
+	Use:   "restore NAME",
+	Short: "Restores a snapshot of the cluster state",
+	Long: `Restores a snapshot of the cluster state. Localkube stops the cluster components and etcd,
+replaces the etcd data with the snapshot, and then starts the components again. The etcd data
+that was replaced is kept in the VM until the next restore. When the cluster is stopped, the
+snapshot is restored the next time it is started, before any component runs.`,
+	Run: func(cmd *cobra.Command, args []string) {
+		if len(args) != 1 {
+			fmt.Fprintln(os.Stderr, "usage: minikube etcd snapshot restore NAME")
+			os.Exit(1)
+		}
+		path, err := getSnapshotPath(args[0])
+		if err != nil {
+			fmt.Fprintln(os.Stderr, err)
+			os.Exit(1)
+		}
+		if _, err := os.Stat(path); err != nil {
+			fmt.Fprintf(os.Stderr, "Snapshot %s not found, see minikube etcd snapshot list\n", args[0])
+			os.Exit(1)
+		}
+
+		api, err := machine.NewAPIClient()
+		if err != nil {
+			fmt.Fprintf(os.Stderr, "Error getting client: %s\n", err)
+			os.Exit(1)
+		}
+		defer api.Close()
+
+		ms, err := cluster.GetHostStatus(api)
+		if err != nil {
+			glog.Errorln("Error getting machine status:", err)
+			cmdUtil.MaybeReportErrorAndExit(err)
+		}
+		switch ms {
+		case state.None.String():
+			fmt.Fprintln(os.Stderr, "The cluster doesn't exist, start it with minikube start first")
+			os.Exit(1)
+		case state.Running.String():
+			if err := cluster.RestoreEtcdSnapshot(api, path); err != nil {
+				glog.Errorln("Error restoring etcd snapshot:", err)
+				cmdUtil.MaybeReportErrorAndExit(err)
+			}
+			fmt.Printf("Restoring snapshot %s. Localkube is restarting, use minikube status to follow its progress.\n", args[0])
+		default:
+			if err := cluster.StageEtcdRestore(path); err != nil {
+				glog.Errorln("Error staging etcd snapshot:", err)
+				cmdUtil.MaybeReportErrorAndExit(err)
+			}
+			fmt.Printf("The cluster is stopped, snapshot %s will be restored the next time it is started with minikube start.\n", args[0])
+		}
+	},
+}
+
+// loadRunningAPI returns a client for the minikube VM, exiting if the VM is not running.
+func loadRunningAPI() libmachine.API {
+	api, err := machine.NewAPIClient()
+	if err != nil {
+		fmt.Fprintf(os.Stderr, "Error getting client: %s\n", err)
+		os.Exit(1)
+	}
+	s, err := cluster.GetHostStatus(api)
+	if err != nil {
+		glog.Errorln("Error getting machine status:", err)
+		cmdUtil.MaybeReportErrorAndExit(err)
+	}
+	if s != state.Running.String() {
+		fmt.Fprintln(os.Stderr, "minikube is not currently running")
+		os.Exit(1)
+	}
+	return api
+}
+
+// loadUnpausedAPI is loadRunningAPI for the commands that need localkube to respond, which a
+// paused localkube doesn't. It exits if the cluster is paused.
+func loadUnpausedAPI() libmachine.API {
+	api := loadRunningAPI()
+	s, err := cluster.GetLocalkubeStatus(api)
+	if err != nil {
+		glog.Errorln("Error getting localkube status:", err)
+		cmdUtil.MaybeReportErrorAndExit(err)
+	}
+	if s == constants.LocalkubePaused {
+		fmt.Fprintln(os.Stderr, "The cluster is paused. Run minikube unpause to resume it first.")
+		os.Exit(1)
+	}
+	return api
+}
+
+func getSnapshotDir() string {
+	return constants.MakeMiniPath("snapshots", config.GetMachineName())
+}
+
+func getSnapshotPath(name string) (string, error) {
+	if name == "" || strings.ContainsAny(name, `/\`) || strings.HasPrefix(name, ".") {
+		return "", errors.Errorf("Invalid snapshot name %q", name)
+	}
+	return filepath.Join(getSnapshotDir(), name+snapshotExtension), nil
+}
+
+// listSnapshots returns the snapshots in dir, oldest first.
+func listSnapshots(dir string) ([]SnapshotListTemplate, error) {
+	files, err := ioutil.ReadDir(dir)
+	if os.IsNotExist(err) {
+		return nil, nil
+	}
+	if err != nil {
+		return nil, err
+	}
+
+	snapshots := []os.FileInfo{}
+	for _, f := range files {
+		if !f.IsDir() && filepath.Ext(f.Name()) == snapshotExtension {
+			snapshots = append(snapshots, f)
+		}
+	}
+	sort.Slice(snapshots, func(i, j int) bool {
+		return snapshots[i].ModTime().Before(snapshots[j].ModTime())
+	})
+
+	list := []SnapshotListTemplate{}
+	for _, f := range snapshots {
+		list = append(list, SnapshotListTemplate{
+			Name: strings.TrimSuffix(f.Name(), snapshotExtension),
+			Time: f.ModTime().Format(time.RFC1123),
+			Size: units.HumanSize(float64(f.Size())),
+		})
+	}
+	return list, nil
+}
+
+func init() {
+	etcdSnapshotListCmd.Flags().StringVar(&snapshotListFormat, "format", constants.DefaultSnapshotListFormat,
+		`Go template format string for the snapshot list output.  The format for Go templates can be found here: https://golang.org/pkg/text/template/
+For the list of accessible variables for the template, see the struct values here: https://godoc.org/k8s.io/minikube/cmd/minikube/cmd#SnapshotListTemplate`)
+	etcdSnapshotCmd.AddCommand(etcdSnapshotSaveCmd)
+	etcdSnapshotCmd.AddCommand(etcdSnapshotListCmd)
+	etcdSnapshotCmd.AddCommand(etcdSnapshotRestoreCmd)
+	etcdCmd.AddCommand(etcdSnapshotCmd)
+	RootCmd.AddCommand(etcdCmd)
+}
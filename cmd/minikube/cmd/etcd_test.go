@@ -0,0 +1,82 @@
+/*
+Copyright 2016 The Kubernetes Authors All rights reserved.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package cmd
+
+import (
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"testing"
+	"time"
+)
+
+func TestListSnapshots(t *testing.T) {
+	dir, err := ioutil.TempDir("", "snapshots")
+	if err != nil {
+		t.Fatalf("Error creating temp dir: %s", err)
+	}
+	defer os.RemoveAll(dir)
+
+	if snapshots, err := listSnapshots(filepath.Join(dir, "missing")); err != nil || len(snapshots) != 0 {
+		t.Fatalf("Expected no snapshots in a missing directory, got %v and error %v", snapshots, err)
+	}
+
+	now := time.Now()
+	files := []struct {
+		name string
+		time time.Time
+	}{
+		{"newer.json", now},
+		{"older.json", now.Add(-time.Hour)},
+		{"notes.txt", now},
+	}
+	for _, f := range files {
+		path := filepath.Join(dir, f.name)
+		if err := ioutil.WriteFile(path, []byte("{}"), 0600); err != nil {
+			t.Fatalf("Error writing %s: %s", f.name, err)
+		}
+		if err := os.Chtimes(path, f.time, f.time); err != nil {
+			t.Fatalf("Error setting time of %s: %s", f.name, err)
+		}
+	}
+
+	snapshots, err := listSnapshots(dir)
+	if err != nil {
+		t.Fatalf("Error listing snapshots: %s", err)
+	}
+	if len(snapshots) != 2 || snapshots[0].Name != "older" || snapshots[1].Name != "newer" {
+		t.Fatalf("Expected snapshots older and newer, got %+v", snapshots)
+	}
+	if snapshots[0].Size != "2 B" {
+		t.Errorf("Expected a size of 2 B, got %s", snapshots[0].Size)
+	}
+}
+
+func TestGetSnapshotPath(t *testing.T) {
+	for _, name := range []string{"", "../etc", `a\b`, ".hidden"} {
+		if _, err := getSnapshotPath(name); err == nil {
+			t.Errorf("Expected an error for snapshot name %q", name)
+		}
+	}
+	path, err := getSnapshotPath("seeded")
+	if err != nil {
+		t.Fatalf("Error getting snapshot path: %s", err)
+	}
+	if filepath.Base(path) != "seeded.json" {
+		t.Errorf("Expected the snapshot to be stored in seeded.json, got %s", path)
+	}
+}
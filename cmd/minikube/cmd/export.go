@@ -0,0 +1,59 @@
+/*
+Copyright 2016 The Kubernetes Authors All rights reserved.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package cmd
+
+import (
+	"fmt"
+	"os"
+
+	"github.com/golang/glog"
+	"github.com/spf13/cobra"
+	"k8s.io/minikube/pkg/minikube/clusterspec"
+	"k8s.io/minikube/pkg/minikube/config"
+)
+
+var exportFormat string
+
+// exportCmd represents the export command
+var exportCmd = &cobra.Command{
+	Use:   "export",
+	Short: "Writes the cluster spec of the current profile",
+	Long: `Writes the spec of the cluster of the current profile, as it was last started, to stdout.
+The spec can be committed to a repository and used to create the same cluster with minikube start --from-file.`,
+	Run: func(cmd *cobra.Command, args []string) {
+		spec, err := clusterspec.Load(config.GetMachineName())
+		if err != nil {
+			glog.Errorln("Error loading cluster spec:", err)
+			os.Exit(1)
+		}
+		if spec == nil {
+			fmt.Fprintf(os.Stderr, "No cluster spec is saved for %s, it is saved each time minikube start runs\n", config.GetMachineName())
+			os.Exit(1)
+		}
+		data, err := spec.Encode(exportFormat)
+		if err != nil {
+			glog.Errorln("Error encoding cluster spec:", err)
+			os.Exit(1)
+		}
+		fmt.Print(string(data))
+	},
+}
+
+func init() {
+	exportCmd.Flags().StringVarP(&exportFormat, "output", "o", "yaml", "The format of the spec, yaml or json")
+	RootCmd.AddCommand(exportCmd)
+}
@@ -0,0 +1,81 @@
+/*
+Copyright 2016 The Kubernetes Authors All rights reserved.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package cmd
+
+import (
+	"fmt"
+	"io"
+	"os"
+	"text/tabwriter"
+
+	"github.com/golang/glog"
+	"github.com/spf13/cobra"
+	"k8s.io/minikube/pkg/minikube/constants"
+	"k8s.io/minikube/pkg/minikube/extraconfig"
+)
+
+// extraConfigCmd represents the extra-config command
+var extraConfigCmd = &cobra.Command{
+	Use:   "extra-config",
+	Short: "Describes the keys minikube start --extra-config can set",
+	Long:  `Describes the keys of the component configurations that minikube start --extra-config can set.`,
+	Run: func(cmd *cobra.Command, args []string) {
+		cmd.Help()
+	},
+}
+
+var extraConfigListCmd = &cobra.Command{
+	Use:   "list [component]",
+	Short: "Lists the keys --extra-config can set, and their types",
+	Long: fmt.Sprintf(`Lists the keys --extra-config can set on the component, or on every component, and their types.
+The keys are the dotted paths of the fields of the component configurations of Kubernetes %s.`, constants.DefaultKubernetesVersion),
+	Run: func(cmd *cobra.Command, args []string) {
+		if len(args) > 1 {
+			fmt.Fprintln(os.Stderr, "usage: minikube extra-config list [component]")
+			os.Exit(1)
+		}
+		components := extraconfig.Components()
+		if len(args) == 1 {
+			components = args
+		}
+		if err := printExtraConfigFields(os.Stdout, components); err != nil {
+			glog.Errorln("Error listing the extra config keys:", err)
+			os.Exit(1)
+		}
+	},
+}
+
+// printExtraConfigFields writes a table of the keys of the components and their types.
+func printExtraConfigFields(out io.Writer, components []string) error {
+	w := tabwriter.NewWriter(out, 0, 8, 2, ' ', 0)
+	fmt.Fprintln(w, "KEY\tTYPE")
+	for _, c := range components {
+		fields, err := extraconfig.ListFields(c)
+		if err != nil {
+			return err
+		}
+		for _, f := range fields {
+			fmt.Fprintf(w, "%s.%s\t%s\n", c, f.Path, f.Type)
+		}
+	}
+	return w.Flush()
+}
+
+func init() {
+	extraConfigCmd.AddCommand(extraConfigListCmd)
+	RootCmd.AddCommand(extraConfigCmd)
+}
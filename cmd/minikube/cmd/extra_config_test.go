@@ -0,0 +1,39 @@
+/*
+Copyright 2016 The Kubernetes Authors All rights reserved.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package cmd
+
+import (
+	"bytes"
+	"regexp"
+	"testing"
+)
+
+func TestPrintExtraConfigFields(t *testing.T) {
+	var buf bytes.Buffer
+	if err := printExtraConfigFields(&buf, []string{"kubelet", "proxy"}); err != nil {
+		t.Fatalf("Error printing the extra config keys: %s", err)
+	}
+	for _, line := range []string{`KEY +TYPE`, `kubelet\.MaxPods +int32`, `proxy\.IPTables\.SyncPeriod\.Duration +time\.Duration`} {
+		if !regexp.MustCompile("(?m)^" + line + "$").Match(buf.Bytes()) {
+			t.Errorf("Expected a line matching %s, got:\n%s", line, buf.String())
+		}
+	}
+
+	if err := printExtraConfigFields(&buf, []string{"dns"}); err == nil {
+		t.Errorf("Expected an error listing the keys of an unknown component")
+	}
+}
@@ -22,15 +22,16 @@ import (
 
 	"github.com/golang/glog"
 	"github.com/spf13/cobra"
-	"k8s.io/minikube/pkg/minikube/config"
 	"k8s.io/minikube/pkg/minikube/machine"
 )
 
+var ipNode string
+
 // ipCmd represents the ip command
 var ipCmd = &cobra.Command{
 	Use:   "ip",
 	Short: "Retrieves the IP address of the running cluster",
-	Long:  `Retrieves the IP address of the running cluster, and writes it to STDOUT.`,
+	Long:  `Retrieves the IP address of the running cluster, or of one of its worker nodes, and writes it to STDOUT.`,
 	Run: func(cmd *cobra.Command, args []string) {
 		api, err := machine.NewAPIClient()
 		if err != nil {
@@ -38,7 +39,7 @@ var ipCmd = &cobra.Command{
 			os.Exit(1)
 		}
 		defer api.Close()
-		host, err := api.Load(config.GetMachineName())
+		host, err := getNodeHost(api, ipNode)
 		if err != nil {
 			glog.Errorln("Error getting IP: ", err)
 			os.Exit(1)
@@ -53,5 +54,6 @@ var ipCmd = &cobra.Command{
 }
 
 func init() {
+	ipCmd.Flags().StringVarP(&ipNode, "node", "n", "", "The worker node to retrieve the IP address of, instead of the cluster's machine")
 	RootCmd.AddCommand(ipCmd)
 }
@@ -20,30 +20,58 @@ import (
 	"fmt"
 	"log"
 	"os"
+	"regexp"
+	"strings"
+	"time"
 
 	"github.com/spf13/cobra"
 	cmdUtil "k8s.io/minikube/cmd/util"
 	"k8s.io/minikube/pkg/minikube/cluster"
 	"k8s.io/minikube/pkg/minikube/machine"
+	"k8s.io/minikube/pkg/util"
 )
 
 var (
-	follow bool
+	follow        bool
+	logComponents []string
+	logSince      time.Duration
+	logGrep       string
+	logLength     int
 )
 
 // logsCmd represents the logs command
 var logsCmd = &cobra.Command{
 	Use:   "logs",
 	Short: "Gets the logs of the running localkube instance, used for debugging minikube, not user code",
-	Long:  `Gets the logs of the running localkube instance, used for debugging minikube, not user code.`,
+	Long: `Gets the logs of the running localkube instance, used for debugging minikube, not user code.
+Localkube tags each line with the component that logged it, which --component filters on: one of
+` + strings.Join(util.LogComponents, ", ") + `.`,
 	Run: func(cmd *cobra.Command, args []string) {
+		for _, c := range logComponents {
+			if !cluster.IsLogComponent(c) {
+				fmt.Fprintf(os.Stderr, "Unknown component %q, expected one of %s\n", c, strings.Join(util.LogComponents, ", "))
+				os.Exit(1)
+			}
+		}
+		if logGrep != "" {
+			if _, err := regexp.CompilePOSIX(logGrep); err != nil {
+				fmt.Fprintf(os.Stderr, "Invalid --grep regular expression: %s\n", err)
+				os.Exit(1)
+			}
+		}
 		api, err := machine.NewAPIClient()
 		if err != nil {
 			fmt.Fprintf(os.Stderr, "Error getting client: %s\n", err)
 			os.Exit(1)
 		}
 		defer api.Close()
-		s, err := cluster.GetHostLogs(api, follow)
+		s, err := cluster.GetHostLogs(api, cluster.LogsOptions{
+			Follow:     follow,
+			Components: logComponents,
+			Since:      logSince,
+			Grep:       logGrep,
+			Length:     logLength,
+		})
 		if err != nil {
 			log.Println("Error getting machine logs:", err)
 			cmdUtil.MaybeReportErrorAndExit(err)
@@ -54,5 +82,9 @@ var logsCmd = &cobra.Command{
 
 func init() {
 	logsCmd.Flags().BoolVarP(&follow, "follow", "f", false, "Show only the most recent journal entries, and continuously print new entries as they are appended to the journal.")
+	logsCmd.Flags().StringSliceVar(&logComponents, "component", nil, "Only show the lines logged by these components, e.g. apiserver,kubelet")
+	logsCmd.Flags().DurationVar(&logSince, "since", 0, "Only show the lines logged within this duration, e.g. 10m")
+	logsCmd.Flags().StringVar(&logGrep, "grep", "", "Only show the lines matching this extended regular expression")
+	logsCmd.Flags().IntVarP(&logLength, "length", "n", 0, "Only show the last n matching lines, or all of them if 0")
 	RootCmd.AddCommand(logsCmd)
 }
@@ -0,0 +1,98 @@
+/*
+Copyright 2016 The Kubernetes Authors All rights reserved.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package cmd
+
+import (
+	"fmt"
+	"io"
+	"os"
+	"strconv"
+	"strings"
+	"text/tabwriter"
+
+	units "github.com/docker/go-units"
+	"github.com/golang/glog"
+	"github.com/spf13/cobra"
+	cmdUtil "k8s.io/minikube/cmd/util"
+	"k8s.io/minikube/pkg/minikube/cluster"
+	"k8s.io/minikube/pkg/minikube/metrics"
+	"k8s.io/minikube/pkg/util"
+)
+
+var metricsRaw bool
+
+// metricsCmd represents the metrics command
+var metricsCmd = &cobra.Command{
+	Use:   "metrics",
+	Short: "Prints a summary of the metrics of the cluster components",
+	Long: fmt.Sprintf(`Scrapes the Prometheus metrics localkube serves in the VM on %s once, and prints a summary:
+the readiness, restarts and number of time series of each component, the resource usage of
+localkube and the mean latencies of the cluster. Every metric has a %q label naming the
+component it belongs to.`, util.LocalkubeMetricsPath, util.MetricsComponentLabel),
+	Run: func(cmd *cobra.Command, args []string) {
+		api := loadUnpausedAPI()
+		defer api.Close()
+
+		out, err := cluster.GetLocalkubeMetrics(api)
+		if err != nil {
+			glog.Errorln("Error getting localkube metrics:", err)
+			cmdUtil.MaybeReportErrorAndExit(err)
+		}
+		if metricsRaw {
+			fmt.Print(out)
+			return
+		}
+		summary, err := metrics.Summarize(strings.NewReader(out))
+		if err != nil {
+			glog.Errorln("Error summarizing localkube metrics:", err)
+			os.Exit(1)
+		}
+		printMetricsSummary(os.Stdout, summary)
+	},
+}
+
+// printMetricsSummary writes the summary as a table of the components followed by the resource
+// usage and latencies.
+func printMetricsSummary(out io.Writer, summary *metrics.Summary) {
+	w := tabwriter.NewWriter(out, 0, 8, 2, ' ', 0)
+	fmt.Fprintln(w, "COMPONENT\tREADY\tRESTARTS\tSERIES")
+	for _, c := range summary.Components {
+		ready, restarts := "-", "-"
+		if c.Supervised {
+			ready, restarts = strconv.FormatBool(c.Ready), strconv.Itoa(c.Restarts)
+		}
+		fmt.Fprintf(w, "%s\t%s\t%s\t%d\n", c.Name, ready, restarts, c.Series)
+	}
+	w.Flush()
+
+	fmt.Fprintf(out, "\nlocalkube: %.1fs CPU, %s resident memory, %.0f goroutines\n",
+		summary.CPUSeconds, units.BytesSize(summary.ResidentMemoryBytes), summary.Goroutines)
+	if len(summary.Latencies) == 0 {
+		return
+	}
+	fmt.Fprintln(out, "\nMean latencies:")
+	w = tabwriter.NewWriter(out, 0, 8, 2, ' ', 0)
+	for _, l := range summary.Latencies {
+		fmt.Fprintf(w, "  %s\t%s\t(%d observed)\n", l.Description, l.Mean, l.Count)
+	}
+	w.Flush()
+}
+
+func init() {
+	metricsCmd.Flags().BoolVar(&metricsRaw, "raw", false, "Print the scraped metrics in the Prometheus text format instead of a summary")
+	RootCmd.AddCommand(metricsCmd)
+}
@@ -0,0 +1,54 @@
+/*
+Copyright 2016 The Kubernetes Authors All rights reserved.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package cmd
+
+import (
+	"bytes"
+	"testing"
+	"time"
+
+	"k8s.io/minikube/pkg/minikube/metrics"
+)
+
+func TestPrintMetricsSummary(t *testing.T) {
+	summary := &metrics.Summary{
+		Components: []metrics.ComponentSummary{
+			{Name: "apiserver", Supervised: true, Ready: true, Restarts: 1, Series: 120},
+			{Name: "localkube", Series: 40},
+		},
+		Latencies: []metrics.Latency{
+			{Description: "apiserver requests", Count: 4, Mean: 3 * time.Millisecond},
+		},
+		CPUSeconds:          12.5,
+		ResidentMemoryBytes: 300 * 1024 * 1024,
+		Goroutines:          1520,
+	}
+	expected := `COMPONENT  READY  RESTARTS  SERIES
+apiserver  true   1         120
+localkube  -      -         40
+
+localkube: 12.5s CPU, 300 MiB resident memory, 1520 goroutines
+
+Mean latencies:
+  apiserver requests  3ms  (4 observed)
+`
+	var buf bytes.Buffer
+	printMetricsSummary(&buf, summary)
+	if buf.String() != expected {
+		t.Errorf("Expected summary:\n%s\ngot:\n%s", expected, buf.String())
+	}
+}
@@ -0,0 +1,289 @@
+/*
+Copyright 2016 The Kubernetes Authors All rights reserved.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package cmd
+
+import (
+	"fmt"
+	"os"
+	"text/template"
+
+	"github.com/docker/machine/libmachine"
+	"github.com/docker/machine/libmachine/host"
+	"github.com/docker/machine/libmachine/state"
+	"github.com/golang/glog"
+	"github.com/spf13/cobra"
+	apierrors "k8s.io/apimachinery/pkg/api/errors"
+	meta_v1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	cmdUtil "k8s.io/minikube/cmd/util"
+	"k8s.io/minikube/pkg/minikube/cluster"
+	"k8s.io/minikube/pkg/minikube/clusterspec"
+	"k8s.io/minikube/pkg/minikube/config"
+	"k8s.io/minikube/pkg/minikube/constants"
+	"k8s.io/minikube/pkg/minikube/machine"
+)
+
+var nodeListFormat string
+
+// NodeStatus holds the values available to the node list format, and to the nodes of the status format.
+type NodeStatus struct {
+	Name            string
+	MachineName     string
+	IP              string
+	MinikubeStatus  string
+	LocalkubeStatus string
+}
+
+// nodeCmd represents the node command
+var nodeCmd = &cobra.Command{
+	Use:   "node",
+	Short: "Adds, removes and lists the worker nodes of a local kubernetes cluster",
+	Long: `Adds, removes and lists worker nodes, additional machines that join the cluster of the current profile.
+A node runs only the kubelet and proxy, which connect to the apiserver of the cluster's machine with a
+client certificate signed by the minikube CA. Nodes are created with the machine settings the cluster was
+last started with, are stopped and started with the cluster, and are deleted with it.`,
+	Run: func(cmd *cobra.Command, args []string) {
+		cmd.Help()
+	},
+}
+
+var nodeAddCmd = &cobra.Command{
+	Use:   "add NAME",
+	Short: "Adds a worker node to the cluster",
+	Long: `Creates a machine for the node, named after the profile and the node, and starts it as a worker of the
+cluster. The node is registered in Kubernetes under the name of its machine.`,
+	Run: func(cmd *cobra.Command, args []string) {
+		if len(args) != 1 {
+			fmt.Fprintln(os.Stderr, "usage: minikube node add NAME")
+			os.Exit(1)
+		}
+		api := loadRunningAPI()
+		defer api.Close()
+
+		machineConfig, kubernetesConfig := getNodeConfigs()
+		nodes, err := cluster.GetNodes()
+		if err != nil {
+			glog.Errorln("Error getting nodes:", err)
+			cmdUtil.MaybeReportErrorAndExit(err)
+		}
+		fmt.Printf("Adding node %s...\n", args[0])
+		if err := cluster.AddNode(api, machineConfig, kubernetesConfig, args[0]); err != nil {
+			glog.Errorln("Error adding node:", err)
+			cmdUtil.MaybeReportErrorAndExit(err)
+		}
+		if len(nodes) == 0 {
+			fmt.Println("Recreating the pods of the cluster on the pod network of its nodes...")
+			if err := recreatePods(); err != nil {
+				glog.Errorln("Error recreating pods:", err)
+			}
+		}
+		fmt.Printf("Node %s joined the cluster as %s.\n", args[0], cluster.GetNodeMachineName(args[0]))
+	},
+}
+
+var nodeRemoveCmd = &cobra.Command{
+	Use:   "remove NAME",
+	Short: "Removes a worker node from the cluster",
+	Long: `Deletes the node from Kubernetes, if the cluster is running, and deletes its machine. The pods that
+ran on the node are rescheduled on the remaining nodes by their controllers.`,
+	Run: func(cmd *cobra.Command, args []string) {
+		if len(args) != 1 {
+			fmt.Fprintln(os.Stderr, "usage: minikube node remove NAME")
+			os.Exit(1)
+		}
+		api, err := machine.NewAPIClient()
+		if err != nil {
+			fmt.Fprintf(os.Stderr, "Error getting client: %s\n", err)
+			os.Exit(1)
+		}
+		defer api.Close()
+
+		fmt.Printf("Removing node %s...\n", args[0])
+		if err := deleteKubernetesNode(api, args[0]); err != nil {
+			glog.Errorln("Error deleting the node from Kubernetes, it is removed from the cluster anyway:", err)
+		}
+		if err := cluster.RemoveNode(api, args[0]); err != nil {
+			glog.Errorln("Error removing node:", err)
+			cmdUtil.MaybeReportErrorAndExit(err)
+		}
+		fmt.Printf("Node %s removed.\n", args[0])
+	},
+}
+
+var nodeListCmd = &cobra.Command{
+	Use:   "list",
+	Short: "Lists the worker nodes of the cluster",
+	Long:  `Lists the worker nodes of the cluster of the current profile, with their IPs and status.`,
+	Run: func(cmd *cobra.Command, args []string) {
+		api, err := machine.NewAPIClient()
+		if err != nil {
+			fmt.Fprintf(os.Stderr, "Error getting client: %s\n", err)
+			os.Exit(1)
+		}
+		defer api.Close()
+
+		nodes, err := getNodeStatuses(api)
+		if err != nil {
+			glog.Errorln("Error getting nodes:", err)
+			cmdUtil.MaybeReportErrorAndExit(err)
+		}
+		tmpl, err := template.New("list").Parse(nodeListFormat)
+		if err != nil {
+			glog.Errorln("Error creating list template:", err)
+			os.Exit(1)
+		}
+		for _, n := range nodes {
+			if err := tmpl.Execute(os.Stdout, n); err != nil {
+				glog.Errorln("Error executing list template:", err)
+				os.Exit(1)
+			}
+		}
+	},
+}
+
+// getNodeConfigs returns the configs the cluster was last started with, which nodes are created
+// and started with.
+func getNodeConfigs() (cluster.MachineConfig, cluster.KubernetesConfig) {
+	spec, err := clusterspec.Load(config.GetMachineName())
+	if err != nil {
+		glog.Errorln("Error loading the saved cluster config:", err)
+		os.Exit(1)
+	}
+	if spec == nil {
+		fmt.Fprintf(os.Stderr, "No cluster config is saved for %s, run minikube start first\n", config.GetMachineName())
+		os.Exit(1)
+	}
+	machineConfig, err := spec.GetMachineConfig()
+	if err != nil {
+		glog.Errorln("Error reading the saved machine config:", err)
+		os.Exit(1)
+	}
+	kubernetesConfig, err := spec.GetKubernetesConfig()
+	if err != nil {
+		glog.Errorln("Error reading the saved Kubernetes config:", err)
+		os.Exit(1)
+	}
+	return machineConfig, kubernetesConfig
+}
+
+// deleteKubernetesNode deletes the node object of a node, when the cluster is running.
+func deleteKubernetesNode(api libmachine.API, name string) error {
+	if s, err := cluster.GetHostStatus(api); err != nil || s != state.Running.String() {
+		return err
+	}
+	client, err := getClusterClient(cmdUtil.GetKubeConfigPath())
+	if err != nil {
+		return err
+	}
+	err = client.CoreV1().Nodes().Delete(cluster.GetNodeMachineName(name), nil)
+	if apierrors.IsNotFound(err) {
+		return nil
+	}
+	return err
+}
+
+// recreatePods deletes the pods that ran before the first node was added, which got their IPs from
+// Docker's bridge and can't be reached from the nodes, so that their controllers recreate them on the
+// pod network. The pods without a controller are listed instead.
+func recreatePods() error {
+	client, err := getClusterClient(cmdUtil.GetKubeConfigPath())
+	if err != nil {
+		return err
+	}
+	pods, err := client.CoreV1().Pods(meta_v1.NamespaceAll).List(meta_v1.ListOptions{})
+	if err != nil {
+		return err
+	}
+	for _, p := range pods.Items {
+		if p.Spec.HostNetwork || p.Status.PodIP == "" {
+			continue
+		}
+		if len(p.OwnerReferences) == 0 {
+			fmt.Printf("Pod %s/%s can't be reached from the nodes until it is recreated.\n", p.Namespace, p.Name)
+			continue
+		}
+		if err := client.CoreV1().Pods(p.Namespace).Delete(p.Name, nil); err != nil && !apierrors.IsNotFound(err) {
+			return err
+		}
+	}
+	return nil
+}
+
+// getNodeStatuses returns the status of the machine and localkube of each node.
+func getNodeStatuses(api libmachine.API) ([]NodeStatus, error) {
+	nodes, err := cluster.GetNodes()
+	if err != nil {
+		return nil, err
+	}
+	statuses := []NodeStatus{}
+	for _, n := range nodes {
+		s := NodeStatus{
+			Name:            n.Name,
+			MachineName:     cluster.GetNodeMachineName(n.Name),
+			LocalkubeStatus: state.None.String(),
+		}
+		s.MinikubeStatus, err = cluster.GetNodeHostStatus(api, n.Name)
+		if err != nil {
+			return nil, err
+		}
+		if s.MinikubeStatus == state.Running.String() {
+			s.LocalkubeStatus, err = cluster.GetNodeLocalkubeStatus(api, n.Name)
+			if err != nil {
+				glog.Infof("Error getting the localkube status of node %s: %s", n.Name, err)
+				s.LocalkubeStatus = state.Error.String()
+			}
+			if h, err := cluster.GetNodeHost(api, n.Name); err == nil {
+				s.IP, _ = h.Driver.GetIP()
+			}
+		}
+		statuses = append(statuses, s)
+	}
+	return statuses, nil
+}
+
+// startNodes starts the nodes of the cluster after the cluster itself, so that they join its
+// apiserver at its current IP. A node that fails to start doesn't fail the cluster.
+func startNodes(api libmachine.API, machineConfig cluster.MachineConfig, kubernetesConfig cluster.KubernetesConfig) {
+	nodes, err := cluster.GetNodes()
+	if err != nil {
+		glog.Errorln("Error getting nodes:", err)
+		return
+	}
+	for _, n := range nodes {
+		fmt.Printf("Starting node %s...\n", n.Name)
+		if err := cluster.StartNode(api, machineConfig, kubernetesConfig, n.Name); err != nil {
+			glog.Errorf("Error starting node %s: %s", n.Name, err)
+		}
+	}
+}
+
+// getNodeHost returns the machine of the node, or of the cluster if node is empty.
+func getNodeHost(api libmachine.API, node string) (*host.Host, error) {
+	if node == "" {
+		return cluster.CheckIfApiExistsAndLoad(api)
+	}
+	return cluster.GetNodeHost(api, node)
+}
+
+func init() {
+	nodeListCmd.Flags().StringVar(&nodeListFormat, "format", constants.DefaultNodeListFormat,
+		`Go template format string for the node list output.  The format for Go templates can be found here: https://golang.org/pkg/text/template/
+For the list of accessible variables for the template, see the struct values here: https://godoc.org/k8s.io/minikube/cmd/minikube/cmd#NodeStatus`)
+	nodeCmd.AddCommand(nodeAddCmd)
+	nodeCmd.AddCommand(nodeRemoveCmd)
+	nodeCmd.AddCommand(nodeListCmd)
+	RootCmd.AddCommand(nodeCmd)
+}
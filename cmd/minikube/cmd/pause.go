@@ -0,0 +1,115 @@
+/*
+Copyright 2016 The Kubernetes Authors All rights reserved.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package cmd
+
+import (
+	"fmt"
+	"os"
+
+	"github.com/docker/machine/libmachine"
+	"github.com/docker/machine/libmachine/state"
+	"github.com/golang/glog"
+	"github.com/spf13/cobra"
+	cmdUtil "k8s.io/minikube/cmd/util"
+	"k8s.io/minikube/pkg/minikube/cluster"
+	"k8s.io/minikube/pkg/minikube/constants"
+	"k8s.io/minikube/pkg/minikube/machine"
+)
+
+// pauseCmd represents the pause command
+var pauseCmd = &cobra.Command{
+	Use:   "pause",
+	Short: "Pauses a running local kubernetes cluster",
+	Long: `Pauses a running local kubernetes cluster to free the CPU it uses. localkube and the containers
+running in the VM are frozen, while the VM itself keeps running with all its state. The cluster
+is resumed in seconds with the "unpause" command.`,
+	Run: func(cmd *cobra.Command, args []string) {
+		api, err := machine.NewAPIClient()
+		if err != nil {
+			fmt.Fprintf(os.Stderr, "Error getting client: %s\n", err)
+			os.Exit(1)
+		}
+		defer api.Close()
+
+		switch getPausableLocalkubeStatus(api) {
+		case constants.LocalkubePaused:
+			fmt.Println("The cluster is already paused.")
+			return
+		case state.Stopped.String():
+			fmt.Fprintln(os.Stderr, "localkube is not running, so the cluster cannot be paused")
+			os.Exit(1)
+		}
+
+		fmt.Println("Pausing local Kubernetes cluster...")
+		if err := cluster.PauseCluster(api); err != nil {
+			glog.Errorln("Error pausing cluster:", err)
+			cmdUtil.MaybeReportErrorAndExit(err)
+		}
+		fmt.Println("Cluster paused. Run minikube unpause to resume it.")
+	},
+}
+
+// unpauseCmd represents the unpause command
+var unpauseCmd = &cobra.Command{
+	Use:   "unpause",
+	Short: "Resumes a paused local kubernetes cluster",
+	Long:  `Resumes the localkube and containers frozen by the "pause" command.`,
+	Run: func(cmd *cobra.Command, args []string) {
+		api, err := machine.NewAPIClient()
+		if err != nil {
+			fmt.Fprintf(os.Stderr, "Error getting client: %s\n", err)
+			os.Exit(1)
+		}
+		defer api.Close()
+
+		if getPausableLocalkubeStatus(api) != constants.LocalkubePaused {
+			fmt.Println("The cluster is not paused.")
+			return
+		}
+
+		fmt.Println("Resuming local Kubernetes cluster...")
+		if err := cluster.UnpauseCluster(api); err != nil {
+			glog.Errorln("Error unpausing cluster:", err)
+			cmdUtil.MaybeReportErrorAndExit(err)
+		}
+		fmt.Println("Cluster resumed.")
+	},
+}
+
+// getPausableLocalkubeStatus returns the status of localkube, exiting if the VM isn't running.
+func getPausableLocalkubeStatus(api libmachine.API) string {
+	ms, err := cluster.GetHostStatus(api)
+	if err != nil {
+		glog.Errorln("Error getting machine status:", err)
+		cmdUtil.MaybeReportErrorAndExit(err)
+	}
+	if ms != state.Running.String() {
+		fmt.Fprintln(os.Stderr, "minikube is not currently running")
+		os.Exit(1)
+	}
+	ls, err := cluster.GetLocalkubeStatus(api)
+	if err != nil {
+		glog.Errorln("Error getting localkube status:", err)
+		cmdUtil.MaybeReportErrorAndExit(err)
+	}
+	return ls
+}
+
+func init() {
+	RootCmd.AddCommand(pauseCmd)
+	RootCmd.AddCommand(unpauseCmd)
+}
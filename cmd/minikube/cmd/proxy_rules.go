@@ -0,0 +1,81 @@
+/*
+Copyright 2016 The Kubernetes Authors All rights reserved.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package cmd
+
+import (
+	"fmt"
+	"strings"
+
+	"github.com/golang/glog"
+	"github.com/spf13/cobra"
+	cmdUtil "k8s.io/minikube/cmd/util"
+	"k8s.io/minikube/pkg/minikube/cluster"
+)
+
+var proxyRulesAll bool
+
+// proxyRulesCmd represents the proxy-rules command
+var proxyRulesCmd = &cobra.Command{
+	Use:   "proxy-rules",
+	Short: "Prints the iptables rules kube-proxy set up for services",
+	Long: `Prints the iptables rules kube-proxy set up in the VM to route service traffic, in the
+iptables-save format. These are the rules of the KUBE- chains, which both the iptables and the
+userspace proxy modes use.`,
+	Run: func(cmd *cobra.Command, args []string) {
+		api := loadRunningAPI()
+		defer api.Close()
+
+		rules, err := cluster.GetProxyRules(api)
+		if err != nil {
+			glog.Errorln("Error getting proxy rules:", err)
+			cmdUtil.MaybeReportErrorAndExit(err)
+		}
+		if !proxyRulesAll {
+			rules = filterProxyRules(rules)
+		}
+		fmt.Print(rules)
+	},
+}
+
+// filterProxyRules returns the chains and rules of kube-proxy in the iptables-save output rules,
+// along with the headers and COMMIT lines of the tables they are in. Tables without any are dropped.
+func filterProxyRules(rules string) string {
+	var out, table []string
+	for _, line := range strings.Split(rules, "\n") {
+		switch {
+		case strings.HasPrefix(line, "*"):
+			table = []string{line}
+		case line == "COMMIT":
+			if len(table) > 1 {
+				out = append(out, table...)
+				out = append(out, line)
+			}
+			table = nil
+		case table != nil && strings.Contains(line, "KUBE-"):
+			table = append(table, line)
+		}
+	}
+	if len(out) == 0 {
+		return ""
+	}
+	return strings.Join(out, "\n") + "\n"
+}
+
+func init() {
+	proxyRulesCmd.Flags().BoolVar(&proxyRulesAll, "all", false, "Print all the iptables rules of the VM, not only the rules of kube-proxy")
+	RootCmd.AddCommand(proxyRulesCmd)
+}
@@ -27,6 +27,8 @@ import (
 	"k8s.io/minikube/pkg/minikube/machine"
 )
 
+var sshNode string
+
 // sshCmd represents the docker-ssh command
 var sshCmd = &cobra.Command{
 	Use:   "ssh",
@@ -39,7 +41,7 @@ var sshCmd = &cobra.Command{
 			os.Exit(1)
 		}
 		defer api.Close()
-		host, err := cluster.CheckIfApiExistsAndLoad(api)
+		host, err := getNodeHost(api, sshNode)
 		if err != nil {
 			fmt.Fprintf(os.Stderr, "Error getting host: %s\n", err)
 			os.Exit(1)
@@ -48,7 +50,11 @@ var sshCmd = &cobra.Command{
 			fmt.Println(`'none' driver does not support 'minikube ssh' command`)
 			os.Exit(0)
 		}
-		err = cluster.CreateSSHShell(api, args)
+		if sshNode != "" {
+			err = cluster.CreateNodeSSHShell(api, sshNode, args)
+		} else {
+			err = cluster.CreateSSHShell(api, args)
+		}
 		if err != nil {
 			glog.Errorln(errors.Wrap(err, "Error attempting to ssh/run-ssh-command"))
 			os.Exit(1)
@@ -57,5 +63,6 @@ var sshCmd = &cobra.Command{
 }
 
 func init() {
+	sshCmd.Flags().StringVarP(&sshNode, "node", "n", "", "The worker node to log into, instead of the cluster's machine")
 	RootCmd.AddCommand(sshCmd)
 }
@@ -19,6 +19,7 @@ package cmd
 import (
 	"fmt"
 	"io/ioutil"
+	"net"
 	"os"
 	"os/exec"
 	"path/filepath"
@@ -27,16 +28,27 @@ import (
 	"time"
 
 	units "github.com/docker/go-units"
+	"github.com/docker/machine/libmachine"
 	"github.com/docker/machine/libmachine/host"
+	"github.com/docker/machine/libmachine/state"
 	"github.com/golang/glog"
+	"github.com/pkg/errors"
 	"github.com/spf13/cobra"
+	"github.com/spf13/pflag"
 	"github.com/spf13/viper"
+	"k8s.io/client-go/kubernetes"
+	"k8s.io/client-go/tools/clientcmd"
+	configCmd "k8s.io/minikube/cmd/minikube/cmd/config"
 	cmdUtil "k8s.io/minikube/cmd/util"
+	"k8s.io/minikube/pkg/minikube/assets"
 	"k8s.io/minikube/pkg/minikube/cluster"
+	"k8s.io/minikube/pkg/minikube/clusterspec"
 	cfg "k8s.io/minikube/pkg/minikube/config"
 	"k8s.io/minikube/pkg/minikube/constants"
+	"k8s.io/minikube/pkg/minikube/extraconfig"
 	"k8s.io/minikube/pkg/minikube/kubernetes_versions"
 	"k8s.io/minikube/pkg/minikube/machine"
+	"k8s.io/minikube/pkg/minikube/storageclass"
 	"k8s.io/minikube/pkg/util"
 	pkgutil "k8s.io/minikube/pkg/util"
 	"k8s.io/minikube/pkg/util/kubeconfig"
@@ -59,9 +71,26 @@ const (
 	createMount           = "mount"
 	featureGates          = "feature-gates"
 	apiServerName         = "apiserver-name"
+	apiServerExtraSANs    = "apiserver-extra-sans"
 	dnsDomain             = "dns-domain"
 	mountString           = "mount-string"
 	disableDriverMounts   = "disable-driver-mounts"
+	storageBackend        = "storage-backend"
+	etcdCAFile            = "etcd-cafile"
+	etcdCertFile          = "etcd-certfile"
+	etcdKeyFile           = "etcd-keyfile"
+	authorizationMode     = "authorization-mode"
+	authzWebhookFile      = "authorization-webhook-config-file"
+	auditLogPath          = "audit-log-path"
+	podNetworkCIDR        = "pod-network-cidr"
+	serviceClusterIPRange = "service-cluster-ip-range"
+	dnsIP                 = "dns-ip"
+	proxyMode             = "proxy-mode"
+	masqueradeAll         = "masquerade-all"
+	clusterCIDR           = "cluster-cidr"
+	waitUntilUsable       = "wait"
+	waitTimeout           = "wait-timeout"
+	fromFile              = "from-file"
 )
 
 var (
@@ -69,6 +98,8 @@ var (
 	dockerEnv        []string
 	dockerOpt        []string
 	insecureRegistry []string
+	etcdServers      []string
+	admissionControl []string
 	extraOptions     util.ExtraOptionSlice
 )
 
@@ -89,6 +120,35 @@ func runStart(cmd *cobra.Command, args []string) {
 	}
 	defer api.Close()
 
+	saved, err := clusterspec.Load(cfg.GetMachineName())
+	if err != nil {
+		glog.Errorln("Error loading the saved cluster config:", err)
+		cmdUtil.MaybeReportErrorAndExit(err)
+	}
+
+	if path := viper.GetString(fromFile); path != "" {
+		spec, err := clusterspec.ReadFile(path)
+		if err != nil {
+			glog.Errorln("Error reading --from-file:", err)
+			os.Exit(1)
+		}
+		if err := applyClusterSpec(cmd.Flags(), spec); err != nil {
+			glog.Errorln("Error applying cluster spec:", err)
+			os.Exit(1)
+		}
+		if err := applyClusterSpecAddons(api, spec.Addons); err != nil {
+			glog.Errorln("Error applying the addons of the cluster spec:", err)
+			cmdUtil.MaybeReportErrorAndExit(err)
+		}
+	}
+	// Settings that aren't set again keep the values the cluster was last started with
+	if saved != nil {
+		if err := applyClusterSpec(cmd.Flags(), saved); err != nil {
+			glog.Errorln("Error applying the saved cluster config:", err)
+			cmdUtil.MaybeReportErrorAndExit(err)
+		}
+	}
+
 	diskSize := viper.GetString(humanReadableDiskSize)
 	diskSizeMB := calculateDiskSizeInMB(diskSize)
 
@@ -98,9 +158,51 @@ func runStart(cmd *cobra.Command, args []string) {
 		os.Exit(1)
 	}
 
+	if sb := viper.GetString(storageBackend); !isValidStorageBackend(sb) {
+		glog.Errorf("Invalid storage backend %q, must be one of %v", sb, constants.StorageBackends)
+		os.Exit(1)
+	}
+
+	if err := pkgutil.ValidateEtcdConfig(etcdServers, viper.GetString(etcdCAFile), viper.GetString(etcdCertFile), viper.GetString(etcdKeyFile)); err != nil {
+		glog.Errorln("Invalid external etcd configuration:", err)
+		os.Exit(1)
+	}
+
+	if err := validateAuthorizationFlags(viper.GetString(authorizationMode), viper.GetString(authzWebhookFile)); err != nil {
+		glog.Errorln("Invalid authorization configuration:", err)
+		os.Exit(1)
+	}
+
+	if err := validateNetworkFlags(viper.GetString(podNetworkCIDR), viper.GetString(serviceClusterIPRange), getDNSIP(),
+		viper.GetString(hostOnlyCIDR), viper.GetString(vmDriver)); err != nil {
+		glog.Errorln("Invalid network configuration:", err)
+		os.Exit(1)
+	}
+	if err := pkgutil.ValidateProxyConfig(viper.GetString(proxyMode), viper.GetString(clusterCIDR)); err != nil {
+		glog.Errorln("Invalid proxy configuration:", err)
+		os.Exit(1)
+	}
+	extraSANs := getExtraSANs(viper.GetString(apiServerExtraSANs))
+	if _, _, err := pkgutil.ParseSANs(extraSANs); err != nil {
+		glog.Errorf("Invalid --%s: %s", apiServerExtraSANs, err)
+		os.Exit(1)
+	}
+	// The registries on the service network are insecure by default, so follow a custom service range
+	if !cmd.Flags().Changed("insecure-registry") {
+		insecureRegistry = []string{viper.GetString(serviceClusterIPRange)}
+	}
+
+	storageClasses, err := storageclass.ParseStorageClasses(viper.GetString(constants.StorageClassesConfig))
+	if err != nil {
+		glog.Errorf("Invalid %s setting: %s", constants.StorageClassesConfig, err)
+		os.Exit(1)
+	}
+	warnDefaultStorageClass(storageClasses)
+
 	if dv := viper.GetString(kubernetesVersion); dv != constants.DefaultKubernetesVersion {
 		validateK8sVersion(dv)
 	}
+	validateExtraConfig(extraOptions, viper.GetString(kubernetesVersion))
 
 	config := cluster.MachineConfig{
 		MinikubeISO:         viper.GetString(isoURL),
@@ -120,6 +222,37 @@ func runStart(cmd *cobra.Command, args []string) {
 		DisableDriverMounts: viper.GetBool(disableDriverMounts),
 	}
 
+	kubernetesConfig := cluster.KubernetesConfig{
+		KubernetesVersion:        viper.GetString(kubernetesVersion),
+		APIServerName:            viper.GetString(apiServerName),
+		APIServerExtraSANs:       extraSANs,
+		DNSDomain:                viper.GetString(dnsDomain),
+		FeatureGates:             viper.GetString(featureGates),
+		ContainerRuntime:         viper.GetString(containerRuntime),
+		NetworkPlugin:            viper.GetString(networkPlugin),
+		StorageBackend:           viper.GetString(storageBackend),
+		EtcdServers:              etcdServers,
+		EtcdCAFile:               viper.GetString(etcdCAFile),
+		EtcdCertFile:             viper.GetString(etcdCertFile),
+		EtcdKeyFile:              viper.GetString(etcdKeyFile),
+		AdmissionControl:         admissionControl,
+		AuthorizationMode:        viper.GetString(authorizationMode),
+		AuthorizationWebhookFile: viper.GetString(authzWebhookFile),
+		AuditLogPath:             viper.GetString(auditLogPath),
+		StorageClasses:           storageClasses,
+		PodCIDR:                  viper.GetString(podNetworkCIDR),
+		ServiceCIDR:              viper.GetString(serviceClusterIPRange),
+		DNSIP:                    getDNSIP(),
+		ProxyMode:                viper.GetString(proxyMode),
+		MasqueradeAll:            viper.GetBool(masqueradeAll),
+		ClusterCIDR:              viper.GetString(clusterCIDR),
+		ExtraOptions:             extraOptions,
+	}
+
+	if saved != nil {
+		checkClusterConfigDrift(api, saved, clusterspec.New(config, kubernetesConfig, nil, getMountString()))
+	}
+
 	fmt.Printf("Starting local Kubernetes %s cluster...\n", viper.GetString(kubernetesVersion))
 	fmt.Println("Starting VM...")
 	var host *host.Host
@@ -142,16 +275,7 @@ func runStart(cmd *cobra.Command, args []string) {
 		glog.Errorln("Error getting VM IP address: ", err)
 		cmdUtil.MaybeReportErrorAndExit(err)
 	}
-	kubernetesConfig := cluster.KubernetesConfig{
-		KubernetesVersion: viper.GetString(kubernetesVersion),
-		NodeIP:            ip,
-		APIServerName:     viper.GetString(apiServerName),
-		DNSDomain:         viper.GetString(dnsDomain),
-		FeatureGates:      viper.GetString(featureGates),
-		ContainerRuntime:  viper.GetString(containerRuntime),
-		NetworkPlugin:     viper.GetString(networkPlugin),
-		ExtraOptions:      extraOptions,
-	}
+	kubernetesConfig.NodeIP = ip
 
 	fmt.Println("Moving files into cluster...")
 	if err := cluster.UpdateCluster(host.Driver, kubernetesConfig); err != nil {
@@ -160,7 +284,7 @@ func runStart(cmd *cobra.Command, args []string) {
 	}
 
 	fmt.Println("Setting up certs...")
-	if err := cluster.SetupCerts(host.Driver, kubernetesConfig.APIServerName, kubernetesConfig.DNSDomain); err != nil {
+	if err := cluster.SetupCerts(host.Driver, kubernetesConfig.APIServerName, kubernetesConfig.DNSDomain, kubernetesConfig.ServiceCIDR, kubernetesConfig.APIServerExtraSANs); err != nil {
 		glog.Errorln("Error configuring authentication: ", err)
 		cmdUtil.MaybeReportErrorAndExit(err)
 	}
@@ -227,6 +351,16 @@ func runStart(cmd *cobra.Command, args []string) {
 		}
 	}
 
+	if err := saveClusterSpec(config, kubernetesConfig); err != nil {
+		glog.Errorln("Error saving cluster config, the next minikube start may not reuse it:", err)
+	}
+
+	startNodes(api, config, kubernetesConfig)
+
+	if viper.GetBool(waitUntilUsable) {
+		waitForCluster(api, kubeConfigFile, viper.GetDuration(waitTimeout))
+	}
+
 	if kubeCfgSetup.KeepContext {
 		fmt.Printf("The local Kubernetes cluster has started. The kubectl context has not been altered, kubectl will require \"--context=%s\" to use the local Kubernetes cluster.\n",
 			kubeCfgSetup.ClusterName)
@@ -260,6 +394,22 @@ This can also be done automatically by setting the env var CHANGE_MINIKUBE_NONE_
 	}
 }
 
+// validateExtraConfig exits if localkube would fail to set any of the options. The keys are
+// checked against the configurations minikube was built with, so for other Kubernetes versions
+// the errors are only warnings.
+func validateExtraConfig(options pkgutil.ExtraOptionSlice, version string) {
+	err := extraconfig.Validate(options)
+	if err == nil {
+		return
+	}
+	if version != constants.DefaultKubernetesVersion {
+		fmt.Fprintf(os.Stderr, "WARNING: These --extra-config options may not apply to Kubernetes %s:\n%s\n", version, err)
+		return
+	}
+	glog.Errorf("Invalid --extra-config options:\n%s\nRun minikube extra-config list to see the keys that can be set.", err)
+	os.Exit(1)
+}
+
 func validateK8sVersion(version string) {
 	validVersion, err := kubernetes_versions.IsValidLocalkubeVersion(version, constants.KubernetesVersionGCSURL)
 	if err != nil {
@@ -273,6 +423,337 @@ func validateK8sVersion(version string) {
 	}
 }
 
+func isValidStorageBackend(backend string) bool {
+	for _, b := range constants.StorageBackends {
+		if b == backend {
+			return true
+		}
+	}
+	return false
+}
+
+// warnDefaultStorageClass warns when a declared storage class is the default while the
+// default-storageclass addon also installs a default one.
+func warnDefaultStorageClass(classes []storageclass.StorageClassConfig) {
+	name := storageclass.GetDefault(classes)
+	if name == "" {
+		return
+	}
+	if enabled, err := assets.Addons["default-storageclass"].IsEnabled(); err == nil && enabled {
+		fmt.Fprintf(os.Stderr, "WARNING: storage class %s and the default-storageclass addon both set a default storage class, "+
+			"disable the addon with: minikube addons disable default-storageclass\n", name)
+	}
+}
+
+// validateAuthorizationFlags checks the authorization modes are ones minikube supports, and that
+// the webhook kubeconfig file is set, and exists on the host, exactly when the Webhook mode is used.
+func validateAuthorizationFlags(mode, webhookFile string) error {
+	webhook := false
+	for _, m := range strings.Split(mode, ",") {
+		if !isValidAuthorizationMode(m) {
+			return fmt.Errorf("Invalid authorization mode %q, must be one of %v", m, constants.AuthorizationModes)
+		}
+		webhook = webhook || m == "Webhook"
+	}
+	if webhook != (webhookFile != "") {
+		return fmt.Errorf("--%s must be set exactly when --%s includes Webhook", authzWebhookFile, authorizationMode)
+	}
+	if webhook {
+		if _, err := os.Stat(webhookFile); err != nil {
+			return errors.Wrapf(err, "Error reading --%s", authzWebhookFile)
+		}
+	}
+	return nil
+}
+
+// getDNSIP returns the DNS IP set with --dns-ip, or the default DNS IP of --service-cluster-ip-range.
+func getDNSIP() string {
+	if ip := viper.GetString(dnsIP); ip != "" {
+		return ip
+	}
+	_, serviceNet, err := net.ParseCIDR(viper.GetString(serviceClusterIPRange))
+	if err != nil {
+		// validateNetworkFlags reports the invalid range
+		return ""
+	}
+	return pkgutil.GetDefaultDNSIP(*serviceNet).String()
+}
+
+// validateNetworkFlags checks that the pod and service ranges don't overlap each other or the
+// host-only network of the VM, and that the DNS IP is a usable service IP.
+func validateNetworkFlags(podCIDR, serviceCIDR, dnsIPStr, hostOnly, driver string) error {
+	_, podNet, err := net.ParseCIDR(podCIDR)
+	if err != nil {
+		return errors.Wrapf(err, "Error parsing --%s", podNetworkCIDR)
+	}
+	_, serviceNet, err := net.ParseCIDR(serviceCIDR)
+	if err != nil {
+		return errors.Wrapf(err, "Error parsing --%s", serviceClusterIPRange)
+	}
+	ip := net.ParseIP(dnsIPStr)
+	if ip == nil {
+		return fmt.Errorf("Invalid --%s %q", dnsIP, dnsIPStr)
+	}
+	if err := pkgutil.ValidateNetworkRanges(*podNet, *serviceNet, ip); err != nil {
+		return err
+	}
+
+	if driver != "virtualbox" {
+		return nil
+	}
+	_, hostOnlyNet, err := net.ParseCIDR(hostOnly)
+	if err != nil {
+		return errors.Wrapf(err, "Error parsing --%s", hostOnlyCIDR)
+	}
+	if pkgutil.CIDRsOverlap(*podNet, *hostOnlyNet) {
+		return fmt.Errorf("--%s %s overlaps --%s %s", podNetworkCIDR, podNet, hostOnlyCIDR, hostOnlyNet)
+	}
+	if pkgutil.CIDRsOverlap(*serviceNet, *hostOnlyNet) {
+		return fmt.Errorf("--%s %s overlaps --%s %s", serviceClusterIPRange, serviceNet, hostOnlyCIDR, hostOnlyNet)
+	}
+	return nil
+}
+
+// applyClusterSpec sets the flags of minikube start to the values of the spec, so that they are
+// validated like flags. Flags set on the command line or in the minikube config take precedence
+// over the spec.
+func applyClusterSpec(flags *pflag.FlagSet, c *clusterspec.Cluster) error {
+	m, k := c.Machine, c.Kubernetes
+	// The DNS IP saved with another service range doesn't fit a new range, which gets its default DNS IP instead
+	dnsIPs := nonEmpty(k.DNSIP)
+	if (flags.Changed(serviceClusterIPRange) || viper.InConfig(serviceClusterIPRange)) && viper.GetString(serviceClusterIPRange) != k.ServiceCIDR {
+		dnsIPs = nil
+	}
+	values := []struct {
+		flag   string
+		values []string
+	}{
+		{isoURL, nonEmpty(m.ISOURL)},
+		{memory, nonZero(m.Memory)},
+		{cpus, nonZero(m.CPUs)},
+		{humanReadableDiskSize, nonEmpty(m.DiskSize)},
+		{vmDriver, nonEmpty(m.VMDriver)},
+		{xhyveDiskDriver, nonEmpty(m.XhyveDiskDriver)},
+		{"docker-env", m.DockerEnv},
+		{"docker-opt", m.DockerOpt},
+		{"insecure-registry", m.InsecureRegistry},
+		{"registry-mirror", m.RegistryMirror},
+		{hostOnlyCIDR, nonEmpty(m.HostOnlyCIDR)},
+		{hypervVirtualSwitch, nonEmpty(m.HypervVirtualSwitch)},
+		{kvmNetwork, nonEmpty(m.KvmNetwork)},
+		{disableDriverMounts, isTrue(m.DisableDriverMounts)},
+		{kubernetesVersion, nonEmpty(k.Version)},
+		{apiServerName, nonEmpty(k.APIServerName)},
+		{apiServerExtraSANs, nonEmpty(strings.Join(k.APIServerExtraSANs, ","))},
+		{dnsDomain, nonEmpty(k.DNSDomain)},
+		{containerRuntime, nonEmpty(k.ContainerRuntime)},
+		{networkPlugin, nonEmpty(k.NetworkPlugin)},
+		{featureGates, nonEmpty(k.FeatureGates)},
+		{storageBackend, nonEmpty(k.StorageBackend)},
+		{"etcd-servers", k.EtcdServers},
+		{etcdCAFile, nonEmpty(k.EtcdCAFile)},
+		{etcdCertFile, nonEmpty(k.EtcdCertFile)},
+		{etcdKeyFile, nonEmpty(k.EtcdKeyFile)},
+		{"admission-control", k.AdmissionControl},
+		{authorizationMode, nonEmpty(k.AuthorizationMode)},
+		{authzWebhookFile, nonEmpty(k.AuthorizationWebhookFile)},
+		{auditLogPath, nonEmpty(k.AuditLogPath)},
+		{podNetworkCIDR, nonEmpty(k.PodCIDR)},
+		{serviceClusterIPRange, nonEmpty(k.ServiceCIDR)},
+		{dnsIP, dnsIPs},
+		{proxyMode, nonEmpty(k.ProxyMode)},
+		{masqueradeAll, isTrue(k.MasqueradeAll)},
+		{clusterCIDR, nonEmpty(k.ClusterCIDR)},
+		{"extra-config", k.ExtraConfig},
+		{createMount, isTrue(c.Mount != "")},
+		{mountString, nonEmpty(c.Mount)},
+	}
+	for _, v := range values {
+		if flags.Changed(v.flag) || viper.InConfig(v.flag) {
+			continue
+		}
+		// The first value replaces the default of slice flags, the others are appended
+		for _, value := range v.values {
+			if err := flags.Set(v.flag, value); err != nil {
+				return errors.Wrapf(err, "Error setting --%s to %q", v.flag, value)
+			}
+		}
+	}
+	if len(k.StorageClasses) > 0 && !viper.InConfig(constants.StorageClassesConfig) {
+		viper.Set(constants.StorageClassesConfig, c.GetStorageClasses())
+	}
+	return nil
+}
+
+func nonEmpty(s string) []string {
+	if s == "" {
+		return nil
+	}
+	return []string{s}
+}
+
+func nonZero(i int) []string {
+	if i == 0 {
+		return nil
+	}
+	return []string{strconv.Itoa(i)}
+}
+
+func isTrue(b bool) []string {
+	if !b {
+		return nil
+	}
+	return []string{"true"}
+}
+
+// applyClusterSpecAddons enables and disables the addons of a spec in the minikube config. The
+// addons of a running cluster are also installed or deleted, those of other clusters are when the
+// cluster is started.
+func applyClusterSpecAddons(api libmachine.API, addons map[string]bool) error {
+	status, err := cluster.GetHostStatus(api)
+	if err != nil {
+		return errors.Wrap(err, "Error getting machine status")
+	}
+	m, err := cfg.ReadConfig()
+	if err != nil {
+		return err
+	}
+	changed := false
+	for name, enabled := range addons {
+		current, err := assets.Addons[name].IsEnabled()
+		if err != nil {
+			return errors.Wrapf(err, "Error checking if %s is enabled", name)
+		}
+		if current == enabled {
+			continue
+		}
+		if status == state.Running.String() {
+			if err := configCmd.Set(name, strconv.FormatBool(enabled)); err != nil {
+				return errors.Wrapf(err, "Error setting %s", name)
+			}
+			continue
+		}
+		m[name] = enabled
+		changed = true
+	}
+	if !changed {
+		return nil
+	}
+	return configCmd.WriteConfig(m)
+}
+
+// saveClusterSpec saves the spec of the started cluster for minikube export.
+func saveClusterSpec(config cluster.MachineConfig, kubernetesConfig cluster.KubernetesConfig) error {
+	addons := map[string]bool{}
+	for name, addon := range assets.Addons {
+		enabled, err := addon.IsEnabled()
+		if err != nil {
+			return errors.Wrapf(err, "Error checking if %s is enabled", name)
+		}
+		addons[name] = enabled
+	}
+	spec := clusterspec.New(config, kubernetesConfig, addons, getMountString())
+	return clusterspec.Save(cfg.GetMachineName(), spec)
+}
+
+// getMountString returns the directories minikube start mounts, or "" if it doesn't.
+func getMountString() string {
+	if !viper.GetBool(createMount) {
+		return ""
+	}
+	return viper.GetString(mountString)
+}
+
+// checkClusterConfigDrift prints the settings that differ from the ones the cluster was last
+// started with. It exits if settings that are only applied when the cluster is created changed,
+// like the settings of the VM.
+func checkClusterConfigDrift(api libmachine.API, saved, current *clusterspec.Cluster) {
+	changes := clusterspec.Diff(saved, current)
+	if len(changes) == 0 {
+		return
+	}
+	exists, err := api.Exists(cfg.GetMachineName())
+	if err != nil {
+		glog.Errorln("Error checking if the machine exists:", err)
+		cmdUtil.MaybeReportErrorAndExit(err)
+	}
+	if !exists {
+		return
+	}
+
+	requiresDelete := false
+	fmt.Fprintf(os.Stderr, "These settings differ from the ones the %s cluster was last started with:\n", cfg.GetMachineName())
+	for _, c := range changes {
+		note := ""
+		if c.RequiresDelete {
+			note = " (requires minikube delete)"
+			requiresDelete = true
+		}
+		fmt.Fprintf(os.Stderr, "  %s: %s -> %s%s\n", c.Field, c.Old, c.New, note)
+		if c.Warning != "" {
+			fmt.Fprintf(os.Stderr, "    Warning: %s\n", c.Warning)
+		}
+	}
+	if requiresDelete {
+		fmt.Fprintf(os.Stderr, "The settings of the VM, the service cluster IP range and the DNS IP are only applied when the cluster is created. Run minikube delete to recreate the cluster with them, or start it without them.\n")
+		os.Exit(1)
+	}
+	fmt.Fprintln(os.Stderr, "They are applied as the cluster starts.")
+}
+
+// waitForCluster waits until the cluster is usable, and otherwise exits after printing what it
+// was waiting for and the status of the localkube components.
+func waitForCluster(api libmachine.API, kubeConfigFile string, timeout time.Duration) {
+	client, err := getClusterClient(kubeConfigFile)
+	if err != nil {
+		glog.Errorln("Error creating kubernetes client:", err)
+		cmdUtil.MaybeReportErrorAndExit(err)
+	}
+	err = cluster.WaitForCluster(client, timeout, os.Stdout)
+	if err == nil {
+		return
+	}
+	fmt.Fprintf(os.Stderr, "The cluster is not usable: %s\n", err)
+	if components, err := cluster.GetLocalkubeComponentStatus(api); err == nil {
+		fmt.Fprintln(os.Stderr, "\nLocalkube components:")
+		for _, c := range components {
+			fmt.Fprintf(os.Stderr, "  %s: %s\n", c.Name, c.Summary())
+		}
+	}
+	fmt.Fprintln(os.Stderr, "\nRun minikube logs to see the logs of the cluster components.")
+	os.Exit(1)
+}
+
+// getClusterClient returns a client of the minikube cluster, whether or not it is the current
+// context of the kubeconfig.
+func getClusterClient(kubeConfigFile string) (kubernetes.Interface, error) {
+	loadingRules := &clientcmd.ClientConfigLoadingRules{ExplicitPath: kubeConfigFile}
+	overrides := &clientcmd.ConfigOverrides{CurrentContext: cfg.GetMachineName()}
+	config, err := clientcmd.NewNonInteractiveDeferredLoadingClientConfig(loadingRules, overrides).ClientConfig()
+	if err != nil {
+		return nil, errors.Wrap(err, "Error loading kubeconfig")
+	}
+	return kubernetes.NewForConfig(config)
+}
+
+// getExtraSANs splits the comma separated extra apiserver SANs, which are read as a string so the
+// minikube config can set them too.
+func getExtraSANs(sans string) []string {
+	if sans == "" {
+		return nil
+	}
+	return strings.Split(sans, ",")
+}
+
+func isValidAuthorizationMode(mode string) bool {
+	for _, m := range constants.AuthorizationModes {
+		if m == mode {
+			return true
+		}
+	}
+	return false
+}
+
 func calculateDiskSizeInMB(humanReadableDiskSize string) int {
 	diskSize, err := units.FromHumanSize(humanReadableDiskSize)
 	if err != nil {
@@ -298,13 +779,32 @@ func init() {
 	startCmd.Flags().StringArrayVar(&dockerEnv, "docker-env", nil, "Environment variables to pass to the Docker daemon. (format: key=value)")
 	startCmd.Flags().StringArrayVar(&dockerOpt, "docker-opt", nil, "Specify arbitrary flags to pass to the Docker daemon. (format: key=value)")
 	startCmd.Flags().String(apiServerName, constants.APIServerName, "The apiserver name which is used in the generated certificate for localkube/kubernetes.  This can be used if you want to make the apiserver available from outside the machine")
+	startCmd.Flags().String(apiServerExtraSANs, "", "A comma separated list of extra hostnames and IPs the apiserver certificate is valid for, e.g. localhost,127.0.0.1 to reach the apiserver through a tunnel or port-forward")
 	startCmd.Flags().String(dnsDomain, constants.ClusterDNSDomain, "The cluster dns domain name used in the kubernetes cluster")
-	startCmd.Flags().StringSliceVar(&insecureRegistry, "insecure-registry", []string{pkgutil.DefaultInsecureRegistry}, "Insecure Docker registries to pass to the Docker daemon")
+	startCmd.Flags().StringSliceVar(&insecureRegistry, "insecure-registry", []string{pkgutil.DefaultInsecureRegistry}, "Insecure Docker registries to pass to the Docker daemon. Defaults to the service cluster IP range")
 	startCmd.Flags().StringSliceVar(&registryMirror, "registry-mirror", nil, "Registry mirrors to pass to the Docker daemon")
 	startCmd.Flags().String(kubernetesVersion, constants.DefaultKubernetesVersion, "The kubernetes version that the minikube VM will use (ex: v1.2.3) \n OR a URI which contains a localkube binary (ex: https://storage.googleapis.com/minikube/k8sReleases/v1.3.0/localkube-linux-amd64)")
 	startCmd.Flags().String(containerRuntime, "", "The container runtime to be used")
 	startCmd.Flags().String(networkPlugin, "", "The name of the network plugin")
 	startCmd.Flags().String(featureGates, "", "A set of key=value pairs that describe feature gates for alpha/experimental features.")
+	startCmd.Flags().String(storageBackend, constants.DefaultStorageBackend, fmt.Sprintf("The etcd storage backend the apiserver uses, one of %v. Switching an existing cluster to etcd3 copies its etcd2 data", constants.StorageBackends))
+	startCmd.Flags().StringSliceVar(&etcdServers, "etcd-servers", nil, "External etcd servers the apiserver uses instead of the etcd embedded in localkube, e.g. https://192.168.99.1:2379")
+	startCmd.Flags().String(etcdCAFile, "", "The CA file used to verify the external etcd servers. It is copied into the VM")
+	startCmd.Flags().String(etcdCertFile, "", "The client certificate file used to authenticate to the external etcd servers. It is copied into the VM")
+	startCmd.Flags().String(etcdKeyFile, "", "The client key file used to authenticate to the external etcd servers. It is copied into the VM")
+	startCmd.Flags().StringSliceVar(&admissionControl, "admission-control", nil, "The admission plugins the apiserver runs, in order. Defaults to NamespaceLifecycle,LimitRanger,ServiceAccount,DefaultStorageClass,ResourceQuota")
+	startCmd.Flags().String(authorizationMode, constants.DefaultAuthorizationMode, fmt.Sprintf("A comma separated list of the authorization modes of the apiserver, in order, from %v. With RBAC, the minikube client certificate is bound to cluster-admin", constants.AuthorizationModes))
+	startCmd.Flags().String(authzWebhookFile, "", "The kubeconfig file of the webhook used with --authorization-mode=Webhook. It is copied into the VM")
+	startCmd.Flags().String(auditLogPath, "", "The file in the VM the apiserver writes its audit log to, e.g. /var/lib/localkube/audit/audit.log. Auditing is disabled unless it is set")
+	startCmd.Flags().String(podNetworkCIDR, pkgutil.DefaultPodCIDR, "The CIDR of the pod network. Must not overlap the service cluster IP range or the host-only network")
+	startCmd.Flags().String(serviceClusterIPRange, pkgutil.DefaultServiceCIDR, "The CIDR services are assigned cluster IPs from. Must not overlap the pod network or the host-only network")
+	startCmd.Flags().String(dnsIP, "", "The cluster IP of the kube-dns service, in the service cluster IP range. Defaults to the tenth IP of --service-cluster-ip-range")
+	startCmd.Flags().String(proxyMode, pkgutil.DefaultProxyMode, fmt.Sprintf("The mode of kube-proxy, one of %v", pkgutil.ProxyModes))
+	startCmd.Flags().Bool(masqueradeAll, false, "If kube-proxy SNATs all traffic sent via service cluster IPs, rather than only traffic from outside the cluster CIDR")
+	startCmd.Flags().String(clusterCIDR, "", "The CIDR of the pods in the cluster, which kube-proxy uses to tell traffic from outside the cluster, and masquerades. Defaults to --pod-network-cidr, or to the /16 around it that the pod CIDRs of nodes are carved from once the cluster has nodes")
+	startCmd.Flags().Bool(waitUntilUsable, false, "Wait until the apiserver is healthy, the node is Ready, the default service account exists and the pods of the enabled addons are Ready")
+	startCmd.Flags().Duration(waitTimeout, 6*time.Minute, "How long --wait waits for the cluster to be usable before failing")
+	startCmd.Flags().String(fromFile, "", "A YAML or JSON cluster spec to start the cluster with, as written by minikube export. Flags set on the command line or in the minikube config take precedence over it")
 	startCmd.Flags().Var(&extraOptions, "extra-config",
 		`A set of key=value pairs that describe configuration that may be passed to different components.
 		The key should be '.' separated, and the first part before the dot is the component to apply the configuration to.
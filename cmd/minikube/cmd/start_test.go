@@ -0,0 +1,179 @@
+/*
+Copyright 2016 The Kubernetes Authors All rights reserved.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package cmd
+
+import (
+	"io/ioutil"
+	"os"
+	"reflect"
+	"testing"
+
+	"github.com/spf13/pflag"
+	"github.com/spf13/viper"
+	"k8s.io/minikube/pkg/minikube/clusterspec"
+	"k8s.io/minikube/pkg/util"
+)
+
+func TestValidateAuthorizationFlags(t *testing.T) {
+	f, err := ioutil.TempFile("", "webhook")
+	if err != nil {
+		t.Fatalf("Error creating temp file: %s", err)
+	}
+	f.Close()
+	defer os.Remove(f.Name())
+
+	var tests = []struct {
+		mode        string
+		webhookFile string
+		shouldErr   bool
+	}{
+		{mode: "AlwaysAllow"},
+		{mode: "RBAC"},
+		{mode: "Node,RBAC"},
+		{mode: "RBAC,Webhook", webhookFile: f.Name()},
+		{mode: "Webhook", shouldErr: true},
+		{mode: "Webhook", webhookFile: f.Name() + ".missing", shouldErr: true},
+		{mode: "RBAC", webhookFile: f.Name(), shouldErr: true},
+		{mode: "ABAC", shouldErr: true},
+		{mode: "", shouldErr: true},
+	}
+
+	for _, test := range tests {
+		t.Run(test.mode, func(t *testing.T) {
+			err := validateAuthorizationFlags(test.mode, test.webhookFile)
+			if err != nil && !test.shouldErr {
+				t.Errorf("Unexpected error: %s", err)
+			}
+			if err == nil && test.shouldErr {
+				t.Errorf("Expected an error")
+			}
+		})
+	}
+}
+
+func TestValidateNetworkFlags(t *testing.T) {
+	var tests = []struct {
+		description string
+		podCIDR     string
+		serviceCIDR string
+		dnsIP       string
+		hostOnly    string
+		driver      string
+		shouldErr   bool
+	}{
+		{"defaults", "10.180.1.0/24", "10.0.0.0/24", "10.0.0.10", "192.168.99.1/24", "virtualbox", false},
+		{"custom", "172.30.0.0/16", "172.31.0.0/24", "172.31.0.53", "192.168.99.1/24", "virtualbox", false},
+		{"ranges-overlap", "10.0.0.0/16", "10.0.0.0/24", "10.0.0.10", "192.168.99.1/24", "virtualbox", true},
+		{"dns-outside-range", "10.180.1.0/24", "10.0.0.0/24", "10.0.1.10", "192.168.99.1/24", "virtualbox", true},
+		{"dns-kubernetes-ip", "10.180.1.0/24", "10.0.0.0/24", "10.0.0.1", "192.168.99.1/24", "virtualbox", true},
+		{"host-only-overlap", "192.168.0.0/16", "10.0.0.0/24", "10.0.0.10", "192.168.99.1/24", "virtualbox", true},
+		{"host-only-other-driver", "192.168.0.0/16", "10.0.0.0/24", "10.0.0.10", "192.168.99.1/24", "kvm", false},
+		{"invalid-pod-cidr", "10.180.1.0", "10.0.0.0/24", "10.0.0.10", "192.168.99.1/24", "virtualbox", true},
+		{"invalid-dns-ip", "10.180.1.0/24", "10.0.0.0/24", "dns", "192.168.99.1/24", "virtualbox", true},
+	}
+
+	for _, test := range tests {
+		t.Run(test.description, func(t *testing.T) {
+			err := validateNetworkFlags(test.podCIDR, test.serviceCIDR, test.dnsIP, test.hostOnly, test.driver)
+			if err != nil && !test.shouldErr {
+				t.Errorf("Unexpected error: %s", err)
+			}
+			if err == nil && test.shouldErr {
+				t.Errorf("Expected an error")
+			}
+		})
+	}
+}
+
+func TestApplyClusterSpec(t *testing.T) {
+	var registries []string
+	var options util.ExtraOptionSlice
+	flags := pflag.NewFlagSet("start", pflag.ContinueOnError)
+	flags.Int(memory, 2048, "")
+	flags.Int(cpus, 2, "")
+	flags.String(vmDriver, "virtualbox", "")
+	flags.StringSliceVar(&registries, "insecure-registry", []string{util.DefaultInsecureRegistry}, "")
+	flags.Var(&options, "extra-config", "")
+	flags.Bool(createMount, false, "")
+	flags.String(mountString, "", "")
+	if err := flags.Parse([]string{"--cpus=8"}); err != nil {
+		t.Fatalf("Error parsing flags: %s", err)
+	}
+
+	spec := &clusterspec.Cluster{
+		Machine:    clusterspec.Machine{Memory: 4096, CPUs: 4, InsecureRegistry: []string{"10.0.0.0/24", "registry:5000"}},
+		Kubernetes: clusterspec.Kubernetes{ExtraConfig: []string{"kubelet.MaxPods=50", "apiserver.EnableSwaggerUI=true"}},
+		Mount:      "/home/user:/data",
+	}
+	if err := applyClusterSpec(flags, spec); err != nil {
+		t.Fatalf("Error applying spec: %s", err)
+	}
+
+	for flag, expected := range map[string]string{
+		memory:      "4096",
+		cpus:        "8",
+		vmDriver:    "virtualbox",
+		createMount: "true",
+		mountString: "/home/user:/data",
+	} {
+		if value := flags.Lookup(flag).Value.String(); value != expected {
+			t.Errorf("Expected --%s to be %s, got %s", flag, expected, value)
+		}
+	}
+	if !reflect.DeepEqual(registries, spec.Machine.InsecureRegistry) {
+		t.Errorf("Expected insecure registries %v, got %v", spec.Machine.InsecureRegistry, registries)
+	}
+	if options.String() != "kubelet.MaxPods=50 apiserver.EnableSwaggerUI=true" {
+		t.Errorf("Unexpected extra config %s", options.String())
+	}
+}
+
+func TestApplyClusterSpecDNSIP(t *testing.T) {
+	defer viper.Reset()
+	spec := &clusterspec.Cluster{
+		Kubernetes: clusterspec.Kubernetes{ServiceCIDR: "172.31.0.0/24", DNSIP: "172.31.0.53"},
+	}
+
+	var tests = []struct {
+		description string
+		args        []string
+		expected    string
+	}{
+		{"saved range", nil, "172.31.0.53"},
+		{"same range", []string{"--service-cluster-ip-range=172.31.0.0/24"}, "172.31.0.53"},
+		{"new range", []string{"--service-cluster-ip-range=172.30.0.0/24"}, "172.30.0.10"},
+		{"new range and DNS IP", []string{"--service-cluster-ip-range=172.30.0.0/24", "--dns-ip=172.30.0.53"}, "172.30.0.53"},
+	}
+	for _, test := range tests {
+		t.Run(test.description, func(t *testing.T) {
+			viper.Reset()
+			flags := pflag.NewFlagSet("start", pflag.ContinueOnError)
+			flags.String(serviceClusterIPRange, util.DefaultServiceCIDR, "")
+			flags.String(dnsIP, "", "")
+			viper.BindPFlags(flags)
+			if err := flags.Parse(test.args); err != nil {
+				t.Fatalf("Error parsing flags: %s", err)
+			}
+			if err := applyClusterSpec(flags, spec); err != nil {
+				t.Fatalf("Error applying spec: %s", err)
+			}
+			if ip := getDNSIP(); ip != test.expected {
+				t.Errorf("Expected DNS IP %s, got %s", test.expected, ip)
+			}
+		})
+	}
+}
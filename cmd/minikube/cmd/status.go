@@ -29,15 +29,18 @@ import (
 	"k8s.io/minikube/pkg/minikube/config"
 	"k8s.io/minikube/pkg/minikube/constants"
 	"k8s.io/minikube/pkg/minikube/machine"
+	"k8s.io/minikube/pkg/util"
 	"k8s.io/minikube/pkg/util/kubeconfig"
 )
 
 var statusFormat string
 
 type Status struct {
-	MinikubeStatus   string
-	LocalkubeStatus  string
-	KubeconfigStatus string
+	MinikubeStatus      string
+	LocalkubeStatus     string
+	LocalkubeComponents []util.ComponentStatus
+	Nodes               []NodeStatus
+	KubeconfigStatus    string
 }
 
 // statusCmd represents the status command
@@ -61,12 +64,20 @@ var statusCmd = &cobra.Command{
 
 		ls := state.None.String()
 		ks := state.None.String()
+		var components []util.ComponentStatus
 		if ms == state.Running.String() {
 			ls, err = cluster.GetLocalkubeStatus(api)
 			if err != nil {
 				glog.Errorln("Error localkube status:", err)
 				cmdUtil.MaybeReportErrorAndExit(err)
 			}
+			// A paused localkube can't serve its component status
+			if ls != state.Stopped.String() && ls != constants.LocalkubePaused {
+				components, err = cluster.GetLocalkubeComponentStatus(api)
+				if err != nil {
+					glog.Infoln("Error localkube component status:", err)
+				}
+			}
 			ip, err := cluster.GetHostDriverIP(api)
 			if err != nil {
 				glog.Errorln("Error host driver ip status:", err)
@@ -85,7 +96,12 @@ var statusCmd = &cobra.Command{
 			}
 		}
 
-		status := Status{ms, ls, ks}
+		nodes, err := getNodeStatuses(api)
+		if err != nil {
+			glog.Errorln("Error getting node status:", err)
+		}
+
+		status := Status{ms, ls, components, nodes, ks}
 
 		tmpl, err := template.New("status").Parse(statusFormat)
 		if err != nil {
@@ -97,6 +113,12 @@ var statusCmd = &cobra.Command{
 			glog.Errorln("Error executing status template:", err)
 			os.Exit(1)
 		}
+
+		expiring, err := cluster.GetExpiringCerts()
+		if err != nil {
+			glog.Errorln("Error checking certificate expiry:", err)
+		}
+		warnExpiringCerts(expiring)
 	},
 }
 
@@ -41,6 +41,16 @@ itself, leaving all files intact. The cluster can be started again with the "sta
 		}
 		defer api.Close()
 
+		nodes, err := cluster.GetNodes()
+		if err != nil {
+			fmt.Println("Error getting nodes: ", err)
+		}
+		for _, n := range nodes {
+			if err := cluster.StopNode(api, n.Name); err != nil {
+				fmt.Printf("Error stopping node %s: %s\n", n.Name, err)
+			}
+		}
+
 		if err = cluster.StopHost(api); err != nil {
 			fmt.Println("Error stopping machine: ", err)
 			cmdUtil.MaybeReportErrorAndExit(err)
@@ -0,0 +1,164 @@
+/*
+Copyright 2016 The Kubernetes Authors All rights reserved.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package cmd
+
+import (
+	"fmt"
+	"os"
+	"text/template"
+	"time"
+
+	"github.com/golang/glog"
+	"github.com/spf13/cobra"
+	corev1 "k8s.io/client-go/kubernetes/typed/core/v1"
+	cmdUtil "k8s.io/minikube/cmd/util"
+	"k8s.io/minikube/pkg/minikube/constants"
+	"k8s.io/minikube/pkg/minikube/service"
+	"k8s.io/minikube/pkg/minikube/volume"
+)
+
+var (
+	volumeNamespace          string
+	volumeStorageClass       string
+	volumeSnapshotListFormat string
+)
+
+// VolumeSnapshotListTemplate holds the values available to the volume snapshot list format.
+type VolumeSnapshotListTemplate struct {
+	Name         string
+	Namespace    string
+	Claim        string
+	StorageClass string
+	Capacity     string
+	Time         string
+}
+
+// volumeCmd represents the volume command
+var volumeCmd = &cobra.Command{
+	Use:   "volume",
+	Short: "Snapshots and restores the volumes of persistent volume claims",
+	Long: `Snapshots and restores the volumes that the storage provisioner created for persistent volume claims.
+Snapshots are kept in the VM, in the .snapshots directory of the storage provisioner's directory.`,
+	Run: func(cmd *cobra.Command, args []string) {
+		cmd.Help()
+	},
+}
+
+var volumeSnapshotCmd = &cobra.Command{
+	Use:   "snapshot CLAIM [NAME]",
+	Short: "Takes a snapshot of the volume of a persistent volume claim",
+	Long: `Takes a snapshot of the volume of a persistent volume claim. Without a name the snapshot is named
+after the claim and the current time. Pods writing to the volume should be stopped first, as the
+files are copied one by one.`,
+	Run: func(cmd *cobra.Command, args []string) {
+		if len(args) < 1 || len(args) > 2 {
+			fmt.Fprintln(os.Stderr, "usage: minikube volume snapshot CLAIM [NAME]")
+			os.Exit(1)
+		}
+		name := args[0] + "-" + time.Now().Format(snapshotNameFormat)
+		if len(args) == 2 {
+			name = args[1]
+		}
+
+		client := getVolumeClient()
+		fmt.Printf("Taking snapshot %s of claim %s...\n", name, args[0])
+		if err := volume.TakeSnapshot(client, volumeNamespace, args[0], name); err != nil {
+			glog.Errorln("Error taking volume snapshot:", err)
+			cmdUtil.MaybeReportErrorAndExit(err)
+		}
+		fmt.Printf("Took snapshot %s\n", name)
+	},
+}
+
+var volumeRestoreCmd = &cobra.Command{
+	Use:   "restore NAME CLAIM",
+	Short: "Restores a volume snapshot into a new persistent volume claim",
+	Long: `Creates a new persistent volume claim whose volume is provisioned with the contents of a snapshot.
+The claim requests the capacity, access modes and storage class of the claim the snapshot was taken of.`,
+	Run: func(cmd *cobra.Command, args []string) {
+		if len(args) != 2 {
+			fmt.Fprintln(os.Stderr, "usage: minikube volume restore NAME CLAIM")
+			os.Exit(1)
+		}
+
+		client := getVolumeClient()
+		if err := volume.RestoreSnapshot(client, args[0], volumeNamespace, args[1], volumeStorageClass); err != nil {
+			glog.Errorln("Error restoring volume snapshot:", err)
+			cmdUtil.MaybeReportErrorAndExit(err)
+		}
+		fmt.Printf("Created claim %s from snapshot %s\n", args[1], args[0])
+	},
+}
+
+var volumeListCmd = &cobra.Command{
+	Use:   "list",
+	Short: "Lists the volume snapshots",
+	Long:  `Lists the volume snapshots, oldest first.`,
+	Run: func(cmd *cobra.Command, args []string) {
+		client := getVolumeClient()
+		snapshots, err := volume.ListSnapshots(client)
+		if err != nil {
+			glog.Errorln("Error listing volume snapshots:", err)
+			cmdUtil.MaybeReportErrorAndExit(err)
+		}
+		tmpl, err := template.New("list").Parse(volumeSnapshotListFormat)
+		if err != nil {
+			glog.Errorln("Error creating list template:", err)
+			os.Exit(1)
+		}
+		for _, s := range snapshots {
+			t := VolumeSnapshotListTemplate{
+				Name:         s.Name,
+				Namespace:    s.Namespace,
+				Claim:        s.Claim,
+				StorageClass: s.StorageClass,
+				Capacity:     s.Capacity,
+				Time:         s.Time.Format(time.RFC1123),
+			}
+			if err := tmpl.Execute(os.Stdout, t); err != nil {
+				glog.Errorln("Error executing list template:", err)
+				os.Exit(1)
+			}
+		}
+	},
+}
+
+// getVolumeClient returns a client for the cluster, exiting if minikube is not running.
+func getVolumeClient() corev1.CoreV1Interface {
+	api := loadRunningAPI()
+	api.Close()
+
+	client, err := (&service.K8sClientGetter{}).GetCoreClient()
+	if err != nil {
+		glog.Errorln("Error getting kubernetes client:", err)
+		os.Exit(1)
+	}
+	return client
+}
+
+func init() {
+	volumeSnapshotCmd.Flags().StringVarP(&volumeNamespace, "namespace", "n", "default", "The namespace of the claim")
+	volumeRestoreCmd.Flags().StringVarP(&volumeNamespace, "namespace", "n", "default", "The namespace to create the claim in")
+	volumeRestoreCmd.Flags().StringVar(&volumeStorageClass, "storage-class", "", "The storage class of the new claim, instead of the class of the claim the snapshot was taken of")
+	volumeListCmd.Flags().StringVar(&volumeSnapshotListFormat, "format", constants.DefaultVolumeSnapshotListFormat,
+		`Go template format string for the volume snapshot list output.  The format for Go templates can be found here: https://golang.org/pkg/text/template/
+For the list of accessible variables for the template, see the struct values here: https://godoc.org/k8s.io/minikube/cmd/minikube/cmd#VolumeSnapshotListTemplate`)
+	volumeCmd.AddCommand(volumeSnapshotCmd)
+	volumeCmd.AddCommand(volumeRestoreCmd)
+	volumeCmd.AddCommand(volumeListCmd)
+	RootCmd.AddCommand(volumeCmd)
+}
@@ -0,0 +1,85 @@
+// +build ignore
+
+/*
+Copyright 2016 The Kubernetes Authors All rights reserved.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+// gen_extra_config_fields writes the fields --extra-config can set on each localkube component to
+// fields.go in the current directory, for pkg/minikube/extraconfig. It is run by go generate, so
+// that minikube can validate extra config without linking in the Kubernetes servers.
+package main
+
+import (
+	"bytes"
+	"fmt"
+	"go/format"
+	"io/ioutil"
+	"os"
+	"reflect"
+	"sort"
+
+	"k8s.io/minikube/pkg/minikube/extraconfig/configtypes"
+	"k8s.io/minikube/pkg/util"
+)
+
+func main() {
+	components := []string{}
+	for c := range configtypes.Components {
+		components = append(components, c)
+	}
+	sort.Strings(components)
+
+	buf := bytes.Buffer{}
+	fmt.Fprint(&buf, `// Code generated by hack/gen_extra_config_fields.go. DO NOT EDIT.
+
+package extraconfig
+
+import (
+	"reflect"
+
+	"k8s.io/minikube/pkg/util"
+)
+
+// componentFields are the fields the extra config of each localkube component can set.
+var componentFields = map[string][]util.FieldPath{
+`)
+	for _, c := range components {
+		fmt.Fprintf(&buf, "%q: {\n", c)
+		for _, f := range util.ListFields(configtypes.Components[c]) {
+			fmt.Fprintf(&buf, "{Path: %q, Type: %q, Kind: reflect.%s},\n", f.Path, f.Type, kindName(f.Kind))
+		}
+		fmt.Fprint(&buf, "},\n")
+	}
+	fmt.Fprint(&buf, "}\n")
+
+	src, err := format.Source(buf.Bytes())
+	if err != nil {
+		fmt.Println(err)
+		os.Exit(1)
+	}
+	if err := ioutil.WriteFile("fields.go", src, 0644); err != nil {
+		fmt.Println(err)
+		os.Exit(1)
+	}
+}
+
+// kindName returns the name of the reflect constant of kind k, e.g. Int32 for int32.
+func kindName(k reflect.Kind) string {
+	s := k.String()
+	if s == "" {
+		return s
+	}
+	return string(s[0]-'a'+'A') + s[1:]
+}
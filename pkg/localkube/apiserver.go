@@ -22,7 +22,6 @@ import (
 	"strconv"
 
 	apiserveroptions "k8s.io/apiserver/pkg/server/options"
-	"k8s.io/apiserver/pkg/storage/storagebackend"
 
 	apiserver "k8s.io/kubernetes/cmd/kube-apiserver/app"
 	"k8s.io/kubernetes/cmd/kube-apiserver/app/options"
@@ -33,7 +32,7 @@ func (lk LocalkubeServer) NewAPIServer() Server {
 	return NewSimpleServer("apiserver", serverInterval, StartAPIServer(lk), readyFunc(lk))
 }
 
-func StartAPIServer(lk LocalkubeServer) func() error {
+func StartAPIServer(lk LocalkubeServer) func(stop <-chan struct{}) error {
 	config := options.NewServerRunOptions()
 
 	config.SecureServing.BindAddress = lk.APIServerAddress
@@ -46,17 +45,24 @@ func StartAPIServer(lk LocalkubeServer) func() error {
 
 	config.SecureServing.ServerCert.CertKey.CertFile = lk.GetPublicKeyCertPath()
 	config.SecureServing.ServerCert.CertKey.KeyFile = lk.GetPrivateKeyCertPath()
-	config.Admission.PluginNames = []string{
-		"NamespaceLifecycle",
-		"LimitRanger",
-		"ServiceAccount",
-		"DefaultStorageClass",
-		"ResourceQuota",
+	config.Admission.PluginNames = lk.AdmissionControl
+	config.Authorization.Mode = lk.AuthorizationMode
+	config.Authorization.WebhookConfigFile = lk.AuthorizationWebhookFile
+
+	config.Audit.LogOptions.Path = lk.AuditLogPath
+	config.Audit.LogOptions.MaxAge = lk.AuditLogMaxAge
+	config.Audit.LogOptions.MaxBackups = lk.AuditLogMaxBackups
+	config.Audit.LogOptions.MaxSize = lk.AuditLogMaxSize
+	if lk.AuditLogPath != "" && advancedAuditingEnabled() {
+		config.Audit.PolicyFile = lk.GetAuditPolicyPath()
 	}
-	// use localkube etcd
+	// use localkube etcd, or the external etcd if one was set
 
-	config.Etcd.StorageConfig.ServerList = KubeEtcdClientURLs
-	config.Etcd.StorageConfig.Type = storagebackend.StorageTypeETCD2
+	config.Etcd.StorageConfig.ServerList = lk.GetEtcdServerList()
+	config.Etcd.StorageConfig.Type = lk.StorageBackend
+	config.Etcd.StorageConfig.CAFile = lk.EtcdCAFile
+	config.Etcd.StorageConfig.CertFile = lk.EtcdCertFile
+	config.Etcd.StorageConfig.KeyFile = lk.EtcdKeyFile
 
 	// set Service IP range
 	config.ServiceClusterIPRange = lk.ServiceClusterIPRange
@@ -77,8 +83,7 @@ func StartAPIServer(lk LocalkubeServer) func() error {
 
 	lk.SetExtraConfigForComponent("apiserver", &config)
 
-	return func() error {
-		stop := make(chan struct{})
+	return func(stop <-chan struct{}) error {
 		return apiserver.Run(config, stop)
 	}
 }
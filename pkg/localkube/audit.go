@@ -0,0 +1,144 @@
+/*
+Copyright 2016 The Kubernetes Authors All rights reserved.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package localkube
+
+import (
+	"io"
+	"io/ioutil"
+	"net/http"
+	"os"
+	"path"
+	"path/filepath"
+	"sort"
+	"strings"
+
+	"github.com/golang/glog"
+	"github.com/pkg/errors"
+	"k8s.io/apiserver/pkg/features"
+	"k8s.io/apiserver/pkg/util/feature"
+)
+
+// defaultAuditPolicyFile is the name of the default audit policy in the localkube directory.
+const defaultAuditPolicyFile = "audit-policy.yaml"
+
+// defaultAuditPolicy logs the metadata of every request, except for the health checks, events
+// and leader election updates that would otherwise make up most of the log.
+const defaultAuditPolicy = `apiVersion: audit.k8s.io/v1alpha1
+kind: Policy
+rules:
+- level: None
+  nonResourceURLs:
+  - /healthz*
+  - /version
+  - /swagger*
+- level: None
+  resources:
+  - group: ""
+    resources: ["events"]
+- level: None
+  namespaces: ["kube-system"]
+  verbs: ["get", "update"]
+  resources:
+  - group: ""
+    resources: ["endpoints"]
+- level: Metadata
+`
+
+// GetAuditPolicyPath returns the audit policy file, or the default policy written by SetupAudit.
+func (lk LocalkubeServer) GetAuditPolicyPath() string {
+	if lk.AuditPolicyFile != "" {
+		return lk.AuditPolicyFile
+	}
+	return path.Join(lk.LocalkubeDirectory, defaultAuditPolicyFile)
+}
+
+// SetupAudit enables the AdvancedAuditing feature, unless it was set with --feature-gates, and
+// writes the default audit policy if no policy file was given. Without AdvancedAuditing the
+// apiserver logs every request in the legacy format and the policy is not used. Auditing is off
+// unless AuditLogPath is set, in which case SetupAudit does nothing.
+func (lk LocalkubeServer) SetupAudit() error {
+	if lk.AuditLogPath == "" {
+		return nil
+	}
+	if !strings.Contains(lk.FeatureGates, string(features.AdvancedAuditing)) {
+		if err := feature.DefaultFeatureGate.Set(string(features.AdvancedAuditing) + "=true"); err != nil {
+			return errors.Wrap(err, "Error enabling advanced auditing")
+		}
+	}
+	if lk.AuditPolicyFile != "" {
+		if _, err := os.Stat(lk.AuditPolicyFile); err != nil {
+			return errors.Wrap(err, "Error reading --audit-policy-file")
+		}
+		return nil
+	}
+	if err := os.MkdirAll(lk.LocalkubeDirectory, 0755); err != nil {
+		return errors.Wrap(err, "Error creating localkube directory")
+	}
+	if err := ioutil.WriteFile(lk.GetAuditPolicyPath(), []byte(defaultAuditPolicy), 0644); err != nil {
+		return errors.Wrap(err, "Error writing default audit policy")
+	}
+	return nil
+}
+
+func advancedAuditingEnabled() bool {
+	return feature.DefaultFeatureGate.Enabled(features.AdvancedAuditing)
+}
+
+// getAuditLogFiles returns the rotated audit logs, oldest first, followed by the current one.
+// Rotated logs are named after the audit log with the time they were rotated inserted before the
+// extension, so sorting them by name sorts them by time.
+func (lk LocalkubeServer) getAuditLogFiles() ([]string, error) {
+	ext := filepath.Ext(lk.AuditLogPath)
+	prefix := strings.TrimSuffix(lk.AuditLogPath, ext)
+	rotated, err := filepath.Glob(prefix + "-*" + ext)
+	if err != nil {
+		return nil, err
+	}
+	sort.Strings(rotated)
+	return append(rotated, lk.AuditLogPath), nil
+}
+
+// AuditLogHandler serves the audit log, including the rotated logs that are still kept, oldest
+// first. It responds with StatusNotFound when auditing is off.
+func (lk LocalkubeServer) AuditLogHandler() http.Handler {
+	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if lk.AuditLogPath == "" {
+			http.Error(w, "auditing is disabled", http.StatusNotFound)
+			return
+		}
+		files, err := lk.getAuditLogFiles()
+		if err != nil {
+			http.Error(w, err.Error(), http.StatusInternalServerError)
+			return
+		}
+		w.Header().Set("Content-Type", "text/plain")
+		for _, name := range files {
+			f, err := os.Open(name)
+			if os.IsNotExist(err) {
+				continue
+			}
+			if err != nil {
+				glog.Errorf("Error reading audit log %s: %s", name, err)
+				continue
+			}
+			if _, err := io.Copy(w, f); err != nil {
+				glog.Errorf("Error serving audit log %s: %s", name, err)
+			}
+			f.Close()
+		}
+	})
+}
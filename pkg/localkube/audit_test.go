@@ -0,0 +1,107 @@
+/*
+Copyright 2016 The Kubernetes Authors All rights reserved.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package localkube
+
+import (
+	"io/ioutil"
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"path/filepath"
+	"testing"
+
+	"k8s.io/apiserver/pkg/audit/policy"
+	"k8s.io/minikube/pkg/util"
+)
+
+func TestSetupAuditDefaultPolicy(t *testing.T) {
+	dir, err := ioutil.TempDir("", "localkube")
+	if err != nil {
+		t.Fatalf("Error creating temp dir: %s", err)
+	}
+	defer os.RemoveAll(dir)
+
+	lk := LocalkubeServer{LocalkubeDirectory: dir, AuditLogPath: filepath.Join(dir, "audit.log")}
+	if err := lk.SetupAudit(); err != nil {
+		t.Fatalf("Error setting up audit logging: %s", err)
+	}
+	if !advancedAuditingEnabled() {
+		t.Fatalf("Expected advanced auditing to be enabled")
+	}
+
+	p, err := policy.LoadPolicyFromFile(lk.GetAuditPolicyPath())
+	if err != nil {
+		t.Fatalf("Error loading default audit policy: %s", err)
+	}
+	if len(p.Rules) != 4 {
+		t.Fatalf("Expected 4 rules in the default audit policy, got %d", len(p.Rules))
+	}
+}
+
+func TestSetupAuditPolicyFile(t *testing.T) {
+	dir, err := ioutil.TempDir("", "localkube")
+	if err != nil {
+		t.Fatalf("Error creating temp dir: %s", err)
+	}
+	defer os.RemoveAll(dir)
+
+	lk := LocalkubeServer{
+		LocalkubeDirectory: dir,
+		AuditLogPath:       filepath.Join(dir, "audit.log"),
+		AuditPolicyFile:    filepath.Join(dir, "missing.yaml"),
+	}
+	if err := lk.SetupAudit(); err == nil {
+		t.Fatalf("Expected an error for a missing audit policy file")
+	}
+	if _, err := os.Stat(filepath.Join(dir, defaultAuditPolicyFile)); !os.IsNotExist(err) {
+		t.Fatalf("Expected the default audit policy not to be written")
+	}
+}
+
+func TestAuditLogHandler(t *testing.T) {
+	dir, err := ioutil.TempDir("", "localkube")
+	if err != nil {
+		t.Fatalf("Error creating temp dir: %s", err)
+	}
+	defer os.RemoveAll(dir)
+
+	logs := map[string]string{
+		"audit-2017-06-02T10-00-00.000.log": "second\n",
+		"audit-2017-06-01T10-00-00.000.log": "first\n",
+		"audit.log":                         "current\n",
+		"other.log":                         "other\n",
+	}
+	for name, contents := range logs {
+		if err := ioutil.WriteFile(filepath.Join(dir, name), []byte(contents), 0644); err != nil {
+			t.Fatalf("Error writing %s: %s", name, err)
+		}
+	}
+
+	lk := LocalkubeServer{AuditLogPath: filepath.Join(dir, "audit.log")}
+	rr := httptest.NewRecorder()
+	lk.AuditLogHandler().ServeHTTP(rr, httptest.NewRequest("GET", util.LocalkubeAuditPath, nil))
+	if expected := "first\nsecond\ncurrent\n"; rr.Body.String() != expected {
+		t.Errorf("Expected audit log %q, got %q", expected, rr.Body.String())
+	}
+
+	lk.AuditLogPath = ""
+	rr = httptest.NewRecorder()
+	lk.AuditLogHandler().ServeHTTP(rr, httptest.NewRequest("GET", util.LocalkubeAuditPath, nil))
+	if rr.Code != http.StatusNotFound {
+		t.Errorf("Expected status %d when auditing is disabled, got %d", http.StatusNotFound, rr.Code)
+	}
+}
@@ -0,0 +1,153 @@
+/*
+Copyright 2016 The Kubernetes Authors All rights reserved.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package localkube
+
+import (
+	"os"
+	"strings"
+	"sync/atomic"
+	"time"
+
+	"github.com/golang/glog"
+	"github.com/pkg/errors"
+	apierrors "k8s.io/apimachinery/pkg/api/errors"
+	meta_v1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/client-go/kubernetes"
+	rbacclient "k8s.io/client-go/kubernetes/typed/rbac/v1beta1"
+	rbac "k8s.io/client-go/pkg/apis/rbac/v1beta1"
+	"k8s.io/client-go/rest"
+	authzmodes "k8s.io/kubernetes/pkg/kubeapiserver/authorizer/modes"
+)
+
+const (
+	// minikubeRBACBinding grants cluster-admin to the user of the client certificate minikube
+	// generates, and to the default service account of kube-system, which the addons run as.
+	minikubeRBACBinding = "minikube-rbac"
+	// minikubeClientUser is the common name of the client certificate minikube generates.
+	minikubeClientUser = "minikube"
+
+	rbacResyncPeriod = time.Minute
+)
+
+// GetAuthorizationModes returns the authorization modes of the apiserver, in order.
+func (lk LocalkubeServer) GetAuthorizationModes() []string {
+	if lk.AuthorizationMode == "" {
+		return []string{}
+	}
+	return strings.Split(lk.AuthorizationMode, ",")
+}
+
+// RBACEnabled returns whether the apiserver authorizes requests with RBAC.
+func (lk LocalkubeServer) RBACEnabled() bool {
+	for _, mode := range lk.GetAuthorizationModes() {
+		if mode == authzmodes.ModeRBAC {
+			return true
+		}
+	}
+	return false
+}
+
+// ValidateAuthorization checks the authorization modes are known to the apiserver, and that the
+// webhook config file exists when the Webhook mode is used.
+func (lk LocalkubeServer) ValidateAuthorization() error {
+	webhook := false
+	for _, mode := range lk.GetAuthorizationModes() {
+		if !authzmodes.IsValidAuthorizationMode(mode) {
+			return errors.Errorf("Invalid authorization mode %q, must be one of %s", mode, strings.Join(authzmodes.AuthorizationModeChoices, ", "))
+		}
+		webhook = webhook || mode == authzmodes.ModeWebhook
+	}
+
+	if webhook != (lk.AuthorizationWebhookFile != "") {
+		return errors.New("--authorization-webhook-config-file must be set exactly when --authorization-mode includes Webhook")
+	}
+	if webhook {
+		if _, err := os.Stat(lk.AuthorizationWebhookFile); err != nil {
+			return errors.Wrap(err, "Error reading --authorization-webhook-config-file")
+		}
+	}
+	return nil
+}
+
+// NewRBACBootstrapServer creates a server that binds cluster-admin to the minikube client
+// certificate and the kube-system default service account, so kubectl and the addons keep
+// working with RBAC. The apiserver itself bootstraps the default roles and bindings.
+func (lk LocalkubeServer) NewRBACBootstrapServer() Server {
+	var bootstrapped int32
+	ready := func() bool {
+		return atomic.LoadInt32(&bootstrapped) == 1
+	}
+	return NewSimpleServer("rbac-bootstrap", serverInterval, StartRBACBootstrap(lk, &bootstrapped), ready, "apiserver")
+}
+
+// StartRBACBootstrap creates the minikube cluster role binding, sets bootstrapped once it exists,
+// and then recreates it whenever it is deleted, until it is stopped.
+func StartRBACBootstrap(lk LocalkubeServer, bootstrapped *int32) func(stop <-chan struct{}) error {
+	config := rest.Config{Host: lk.GetAPIServerInsecureURL()}
+	return func(stop <-chan struct{}) error {
+		clientset, err := kubernetes.NewForConfig(&config)
+		if err != nil {
+			return errors.Wrap(err, "Error creating client")
+		}
+
+		ticker := time.NewTicker(rbacResyncPeriod)
+		defer ticker.Stop()
+		for {
+			if err := ensureClusterRoleBinding(clientset.RbacV1beta1().ClusterRoleBindings(), minikubeClusterRoleBinding()); err != nil {
+				return err
+			}
+			atomic.StoreInt32(bootstrapped, 1)
+			select {
+			case <-stop:
+				return nil
+			case <-ticker.C:
+			}
+		}
+	}
+}
+
+func minikubeClusterRoleBinding() *rbac.ClusterRoleBinding {
+	return &rbac.ClusterRoleBinding{
+		ObjectMeta: meta_v1.ObjectMeta{Name: minikubeRBACBinding},
+		Subjects: []rbac.Subject{
+			{Kind: rbac.UserKind, APIGroup: rbac.GroupName, Name: minikubeClientUser},
+			{Kind: rbac.ServiceAccountKind, Namespace: meta_v1.NamespaceSystem, Name: "default"},
+		},
+		RoleRef: rbac.RoleRef{
+			APIGroup: rbac.GroupName,
+			Kind:     "ClusterRole",
+			Name:     "cluster-admin",
+		},
+	}
+}
+
+// ensureClusterRoleBinding creates binding if it does not exist. An existing binding is left
+// as it is, so it can be edited.
+func ensureClusterRoleBinding(client rbacclient.ClusterRoleBindingInterface, binding *rbac.ClusterRoleBinding) error {
+	_, err := client.Get(binding.Name, meta_v1.GetOptions{})
+	if err == nil {
+		return nil
+	}
+	if !apierrors.IsNotFound(err) {
+		return errors.Wrapf(err, "Error getting cluster role binding %s", binding.Name)
+	}
+	if _, err := client.Create(binding); err != nil && !apierrors.IsAlreadyExists(err) {
+		return errors.Wrapf(err, "Error creating cluster role binding %s", binding.Name)
+	}
+	glog.Infof("Created cluster role binding %s", binding.Name)
+	return nil
+}
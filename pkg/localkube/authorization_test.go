@@ -0,0 +1,119 @@
+/*
+Copyright 2016 The Kubernetes Authors All rights reserved.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package localkube
+
+import (
+	"encoding/json"
+	"io/ioutil"
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"sync"
+	"testing"
+
+	"k8s.io/client-go/kubernetes"
+	rbac "k8s.io/client-go/pkg/apis/rbac/v1beta1"
+	"k8s.io/client-go/rest"
+)
+
+func TestValidateAuthorization(t *testing.T) {
+	f, err := ioutil.TempFile("", "webhook")
+	if err != nil {
+		t.Fatalf("Error creating temp file: %s", err)
+	}
+	f.Close()
+	defer os.Remove(f.Name())
+
+	var tests = []struct {
+		mode        string
+		webhookFile string
+		shouldErr   bool
+		rbac        bool
+	}{
+		{mode: "AlwaysAllow"},
+		{mode: "RBAC", rbac: true},
+		{mode: "Node,RBAC", rbac: true},
+		{mode: "RBAC,Webhook", webhookFile: f.Name(), rbac: true},
+		{mode: "Webhook", shouldErr: true},
+		{mode: "Webhook", webhookFile: f.Name() + ".missing", shouldErr: true},
+		{mode: "AlwaysAllow", webhookFile: f.Name(), shouldErr: true},
+		{mode: "rbac", shouldErr: true},
+	}
+
+	for _, test := range tests {
+		t.Run(test.mode, func(t *testing.T) {
+			lk := LocalkubeServer{AuthorizationMode: test.mode, AuthorizationWebhookFile: test.webhookFile}
+			err := lk.ValidateAuthorization()
+			if err != nil && !test.shouldErr {
+				t.Errorf("Unexpected error: %s", err)
+			}
+			if err == nil && test.shouldErr {
+				t.Errorf("Expected an error")
+			}
+			if !test.shouldErr && lk.RBACEnabled() != test.rbac {
+				t.Errorf("Expected RBAC enabled to be %t", test.rbac)
+			}
+		})
+	}
+}
+
+func TestEnsureClusterRoleBinding(t *testing.T) {
+	var lock sync.Mutex
+	created := []rbac.ClusterRoleBinding{}
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		lock.Lock()
+		defer lock.Unlock()
+		w.Header().Set("Content-Type", "application/json")
+		switch r.Method {
+		case "GET":
+			if len(created) == 0 {
+				w.WriteHeader(http.StatusNotFound)
+				w.Write([]byte(`{"kind":"Status","apiVersion":"v1","status":"Failure","reason":"NotFound","code":404}`))
+				return
+			}
+			json.NewEncoder(w).Encode(created[0])
+		case "POST":
+			binding := rbac.ClusterRoleBinding{}
+			if err := json.NewDecoder(r.Body).Decode(&binding); err != nil {
+				t.Errorf("Error decoding cluster role binding: %s", err)
+			}
+			created = append(created, binding)
+			w.WriteHeader(http.StatusCreated)
+			json.NewEncoder(w).Encode(binding)
+		default:
+			t.Errorf("Unexpected request %s %s", r.Method, r.URL)
+		}
+	}))
+	defer server.Close()
+
+	clientset, err := kubernetes.NewForConfig(&rest.Config{Host: server.URL})
+	if err != nil {
+		t.Fatalf("Error creating client: %s", err)
+	}
+	for i := 0; i < 2; i++ {
+		if err := ensureClusterRoleBinding(clientset.RbacV1beta1().ClusterRoleBindings(), minikubeClusterRoleBinding()); err != nil {
+			t.Fatalf("Error ensuring cluster role binding: %s", err)
+		}
+	}
+
+	if len(created) != 1 {
+		t.Fatalf("Expected the cluster role binding to be created once, got %d", len(created))
+	}
+	if b := created[0]; b.Name != minikubeRBACBinding || b.RoleRef.Name != "cluster-admin" || len(b.Subjects) != 2 {
+		t.Fatalf("Unexpected cluster role binding %+v", b)
+	}
+}
@@ -22,7 +22,7 @@ import (
 )
 
 func (lk LocalkubeServer) NewControllerManagerServer() Server {
-	return NewSimpleServer("controller-manager", serverInterval, StartControllerManagerServer(lk), noop)
+	return newComponentServer("controller-manager", noop, "apiserver")
 }
 
 func StartControllerManagerServer(lk LocalkubeServer) func() error {
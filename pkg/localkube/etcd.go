@@ -17,16 +17,24 @@ limitations under the License.
 package localkube
 
 import (
+	"bufio"
 	"crypto/tls"
+	"errors"
 	"fmt"
 	"net"
 	"net/http"
+	"sync"
 	"time"
 
 	"github.com/coreos/etcd/etcdserver"
 	"github.com/coreos/etcd/etcdserver/api/v2http"
+	"github.com/coreos/etcd/etcdserver/api/v3rpc"
 	"github.com/coreos/etcd/pkg/transport"
 	"github.com/coreos/etcd/pkg/types"
+	"github.com/golang/glog"
+	"golang.org/x/net/http2"
+	"google.golang.org/grpc"
+	"k8s.io/apiserver/pkg/storage/storagebackend"
 )
 
 const (
@@ -46,6 +54,11 @@ type EtcdServer struct {
 	*etcdserver.EtcdServer
 	config          *etcdserver.ServerConfig
 	clientListeners []net.Listener
+	// serveV3 is set when the v3 gRPC API is served next to the v2 HTTP API
+	serveV3    bool
+	grpcServer *grpc.Server
+	// stopped is closed when Stop is called, so the listeners closing is not treated as an error
+	stopped chan struct{}
 }
 
 // NewEtcd creates a new default etcd Server using 'dataDir' for persistence. Panics if could not be configured.
@@ -83,7 +96,8 @@ func (lk LocalkubeServer) NewEtcd(clientURLStrs, peerURLStrs []string, name, dat
 	lk.SetExtraConfigForComponent(EtcdName, &config)
 
 	return &EtcdServer{
-		config: config,
+		config:  config,
+		serveV3: lk.StorageBackend == storagebackend.StorageTypeETCD3,
 	}, nil
 }
 
@@ -103,20 +117,52 @@ func (e *EtcdServer) Start() {
 	e.EtcdServer.Start()
 
 	// setup client listeners
+	e.stopped = make(chan struct{})
 	ch := v2http.NewClientHandler(e.EtcdServer, e.requestTimeout())
+	if e.serveV3 {
+		e.grpcServer = v3rpc.Server(e.EtcdServer, nil)
+	}
 	for _, l := range e.clientListeners {
+		if e.serveV3 {
+			var grpcListener net.Listener
+			grpcListener, l = splitListener(l)
+			go func() {
+				err := e.grpcServer.Serve(grpcListener)
+				if !e.isStopped() {
+					glog.Errorf("Error serving the etcd v3 API: %s", err)
+				}
+			}()
+		}
 		go func(l net.Listener) {
 			srv := &http.Server{
 				Handler:     ch,
 				ReadTimeout: 5 * time.Minute,
 			}
-			panic(srv.Serve(l))
+			err := srv.Serve(l)
+			if !e.isStopped() {
+				panic(err)
+			}
 		}(l)
 	}
 }
 
+func (e *EtcdServer) isStopped() bool {
+	select {
+	case <-e.stopped:
+		return true
+	default:
+		return false
+	}
+}
+
 // Stop closes all connections and stops the Etcd server
 func (e *EtcdServer) Stop() {
+	if e.stopped != nil {
+		close(e.stopped)
+	}
+	if e.grpcServer != nil {
+		e.grpcServer.Stop()
+	}
 	if e.EtcdServer != nil {
 		e.EtcdServer.Stop()
 	}
@@ -152,3 +198,100 @@ func createListenersOrPanic(urls types.URLs) (listeners []net.Listener) {
 	}
 	return listeners
 }
+
+var errListenerClosed = errors.New("listener closed")
+
+// splitTimeout is how long a new client connection has to send its first bytes.
+const splitTimeout = 10 * time.Second
+
+// splitListener hands the connections accepted by l that start with the HTTP/2 client preface,
+// which is what gRPC clients send, to the first listener returned and all others to the second.
+// This lets the v2 HTTP API and the v3 gRPC API share the etcd client URLs.
+func splitListener(l net.Listener) (grpcListener, httpListener net.Listener) {
+	g, h := newConnListener(l.Addr()), newConnListener(l.Addr())
+	go func() {
+		defer g.Close()
+		defer h.Close()
+		for {
+			conn, err := l.Accept()
+			if err != nil {
+				return
+			}
+			go func() {
+				c := &bufferedConn{Conn: conn, r: bufio.NewReader(conn)}
+				target := h
+				c.SetReadDeadline(time.Now().Add(splitTimeout))
+				if c.hasPrefix(http2.ClientPreface) {
+					target = g
+				}
+				c.SetReadDeadline(time.Time{})
+				target.hand(c)
+			}()
+		}
+	}()
+	return g, h
+}
+
+// bufferedConn is a connection whose first bytes can be inspected without consuming them.
+type bufferedConn struct {
+	net.Conn
+	r *bufio.Reader
+}
+
+func (c *bufferedConn) Read(b []byte) (int, error) {
+	return c.r.Read(b)
+}
+
+// hasPrefix reads only as much of the connection as is needed to tell whether it starts with prefix.
+func (c *bufferedConn) hasPrefix(prefix string) bool {
+	for n := 1; n <= len(prefix); n++ {
+		b, err := c.r.Peek(n)
+		if err != nil || b[n-1] != prefix[n-1] {
+			return false
+		}
+	}
+	return true
+}
+
+// connListener is a net.Listener for connections accepted elsewhere.
+type connListener struct {
+	addr      net.Addr
+	conns     chan net.Conn
+	closed    chan struct{}
+	closeOnce sync.Once
+}
+
+func newConnListener(addr net.Addr) *connListener {
+	return &connListener{
+		addr:   addr,
+		conns:  make(chan net.Conn),
+		closed: make(chan struct{}),
+	}
+}
+
+// hand passes c to the next call to Accept, or closes it if the listener is closed first.
+func (l *connListener) hand(c net.Conn) {
+	select {
+	case l.conns <- c:
+	case <-l.closed:
+		c.Close()
+	}
+}
+
+func (l *connListener) Accept() (net.Conn, error) {
+	select {
+	case c := <-l.conns:
+		return c, nil
+	case <-l.closed:
+		return nil, errListenerClosed
+	}
+}
+
+func (l *connListener) Close() error {
+	l.closeOnce.Do(func() { close(l.closed) })
+	return nil
+}
+
+func (l *connListener) Addr() net.Addr {
+	return l.addr
+}
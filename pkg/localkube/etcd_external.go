@@ -0,0 +1,31 @@
+/*
+Copyright 2016 The Kubernetes Authors All rights reserved.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package localkube
+
+// UseExternalEtcd returns whether the apiserver stores the cluster state in an external etcd
+// instead of the embedded one.
+func (lk LocalkubeServer) UseExternalEtcd() bool {
+	return len(lk.EtcdServers) > 0
+}
+
+// GetEtcdServerList returns the etcd servers the apiserver connects to.
+func (lk LocalkubeServer) GetEtcdServerList() []string {
+	if lk.UseExternalEtcd() {
+		return lk.EtcdServers
+	}
+	return KubeEtcdClientURLs
+}
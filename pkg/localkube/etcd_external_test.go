@@ -0,0 +1,34 @@
+/*
+Copyright 2016 The Kubernetes Authors All rights reserved.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package localkube
+
+import (
+	"reflect"
+	"testing"
+)
+
+func TestGetEtcdServerList(t *testing.T) {
+	lk := LocalkubeServer{}
+	if lk.UseExternalEtcd() || !reflect.DeepEqual(lk.GetEtcdServerList(), KubeEtcdClientURLs) {
+		t.Fatalf("Expected the embedded etcd to be used, got %v", lk.GetEtcdServerList())
+	}
+
+	lk.EtcdServers = []string{"https://10.0.0.5:2379", "https://10.0.0.6:2379"}
+	if !lk.UseExternalEtcd() || !reflect.DeepEqual(lk.GetEtcdServerList(), lk.EtcdServers) {
+		t.Fatalf("Expected the external etcd to be used, got %v", lk.GetEtcdServerList())
+	}
+}
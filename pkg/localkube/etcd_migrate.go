@@ -0,0 +1,144 @@
+/*
+Copyright 2016 The Kubernetes Authors All rights reserved.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package localkube
+
+import (
+	"path"
+	"strings"
+
+	etcderr "github.com/coreos/etcd/error"
+	"github.com/coreos/etcd/etcdserver"
+	pb "github.com/coreos/etcd/etcdserver/etcdserverpb"
+	"github.com/coreos/etcd/store"
+	"github.com/golang/glog"
+	"github.com/pkg/errors"
+	"golang.org/x/net/context"
+)
+
+// v2MigrationMarkerPrefix is the v3 key prefix of the markers of completed migrations, followed by
+// the migrated prefix. It is outside the prefix of the apiserver's keys.
+const v2MigrationMarkerPrefix = "/localkube/v2-migrated"
+
+// MigrateV2Data copies the keys under prefix from the etcd v2 store into the v3 keyspace, so that an
+// apiserver switched to the etcd3 storage backend keeps the cluster state it stored with etcd2.
+// Keys with a TTL are attached to a lease with the remaining TTL. Once every key is copied, a
+// marker is written to the v3 keyspace and nothing is copied anymore. Without the marker, the keys
+// of an interrupted migration are deleted and the migration starts over. The v2 data is left
+// untouched, so switching back to etcd2 returns to the state the cluster was in before the
+// migration. It returns the number of keys copied.
+func (e *EtcdServer) MigrateV2Data(prefix string) (int, error) {
+	return e.migrateV2Data(prefix, e.putV3)
+}
+
+// migrateV2Data migrates the keys under prefix like MigrateV2Data, writing each one with put.
+func (e *EtcdServer) migrateV2Data(prefix string, put func(key string, value []byte, ttl int64) error) (int, error) {
+	<-e.ReadyNotify()
+
+	ctx, cancel := context.WithTimeout(context.Background(), e.requestTimeout())
+	defer cancel()
+	marker := []byte(v2MigrationMarkerPrefix + prefix)
+	migrated, err := e.Range(ctx, &pb.RangeRequest{Key: marker, CountOnly: true})
+	if err != nil {
+		return 0, errors.Wrap(err, "Error checking the etcd v3 keyspace")
+	}
+	if migrated.Count > 0 {
+		return 0, nil
+	}
+
+	// Start over from the keys of a migration that didn't complete
+	if _, err := e.DeleteRange(ctx, &pb.DeleteRangeRequest{Key: []byte(prefix), RangeEnd: prefixRangeEnd(prefix)}); err != nil {
+		return 0, errors.Wrap(err, "Error deleting the keys of an incomplete migration")
+	}
+
+	count := 0
+	resp, err := e.Do(ctx, pb.Request{
+		Method:    "GET",
+		Path:      path.Join(etcdserver.StoreKeysPrefix, prefix),
+		Recursive: true,
+		Sorted:    true,
+	})
+	switch etcdErr, ok := err.(*etcderr.Error); {
+	case ok && etcdErr.ErrorCode == etcderr.EcodeKeyNotFound:
+	case err != nil:
+		return 0, errors.Wrap(err, "Error reading the etcd v2 store")
+	default:
+		glog.Infof("Migrating etcd v2 keys under %s to the v3 keyspace", prefix)
+		if count, err = migrateNode(resp.Event.Node, put); err != nil {
+			return count, err
+		}
+	}
+
+	// Even without v2 keys, so that the keys the apiserver writes are never taken for an incomplete migration
+	if _, err := e.Put(ctx, &pb.PutRequest{Key: marker}); err != nil {
+		return count, errors.Wrap(err, "Error marking the migration complete")
+	}
+	return count, nil
+}
+
+func migrateNode(n *store.NodeExtern, put func(key string, value []byte, ttl int64) error) (int, error) {
+	if n.Dir {
+		count := 0
+		for _, child := range n.Nodes {
+			c, err := migrateNode(child, put)
+			count += c
+			if err != nil {
+				return count, err
+			}
+		}
+		return count, nil
+	}
+
+	key := strings.TrimPrefix(n.Key, etcdserver.StoreKeysPrefix)
+	var value []byte
+	if n.Value != nil {
+		value = []byte(*n.Value)
+	}
+	if err := put(key, value, n.TTL); err != nil {
+		return 0, errors.Wrapf(err, "Error migrating %s", key)
+	}
+	return 1, nil
+}
+
+// putV3 writes key to the v3 keyspace, attached to a new lease if ttl is set.
+func (e *EtcdServer) putV3(key string, value []byte, ttl int64) error {
+	ctx, cancel := context.WithTimeout(context.Background(), e.requestTimeout())
+	defer cancel()
+
+	put := &pb.PutRequest{Key: []byte(key), Value: value}
+	if ttl > 0 {
+		lease, err := e.LeaseGrant(ctx, &pb.LeaseGrantRequest{TTL: ttl})
+		if err != nil {
+			return errors.Wrap(err, "Error granting lease")
+		}
+		put.Lease = lease.ID
+	}
+	_, err := e.Put(ctx, put)
+	return err
+}
+
+// prefixRangeEnd returns the end of the range of keys starting with prefix.
+func prefixRangeEnd(prefix string) []byte {
+	end := []byte(prefix)
+	for i := len(end) - 1; i >= 0; i-- {
+		if end[i] < 0xff {
+			end[i]++
+			return end[:i+1]
+		}
+	}
+	// every byte is 0xff, so the range is every key >= prefix
+	return []byte{0}
+}
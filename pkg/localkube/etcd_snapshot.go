@@ -0,0 +1,202 @@
+/*
+Copyright 2016 The Kubernetes Authors All rights reserved.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package localkube
+
+import (
+	"encoding/json"
+	"io/ioutil"
+	"net/http"
+	"os"
+	"path"
+	"strings"
+	"time"
+
+	"github.com/coreos/etcd/etcdserver"
+	pb "github.com/coreos/etcd/etcdserver/etcdserverpb"
+	"github.com/coreos/etcd/lease"
+	"github.com/coreos/etcd/store"
+	"github.com/golang/glog"
+	"github.com/pkg/errors"
+	"golang.org/x/net/context"
+
+	"k8s.io/minikube/pkg/util"
+)
+
+// Snapshot copies every key in the etcd v2 store and v3 keyspace. Both are read through raft,
+// so the snapshot reflects every write etcd acknowledged before it was taken. It is a logical
+// copy rather than etcd's backend snapshot, which leaves out the v2 store the etcd2 storage
+// backend keeps the cluster state in.
+func (e *EtcdServer) Snapshot() (*util.EtcdSnapshot, error) {
+	ctx, cancel := context.WithTimeout(context.Background(), e.requestTimeout())
+	defer cancel()
+	snap := &util.EtcdSnapshot{Time: time.Now(), V3: []util.EtcdSnapshotKey{}}
+
+	resp, err := e.Do(ctx, pb.Request{
+		Method:    "GET",
+		Path:      etcdserver.StoreKeysPrefix,
+		Recursive: true,
+		Sorted:    true,
+		Quorum:    true,
+	})
+	if err != nil {
+		return nil, errors.Wrap(err, "Error reading the etcd v2 store")
+	}
+	snap.V2 = v2SnapshotKeys(resp.Event.Node)
+
+	// A range from \x00 to \x00 covers every key
+	kvs, err := e.Range(ctx, &pb.RangeRequest{Key: []byte{0}, RangeEnd: []byte{0}})
+	if err != nil {
+		return nil, errors.Wrap(err, "Error reading the etcd v3 keyspace")
+	}
+	ttls := map[int64]int64{}
+	for _, kv := range kvs.Kvs {
+		key := util.EtcdSnapshotKey{Key: string(kv.Key), Value: kv.Value}
+		if kv.Lease != 0 {
+			ttl, ok := ttls[kv.Lease]
+			if !ok {
+				l, err := e.LeaseTimeToLive(ctx, &pb.LeaseTimeToLiveRequest{ID: kv.Lease})
+				if err != nil && err != lease.ErrLeaseNotFound {
+					return nil, errors.Wrapf(err, "Error getting the lease of %s", kv.Key)
+				}
+				if err == nil {
+					ttl = l.TTL
+				}
+				ttls[kv.Lease] = ttl
+			}
+			if ttl <= 0 {
+				// the key is expiring
+				continue
+			}
+			key.TTL = ttl
+		}
+		snap.V3 = append(snap.V3, key)
+	}
+	return snap, nil
+}
+
+func v2SnapshotKeys(n *store.NodeExtern) []util.EtcdSnapshotKey {
+	if !n.Dir {
+		key := util.EtcdSnapshotKey{Key: strings.TrimPrefix(n.Key, etcdserver.StoreKeysPrefix), TTL: n.TTL}
+		if n.Value != nil {
+			key.Value = []byte(*n.Value)
+		}
+		return []util.EtcdSnapshotKey{key}
+	}
+	keys := []util.EtcdSnapshotKey{}
+	for _, child := range n.Nodes {
+		keys = append(keys, v2SnapshotKeys(child)...)
+	}
+	return keys
+}
+
+// RestoreSnapshot writes the keys in snap to etcd, which is expected to be empty.
+func (e *EtcdServer) RestoreSnapshot(snap *util.EtcdSnapshot) error {
+	<-e.ReadyNotify()
+
+	for _, key := range snap.V2 {
+		req := pb.Request{
+			Method: "PUT",
+			Path:   path.Join(etcdserver.StoreKeysPrefix, key.Key),
+			Val:    string(key.Value),
+		}
+		if key.TTL > 0 {
+			req.Expiration = time.Now().Add(time.Duration(key.TTL) * time.Second).UnixNano()
+		}
+		ctx, cancel := context.WithTimeout(context.Background(), e.requestTimeout())
+		_, err := e.Do(ctx, req)
+		cancel()
+		if err != nil {
+			return errors.Wrapf(err, "Error restoring v2 key %s", key.Key)
+		}
+	}
+
+	for _, key := range snap.V3 {
+		if err := e.putV3(key.Key, key.Value, key.TTL); err != nil {
+			return errors.Wrapf(err, "Error restoring v3 key %s", key.Key)
+		}
+	}
+	return nil
+}
+
+// SnapshotHandler serves a snapshot of etcd as JSON.
+func (e *EtcdServer) SnapshotHandler() http.Handler {
+	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		snap, err := e.Snapshot()
+		if err != nil {
+			http.Error(w, err.Error(), http.StatusInternalServerError)
+			return
+		}
+		w.Header().Set("Content-Type", "application/json")
+		if err := json.NewEncoder(w).Encode(snap); err != nil {
+			glog.Errorf("Error encoding etcd snapshot: %s", err)
+		}
+	})
+}
+
+// GetEtcdRestorePath returns the path of the snapshot localkube restores the next time it starts.
+func (lk LocalkubeServer) GetEtcdRestorePath() string {
+	return path.Join(lk.LocalkubeDirectory, util.EtcdRestoreFile)
+}
+
+func (lk LocalkubeServer) getEtcdBackupDirectory() string {
+	return lk.GetEtcdDataDirectory() + ".pre-restore"
+}
+
+// EtcdRestorePending returns whether there is a snapshot waiting to be restored.
+func (lk LocalkubeServer) EtcdRestorePending() bool {
+	_, err := os.Stat(lk.GetEtcdRestorePath())
+	return err == nil
+}
+
+// LoadEtcdRestore reads the snapshot waiting to be restored, if there is one, and moves the etcd
+// data directory aside so the snapshot is restored into an empty etcd. The data directory is kept
+// next to the new one until the next restore, with a .pre-restore suffix.
+func (lk LocalkubeServer) LoadEtcdRestore() (*util.EtcdSnapshot, error) {
+	contents, err := ioutil.ReadFile(lk.GetEtcdRestorePath())
+	if os.IsNotExist(err) {
+		return nil, nil
+	}
+	if err != nil {
+		return nil, errors.Wrap(err, "Error reading etcd snapshot")
+	}
+	snap := &util.EtcdSnapshot{}
+	if err := json.Unmarshal(contents, snap); err != nil {
+		return nil, errors.Wrap(err, "Error parsing etcd snapshot")
+	}
+
+	if err := os.RemoveAll(lk.getEtcdBackupDirectory()); err != nil {
+		return nil, errors.Wrap(err, "Error removing the previous etcd backup")
+	}
+	if err := os.Rename(lk.GetEtcdDataDirectory(), lk.getEtcdBackupDirectory()); err != nil && !os.IsNotExist(err) {
+		return nil, errors.Wrap(err, "Error moving the etcd data directory aside")
+	}
+	return snap, nil
+}
+
+// FinishEtcdRestore removes the snapshot that was restored, or if the restore failed, puts the
+// etcd data directory that was moved aside back in place.
+func (lk LocalkubeServer) FinishEtcdRestore(restoreErr error) error {
+	if restoreErr != nil {
+		if err := os.RemoveAll(lk.GetEtcdDataDirectory()); err != nil {
+			return err
+		}
+		if err := os.Rename(lk.getEtcdBackupDirectory(), lk.GetEtcdDataDirectory()); err != nil && !os.IsNotExist(err) {
+			return err
+		}
+	}
+	return os.Remove(lk.GetEtcdRestorePath())
+}
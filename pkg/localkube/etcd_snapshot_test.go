@@ -0,0 +1,122 @@
+/*
+Copyright 2016 The Kubernetes Authors All rights reserved.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package localkube
+
+import (
+	"errors"
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"reflect"
+	"testing"
+	"time"
+
+	pb "github.com/coreos/etcd/etcdserver/etcdserverpb"
+	"golang.org/x/net/context"
+	"k8s.io/apiserver/pkg/storage/storagebackend"
+
+	"k8s.io/minikube/pkg/util"
+)
+
+func TestEtcdSnapshot(t *testing.T) {
+	e, dir := startTestEtcd(t, storagebackend.StorageTypeETCD2)
+	defer os.RemoveAll(dir)
+	defer e.Stop()
+
+	ctx, cancel := context.WithTimeout(context.Background(), 10*time.Second)
+	defer cancel()
+	if _, err := e.Do(ctx, pb.Request{Method: "PUT", Path: "/1/registry/pods/default/busybox", Val: "pod"}); err != nil {
+		t.Fatalf("Error writing v2 key: %s", err)
+	}
+	expiration := time.Now().Add(time.Hour).UnixNano()
+	if _, err := e.Do(ctx, pb.Request{Method: "PUT", Path: "/1/registry/events/default/busybox", Val: "event", Expiration: expiration}); err != nil {
+		t.Fatalf("Error writing v2 key: %s", err)
+	}
+	if err := e.putV3("/registry/namespaces/default", []byte("namespace"), 0); err != nil {
+		t.Fatalf("Error writing v3 key: %s", err)
+	}
+
+	snap, err := e.Snapshot()
+	if err != nil {
+		t.Fatalf("Error taking snapshot: %s", err)
+	}
+	if len(snap.V2) != 2 || snap.V2[0].Key != "/registry/events/default/busybox" || snap.V2[0].TTL == 0 {
+		t.Fatalf("Unexpected v2 keys in snapshot: %+v", snap.V2)
+	}
+	if len(snap.V3) != 1 || string(snap.V3[0].Value) != "namespace" {
+		t.Fatalf("Unexpected v3 keys in snapshot: %+v", snap.V3)
+	}
+
+	restored, restoredDir := startTestEtcd(t, storagebackend.StorageTypeETCD2)
+	defer os.RemoveAll(restoredDir)
+	defer restored.Stop()
+	if err := restored.RestoreSnapshot(snap); err != nil {
+		t.Fatalf("Error restoring snapshot: %s", err)
+	}
+	restoredSnap, err := restored.Snapshot()
+	if err != nil {
+		t.Fatalf("Error taking snapshot of restored etcd: %s", err)
+	}
+	if !reflect.DeepEqual(restoredSnap.V3, snap.V3) || len(restoredSnap.V2) != 2 || !reflect.DeepEqual(restoredSnap.V2[1], snap.V2[1]) {
+		t.Fatalf("Expected restored etcd to have the keys %+v, got %+v", snap, restoredSnap)
+	}
+}
+
+func TestEtcdRestore(t *testing.T) {
+	dir, err := ioutil.TempDir("", "localkube-restore")
+	if err != nil {
+		t.Fatalf("Error creating temp dir: %s", err)
+	}
+	defer os.RemoveAll(dir)
+	lk := LocalkubeServer{LocalkubeDirectory: dir}
+
+	if snap, err := lk.LoadEtcdRestore(); snap != nil || err != nil {
+		t.Fatalf("Expected no snapshot to restore, got %+v and error %v", snap, err)
+	}
+
+	member := filepath.Join(lk.GetEtcdDataDirectory(), "member")
+	if err := os.MkdirAll(member, 0755); err != nil {
+		t.Fatalf("Error creating etcd data directory: %s", err)
+	}
+	if err := ioutil.WriteFile(lk.GetEtcdRestorePath(), []byte(`{"v2":[{"key":"/a","value":"Yg=="}]}`), 0644); err != nil {
+		t.Fatalf("Error writing snapshot: %s", err)
+	}
+	if !lk.EtcdRestorePending() {
+		t.Fatal("Expected a restore to be pending")
+	}
+
+	snap, err := lk.LoadEtcdRestore()
+	if err != nil {
+		t.Fatalf("Error loading snapshot: %s", err)
+	}
+	if expected := []util.EtcdSnapshotKey{{Key: "/a", Value: []byte("b")}}; !reflect.DeepEqual(snap.V2, expected) {
+		t.Fatalf("Expected snapshot keys %+v, got %+v", expected, snap.V2)
+	}
+	if _, err := os.Stat(member); !os.IsNotExist(err) {
+		t.Fatalf("Expected the etcd data directory to be moved aside, got %v", err)
+	}
+
+	if err := lk.FinishEtcdRestore(errors.New("restore failed")); err != nil {
+		t.Fatalf("Error finishing restore: %s", err)
+	}
+	if _, err := os.Stat(member); err != nil {
+		t.Fatalf("Expected the etcd data directory to be put back after a failed restore: %s", err)
+	}
+	if lk.EtcdRestorePending() {
+		t.Fatal("Expected the snapshot to be removed")
+	}
+}
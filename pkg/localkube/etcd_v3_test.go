@@ -0,0 +1,193 @@
+/*
+Copyright 2016 The Kubernetes Authors All rights reserved.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package localkube
+
+import (
+	"errors"
+	"io/ioutil"
+	"net/http"
+	"os"
+	"path/filepath"
+	"reflect"
+	"testing"
+	"time"
+
+	"github.com/coreos/etcd/clientv3"
+	pb "github.com/coreos/etcd/etcdserver/etcdserverpb"
+	"golang.org/x/net/context"
+	"k8s.io/apiserver/pkg/storage/storagebackend"
+)
+
+func startTestEtcd(t *testing.T, storageBackend string) (*EtcdServer, string) {
+	dir, err := ioutil.TempDir("", "localkube-etcd")
+	if err != nil {
+		t.Fatalf("Error creating temp dir: %s", err)
+	}
+	lk := LocalkubeServer{StorageBackend: storageBackend}
+	e, err := lk.NewEtcd([]string{"http://127.0.0.1:0"}, []string{"http://127.0.0.1:0"}, "test", filepath.Join(dir, "etcd"))
+	if err != nil {
+		t.Fatalf("Error creating etcd: %s", err)
+	}
+	e.Start()
+	<-e.ReadyNotify()
+	return e, dir
+}
+
+func TestEtcdV3(t *testing.T) {
+	e, dir := startTestEtcd(t, storagebackend.StorageTypeETCD3)
+	defer os.RemoveAll(dir)
+	defer e.Stop()
+	endpoint := "http://" + e.clientListeners[0].Addr().String()
+
+	ctx, cancel := context.WithTimeout(context.Background(), 10*time.Second)
+	defer cancel()
+	for _, key := range []string{"/registry/namespaces/default", "/registry/pods/default/busybox"} {
+		value := key
+		if _, err := e.Do(ctx, pb.Request{Method: "PUT", Path: "/1" + key, Val: value}); err != nil {
+			t.Fatalf("Error writing %s to the v2 store: %s", key, err)
+		}
+	}
+
+	n, err := e.MigrateV2Data("/registry")
+	if err != nil {
+		t.Fatalf("Error migrating v2 data: %s", err)
+	}
+	if n != 2 {
+		t.Fatalf("Expected 2 keys to be migrated, got %d", n)
+	}
+	if n, err := e.MigrateV2Data("/registry"); err != nil || n != 0 {
+		t.Fatalf("Expected nothing to be migrated a second time, got %d keys and error %v", n, err)
+	}
+
+	client, err := clientv3.New(clientv3.Config{Endpoints: []string{endpoint}, DialTimeout: 5 * time.Second})
+	if err != nil {
+		t.Fatalf("Error creating etcd v3 client: %s", err)
+	}
+	defer client.Close()
+
+	get, err := client.Get(ctx, "/registry/", clientv3.WithPrefix())
+	if err != nil {
+		t.Fatalf("Error getting migrated keys: %s", err)
+	}
+	if len(get.Kvs) != 2 || string(get.Kvs[1].Value) != "/registry/pods/default/busybox" {
+		t.Fatalf("Unexpected migrated keys: %v", get.Kvs)
+	}
+
+	watch := client.Watch(ctx, "/registry/pods/", clientv3.WithPrefix(), clientv3.WithPrevKV())
+	if _, err := client.Put(ctx, "/registry/pods/default/busybox", "updated"); err != nil {
+		t.Fatalf("Error updating key: %s", err)
+	}
+	select {
+	case resp := <-watch:
+		if len(resp.Events) != 1 || resp.Events[0].PrevKv == nil || string(resp.Events[0].Kv.Value) != "updated" {
+			t.Fatalf("Unexpected watch response: %+v", resp)
+		}
+	case <-ctx.Done():
+		t.Fatal("Timed out waiting for watch event")
+	}
+
+	// The v2 HTTP API is still served on the same listener
+	resp, err := http.Get(endpoint + "/v2/keys/registry/namespaces/default")
+	if err != nil {
+		t.Fatalf("Error getting v2 key: %s", err)
+	}
+	resp.Body.Close()
+	if resp.StatusCode != http.StatusOK {
+		t.Fatalf("Expected the v2 API to respond with 200, got %d", resp.StatusCode)
+	}
+}
+
+func TestMigrateV2DataResume(t *testing.T) {
+	e, dir := startTestEtcd(t, storagebackend.StorageTypeETCD3)
+	defer os.RemoveAll(dir)
+	defer e.Stop()
+
+	ctx, cancel := context.WithTimeout(context.Background(), 10*time.Second)
+	defer cancel()
+	for _, key := range []string{"/registry/namespaces/default", "/registry/pods/default/busybox"} {
+		if _, err := e.Do(ctx, pb.Request{Method: "PUT", Path: "/1" + key, Val: key}); err != nil {
+			t.Fatalf("Error writing %s to the v2 store: %s", key, err)
+		}
+	}
+	v3Keys := func() []string {
+		resp, err := e.Range(ctx, &pb.RangeRequest{Key: []byte("/registry"), RangeEnd: prefixRangeEnd("/registry")})
+		if err != nil {
+			t.Fatalf("Error listing the v3 keys: %s", err)
+		}
+		keys := []string{}
+		for _, kv := range resp.Kvs {
+			keys = append(keys, string(kv.Key))
+		}
+		return keys
+	}
+
+	// Fail after the first key is copied
+	failed := errors.New("injected failure")
+	n, err := e.migrateV2Data("/registry", func(key string, value []byte, ttl int64) error {
+		if key == "/registry/pods/default/busybox" {
+			return failed
+		}
+		return e.putV3(key, value, ttl)
+	})
+	if err == nil || n != 1 {
+		t.Fatalf("Expected the migration to fail after 1 key, got %d keys and error %v", n, err)
+	}
+
+	// The partial copy is deleted rather than kept, so a key removed from v2 meanwhile is gone
+	if _, err := e.Do(ctx, pb.Request{Method: "DELETE", Path: "/1/registry/namespaces/default"}); err != nil {
+		t.Fatalf("Error deleting v2 key: %s", err)
+	}
+	if n, err := e.MigrateV2Data("/registry"); err != nil || n != 1 {
+		t.Fatalf("Expected the migration to resume and copy 1 key, got %d keys and error %v", n, err)
+	}
+	if keys := v3Keys(); !reflect.DeepEqual(keys, []string{"/registry/pods/default/busybox"}) {
+		t.Fatalf("Unexpected migrated keys: %v", keys)
+	}
+
+	// Once complete, the migration isn't run again even though v2 has keys v3 doesn't
+	if _, err := e.Do(ctx, pb.Request{Method: "PUT", Path: "/1/registry/namespaces/default", Val: "again"}); err != nil {
+		t.Fatalf("Error writing v2 key: %s", err)
+	}
+	if n, err := e.MigrateV2Data("/registry"); err != nil || n != 0 {
+		t.Fatalf("Expected nothing to be migrated after a complete migration, got %d keys and error %v", n, err)
+	}
+	if keys := v3Keys(); len(keys) != 1 {
+		t.Fatalf("Expected the migrated keys to be kept, got %v", keys)
+	}
+}
+
+func TestMigrateV2DataEmpty(t *testing.T) {
+	e, dir := startTestEtcd(t, storagebackend.StorageTypeETCD3)
+	defer os.RemoveAll(dir)
+	defer e.Stop()
+
+	if n, err := e.MigrateV2Data("/registry"); err != nil || n != 0 {
+		t.Fatalf("Expected nothing to be migrated without v2 keys, got %d keys and error %v", n, err)
+	}
+	// The keys the apiserver writes next are not taken for an incomplete migration
+	ctx, cancel := context.WithTimeout(context.Background(), 10*time.Second)
+	defer cancel()
+	if _, err := e.Put(ctx, &pb.PutRequest{Key: []byte("/registry/namespaces/default")}); err != nil {
+		t.Fatalf("Error writing v3 key: %s", err)
+	}
+	if _, err := e.MigrateV2Data("/registry"); err != nil {
+		t.Fatalf("Error migrating v2 data: %s", err)
+	}
+	if resp, err := e.Range(ctx, &pb.RangeRequest{Key: []byte("/registry/namespaces/default")}); err != nil || resp.Count != 1 {
+		t.Fatalf("Expected the key written to v3 to be kept, got %v and error %v", resp, err)
+	}
+}
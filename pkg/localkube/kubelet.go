@@ -22,14 +22,19 @@ import (
 )
 
 func (lk LocalkubeServer) NewKubeletServer() Server {
-	return NewSimpleServer("kubelet", serverInterval, StartKubeletServer(lk), noop)
+	return newComponentServer("kubelet", noop, lk.apiserverDependencies()...)
 }
 
 func StartKubeletServer(lk LocalkubeServer) func() error {
 	config := options.NewKubeletServer()
 
 	// Master details
-	config.APIServerList = []string{lk.GetAPIServerInsecureURL()}
+	if lk.IsWorker() {
+		config.KubeConfig.Set(lk.GetWorkerKubeconfigPath())
+		config.RequireKubeConfig = true
+	} else {
+		config.APIServerList = []string{lk.GetAPIServerInsecureURL()}
+	}
 
 	// Set containerized based on the flag
 	config.Containerized = lk.Containerized
@@ -41,7 +46,10 @@ func StartKubeletServer(lk LocalkubeServer) func() error {
 	config.ClusterDomain = lk.DNSDomain
 	config.ClusterDNS = []string{lk.DNSIP.String()}
 	// For kubenet plugin.
-	config.PodCIDR = "10.180.1.0/24"
+	config.PodCIDR = lk.PodCIDR.String()
+	if lk.ClusterCIDR != "" {
+		config.NonMasqueradeCIDR = lk.ClusterCIDR
+	}
 
 	config.NodeIP = lk.NodeIP.String()
 
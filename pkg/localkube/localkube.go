@@ -17,12 +17,13 @@ limitations under the License.
 package localkube
 
 import (
-	"crypto/x509"
-	"encoding/pem"
+	"crypto/sha256"
+	"encoding/hex"
 	"fmt"
 	"io/ioutil"
 	"net"
 	"path"
+	"time"
 
 	"github.com/golang/glog"
 
@@ -40,32 +41,72 @@ type LocalkubeServer struct {
 	Servers
 
 	// Options
-	Containerized            bool
-	EnableDNS                bool
-	DNSDomain                string
-	DNSIP                    net.IP
-	LocalkubeDirectory       string
-	ServiceClusterIPRange    net.IPNet
-	APIServerAddress         net.IP
-	APIServerPort            int
-	APIServerInsecureAddress net.IP
-	APIServerInsecurePort    int
-	APIServerName            string
-	ShouldGenerateCerts      bool
-	ShowVersion              bool
-	ShowHostIP               bool
-	RuntimeConfig            flag.ConfigurationMap
-	NodeIP                   net.IP
-	ContainerRuntime         string
-	NetworkPlugin            string
-	FeatureGates             string
-	ExtraConfig              util.ExtraOptionSlice
+	Containerized                  bool
+	EnableDNS                      bool
+	DNSDomain                      string
+	DNSIP                          net.IP
+	LocalkubeDirectory             string
+	ServiceClusterIPRange          net.IPNet
+	PodCIDR                        net.IPNet
+	APIServerAddress               net.IP
+	APIServerPort                  int
+	APIServerInsecureAddress       net.IP
+	APIServerInsecurePort          int
+	APIServerName                  string
+	APIServerExtraSANs             []string
+	ShouldGenerateCerts            bool
+	ShowVersion                    bool
+	ShowHostIP                     bool
+	RuntimeConfig                  flag.ConfigurationMap
+	NodeIP                         net.IP
+	ContainerRuntime               string
+	NetworkPlugin                  string
+	FeatureGates                   string
+	ExtraConfig                    util.ExtraOptionSlice
+	ReadyTimeout                   time.Duration
+	ComponentReadyTimeouts         flag.ConfigurationMap
+	StatusAddress                  string
+	ConfigFile                     string
+	ShutdownGracePeriod            time.Duration
+	StorageBackend                 string
+	EtcdServers                    []string
+	EtcdCAFile                     string
+	EtcdCertFile                   string
+	EtcdKeyFile                    string
+	AdmissionControl               []string
+	AuthorizationMode              string
+	AuthorizationWebhookFile       string
+	AuditLogPath                   string
+	AuditLogMaxAge                 int
+	AuditLogMaxBackups             int
+	AuditLogMaxSize                int
+	AuditPolicyFile                string
+	HostPathProvisionerDir         string
+	HostPathProvisionerEnforceSize bool
+	StorageClassDirs               flag.ConfigurationMap
+	ProxyMode                      string
+	MasqueradeAll                  bool
+	ClusterCIDR                    string
+	JoinAPIServer                  string
 }
 
 func (lk *LocalkubeServer) AddServer(server Server) {
 	lk.Servers = append(lk.Servers, server)
 }
 
+// GetReadyTimeout returns how long the named component is given to become ready,
+// using the per-component override if one was set.
+func (lk LocalkubeServer) GetReadyTimeout(name string) time.Duration {
+	if v, ok := lk.ComponentReadyTimeouts[name]; ok {
+		d, err := time.ParseDuration(v)
+		if err == nil {
+			return d
+		}
+		glog.Warningf("Invalid ready timeout %q for %s, using %s. Error: %s", v, name, lk.ReadyTimeout, err)
+	}
+	return lk.ReadyTimeout
+}
+
 func (lk LocalkubeServer) GetEtcdDataDirectory() string {
 	return path.Join(lk.LocalkubeDirectory, "etcd")
 }
@@ -90,6 +131,18 @@ func (lk LocalkubeServer) GetCAPublicKeyCertPath() string {
 	return path.Join(lk.GetCertificateDirectory(), "ca.crt")
 }
 
+// GetCertsFingerprint returns a digest of the CA and apiserver certificates, which changes when
+// they are replaced.
+func (lk LocalkubeServer) GetCertsFingerprint() string {
+	h := sha256.New()
+	for _, p := range []string{lk.GetCAPublicKeyCertPath(), lk.GetPublicKeyCertPath()} {
+		// A missing certificate is hashed as empty
+		contents, _ := ioutil.ReadFile(p)
+		h.Write(contents)
+	}
+	return hex.EncodeToString(h.Sum(nil))
+}
+
 func (lk LocalkubeServer) GetAPIServerSecureURL() string {
 	return fmt.Sprintf("https://%s:%d", lk.APIServerAddress.String(), lk.APIServerPort)
 }
@@ -123,32 +176,26 @@ func (lk LocalkubeServer) SetExtraConfigForComponent(component string, config in
 	}
 }
 
-func (lk LocalkubeServer) loadCert(path string) (*x509.Certificate, error) {
-	contents, err := ioutil.ReadFile(path)
-	if err != nil {
-		return nil, err
-	}
-	decoded, _ := pem.Decode(contents)
-	if decoded == nil {
-		return nil, fmt.Errorf("Unable to decode certificate.")
-	}
-
-	return x509.ParseCertificate(decoded.Bytes)
-}
-
-func (lk LocalkubeServer) shouldGenerateCerts(ips []net.IP) bool {
+// shouldGenerateCerts returns whether the apiserver certificate is missing, expires soon, or
+// isn't for exactly the given IPs and DNS names.
+func (lk LocalkubeServer) shouldGenerateCerts(ips []net.IP, dnsNames []string) bool {
 	if !(util.CanReadFile(lk.GetPublicKeyCertPath()) &&
 		util.CanReadFile(lk.GetPrivateKeyCertPath())) {
 		fmt.Println("Regenerating certs because the files aren't readable")
 		return true
 	}
 
-	cert, err := lk.loadCert(lk.GetPublicKeyCertPath())
+	cert, err := util.LoadCert(lk.GetPublicKeyCertPath())
 	if err != nil {
 		fmt.Println("Regenerating certs because there was an error loading the certificate: ", err)
 		return true
 	}
 
+	if util.CertExpiresSoon(cert) {
+		fmt.Println("Regenerating certs because they expire on", cert.NotAfter)
+		return true
+	}
+
 	certIPs := map[string]bool{}
 	for _, certIP := range cert.IPAddresses {
 		certIPs[certIP.String()] = true
@@ -159,6 +206,27 @@ func (lk LocalkubeServer) shouldGenerateCerts(ips []net.IP) bool {
 			fmt.Println("Regenerating certs becase an IP is missing: ", ip)
 			return true
 		}
+		delete(certIPs, ip.String())
+	}
+	for ip := range certIPs {
+		fmt.Println("Regenerating certs because an IP is no longer used: ", ip)
+		return true
+	}
+
+	certNames := map[string]bool{}
+	for _, name := range cert.DNSNames {
+		certNames[name] = true
+	}
+	for _, name := range dnsNames {
+		if _, ok := certNames[name]; !ok {
+			fmt.Println("Regenerating certs because a DNS name is missing: ", name)
+			return true
+		}
+		delete(certNames, name)
+	}
+	for name := range certNames {
+		fmt.Println("Regenerating certs because a DNS name is no longer used: ", name)
+		return true
 	}
 	return false
 }
@@ -170,17 +238,21 @@ func (lk LocalkubeServer) shouldGenerateCACerts() bool {
 		return true
 	}
 
-	_, err := lk.loadCert(lk.GetCAPublicKeyCertPath())
+	cert, err := util.LoadCert(lk.GetCAPublicKeyCertPath())
 	if err != nil {
 		fmt.Println("Regenerating CA certs because there was an error loading the certificate: ", err)
 		return true
 	}
+	// The certificates signed by the CA would no longer be trusted, so it is only reported
+	if util.CertExpiresSoon(cert) {
+		glog.Warningf("The CA certificate %s expires on %s, run minikube certs rotate --ca to replace it", lk.GetCAPublicKeyCertPath(), cert.NotAfter)
+	}
 
 	return false
 }
 
 func (lk LocalkubeServer) getAllIPs() ([]net.IP, error) {
-	ips := []net.IP{net.ParseIP(util.DefaultServiceClusterIP)}
+	ips := []net.IP{util.GetServiceClusterIP(lk.ServiceClusterIPRange)}
 	addrs, err := net.InterfaceAddrs()
 	if err != nil {
 		return nil, err
@@ -211,14 +283,20 @@ func (lk LocalkubeServer) GenerateCerts() error {
 	if err != nil {
 		return err
 	}
+	extraIPs, extraNames, err := util.ParseSANs(lk.APIServerExtraSANs)
+	if err != nil {
+		return err
+	}
+	ips = append(ips, extraIPs...)
+	dnsNames := append(util.GetAlternateDNS(lk.DNSDomain), extraNames...)
 
-	if !lk.shouldGenerateCerts(ips) {
+	if !lk.shouldGenerateCerts(ips, dnsNames) {
 		fmt.Println("Using these existing certs: ", lk.GetPublicKeyCertPath(), lk.GetPrivateKeyCertPath())
 		return nil
 	}
 	fmt.Println("Creating cert with IPs: ", ips)
 
-	if err := util.GenerateSignedCert(lk.GetPublicKeyCertPath(), lk.GetPrivateKeyCertPath(), ips, util.GetAlternateDNS(lk.DNSDomain), lk.GetCAPublicKeyCertPath(), lk.GetCAPrivateKeyCertPath()); err != nil {
+	if err := util.GenerateSignedCert(lk.GetPublicKeyCertPath(), lk.GetPrivateKeyCertPath(), ips, dnsNames, lk.GetCAPublicKeyCertPath(), lk.GetCAPrivateKeyCertPath()); err != nil {
 		fmt.Println("Failed to create certs: ", err)
 		return err
 	}
@@ -24,9 +24,13 @@ import (
 	"testing"
 
 	"k8s.io/minikube/pkg/minikube/tests"
+	"k8s.io/minikube/pkg/util"
 )
 
-var testIPs = []net.IP{net.ParseIP("1.2.3.4")}
+var (
+	testIPs      = []net.IP{net.ParseIP("1.2.3.4")}
+	testDNSNames = util.GetAlternateDNS(util.DefaultDNSDomain)
+)
 
 func TestGenerateCerts(t *testing.T) {
 	tempDir := tests.MakeTempDir()
@@ -50,7 +54,7 @@ func TestGenerateCerts(t *testing.T) {
 			t.Fatalf("Certificate not created: %s", p)
 		}
 	}
-	_, err := lk.loadCert(filepath.Join(tempDir, "certs", "apiserver.crt"))
+	_, err := util.LoadCert(filepath.Join(tempDir, "certs", "apiserver.crt"))
 	if err != nil {
 		t.Fatalf("Error parsing cert: %s", err)
 	}
@@ -58,7 +62,7 @@ func TestGenerateCerts(t *testing.T) {
 
 func TestShouldGenerateCertsNoFiles(t *testing.T) {
 	lk := LocalkubeServer{LocalkubeDirectory: "baddir"}
-	if !lk.shouldGenerateCerts(testIPs) {
+	if !lk.shouldGenerateCerts(testIPs, testDNSNames) {
 		t.Fatalf("No certs exist, we should generate.")
 	}
 }
@@ -69,7 +73,7 @@ func TestShouldGenerateCertsOneFile(t *testing.T) {
 	os.Mkdir(filepath.Join(tempDir, "certs"), 0777)
 	ioutil.WriteFile(filepath.Join(tempDir, "certs", "apiserver.crt"), []byte(""), 0644)
 	lk := LocalkubeServer{LocalkubeDirectory: tempDir}
-	if !lk.shouldGenerateCerts(testIPs) {
+	if !lk.shouldGenerateCerts(testIPs, testDNSNames) {
 		t.Fatalf("Not all certs exist, we should generate.")
 	}
 }
@@ -82,7 +86,7 @@ func TestShouldGenerateCertsBadFiles(t *testing.T) {
 		ioutil.WriteFile(filepath.Join(tempDir, "certs", f), []byte(""), 0644)
 	}
 	lk := LocalkubeServer{LocalkubeDirectory: tempDir}
-	if !lk.shouldGenerateCerts(testIPs) {
+	if !lk.shouldGenerateCerts(testIPs, testDNSNames) {
 		t.Fatalf("Certs are badly formatted, we should generate.")
 	}
 }
@@ -100,7 +104,7 @@ func TestShouldGenerateCertsMismatchedIP(t *testing.T) {
 
 	lk.GenerateCerts()
 
-	if !lk.shouldGenerateCerts([]net.IP{net.ParseIP("4.3.2.1")}) {
+	if !lk.shouldGenerateCerts([]net.IP{net.ParseIP("4.3.2.1")}, util.GetAlternateDNS(lk.DNSDomain)) {
 		t.Fatalf("IPs don't match, we should generate.")
 	}
 }
@@ -117,7 +121,71 @@ func TestShouldNotGenerateCerts(t *testing.T) {
 	}
 	lk.GenerateCerts()
 	ips, _ := lk.getAllIPs()
-	if lk.shouldGenerateCerts(ips) {
+	if lk.shouldGenerateCerts(ips, util.GetAlternateDNS(lk.DNSDomain)) {
 		t.Fatalf("IPs match, we should not generate.")
 	}
 }
+
+func TestShouldGenerateCertsExtraSANs(t *testing.T) {
+	tempDir := tests.MakeTempDir()
+	defer os.RemoveAll(tempDir)
+	os.Mkdir(filepath.Join(tempDir, "certs"), 0777)
+
+	_, ipRange, _ := net.ParseCIDR("10.0.0.0/24")
+	lk := LocalkubeServer{
+		LocalkubeDirectory:    tempDir,
+		ServiceClusterIPRange: *ipRange,
+		APIServerExtraSANs:    []string{"minikube.example.com", "192.168.0.10"},
+	}
+	if err := lk.GenerateCerts(); err != nil {
+		t.Fatalf("Unexpected error generating certs: %s", err)
+	}
+	cert, err := util.LoadCert(lk.GetPublicKeyCertPath())
+	if err != nil {
+		t.Fatalf("Error loading cert: %s", err)
+	}
+	ips, _ := lk.getAllIPs()
+	ips = append(ips, net.ParseIP("192.168.0.10"))
+	names := append(util.GetAlternateDNS(lk.DNSDomain), "minikube.example.com")
+	if lk.shouldGenerateCerts(ips, names) {
+		t.Fatalf("Expected the cert to have the extra SANs, got %v and %v", cert.IPAddresses, cert.DNSNames)
+	}
+
+	if !lk.shouldGenerateCerts(ips[:len(ips)-1], names) {
+		t.Errorf("An IP was removed, we should generate.")
+	}
+	if !lk.shouldGenerateCerts(ips, names[:len(names)-1]) {
+		t.Errorf("A DNS name was removed, we should generate.")
+	}
+	if !lk.shouldGenerateCerts(ips, append(names, "localhost")) {
+		t.Errorf("A DNS name was added, we should generate.")
+	}
+}
+
+func TestGetCertsFingerprint(t *testing.T) {
+	tempDir := tests.MakeTempDir()
+	defer os.RemoveAll(tempDir)
+	os.Mkdir(filepath.Join(tempDir, "certs"), 0777)
+
+	_, ipRange, _ := net.ParseCIDR("10.0.0.0/24")
+	lk := LocalkubeServer{
+		LocalkubeDirectory:    tempDir,
+		ServiceClusterIPRange: *ipRange,
+	}
+	missing := lk.GetCertsFingerprint()
+	lk.GenerateCerts()
+	fingerprint := lk.GetCertsFingerprint()
+	if fingerprint == missing {
+		t.Fatalf("Expected the fingerprint to change when the certs are created")
+	}
+	if lk.GetCertsFingerprint() != fingerprint {
+		t.Fatalf("Expected the fingerprint of unchanged certs to stay the same")
+	}
+
+	if err := util.GenerateSignedCert(lk.GetPublicKeyCertPath(), lk.GetPrivateKeyCertPath(), testIPs, nil, lk.GetCAPublicKeyCertPath(), lk.GetCAPrivateKeyCertPath()); err != nil {
+		t.Fatalf("Error generating cert: %s", err)
+	}
+	if lk.GetCertsFingerprint() == fingerprint {
+		t.Fatalf("Expected the fingerprint to change when the certs are replaced")
+	}
+}
@@ -0,0 +1,329 @@
+/*
+Copyright 2016 The Kubernetes Authors All rights reserved.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package localkube
+
+import (
+	"bufio"
+	"debug/elf"
+	"debug/gosym"
+	"fmt"
+	"io"
+	"io/ioutil"
+	"os"
+	"os/exec"
+	"os/signal"
+	"path/filepath"
+	"regexp"
+	"strconv"
+	"strings"
+	"sync"
+	"syscall"
+
+	"k8s.io/minikube/pkg/util"
+)
+
+// LogTaggerCommand is the hidden localkube command TagLogs runs to tag the output of localkube.
+const LogTaggerCommand = "tag-logs"
+
+// logTaggerEnv is set once the output of localkube goes through the log tagger, so that the
+// localkube it re-executes into on SIGHUP keeps writing to the same tagger.
+const logTaggerEnv = "LOCALKUBE_LOG_TAGGER"
+
+// TagLogs sends the stdout and stderr of localkube through a `localkube tag-logs` process, which
+// prefixes every line with the component that logged it in brackets, e.g. "[kubelet] ", see
+// util.LogComponents. The tagger runs in its own process so that it also tags the last lines of
+// localkube crashing, and it exits once localkube closes its output.
+func TagLogs() error {
+	if os.Getenv(logTaggerEnv) != "" {
+		return nil
+	}
+	executable, err := os.Executable()
+	if err != nil {
+		return err
+	}
+	stdoutR, stdoutW, err := os.Pipe()
+	if err != nil {
+		return err
+	}
+	defer stdoutW.Close()
+	stderrR, stderrW, err := os.Pipe()
+	if err != nil {
+		stdoutR.Close()
+		return err
+	}
+	defer stderrW.Close()
+
+	cmd := exec.Command(executable, LogTaggerCommand)
+	cmd.Stdout = os.Stdout
+	cmd.Stderr = os.Stderr
+	cmd.ExtraFiles = []*os.File{stdoutR, stderrR}
+	err = cmd.Start()
+	stdoutR.Close()
+	stderrR.Close()
+	if err != nil {
+		return err
+	}
+	go cmd.Wait()
+
+	if err := syscall.Dup2(int(stdoutW.Fd()), syscall.Stdout); err != nil {
+		return err
+	}
+	if err := syscall.Dup2(int(stderrW.Fd()), syscall.Stderr); err != nil {
+		return err
+	}
+	return os.Setenv(logTaggerEnv, "true")
+}
+
+// RunLogTagger tags the lines of the stdout and stderr of localkube, which TagLogs passes as the
+// file descriptors 3 and 4, and writes them to its own stdout and stderr. It ignores the signals
+// localkube handles, to tag the lines localkube logs while shutting down or restarting.
+func RunLogTagger() {
+	signal.Ignore(os.Interrupt, syscall.SIGTERM, syscall.SIGHUP)
+
+	resolve := func(string, int) string { return "localkube" }
+	if executable, err := os.Executable(); err != nil {
+		fmt.Fprintf(os.Stderr, "[localkube] Error finding the localkube executable, tagging glog lines as localkube: %s\n", err)
+	} else if index, err := newSourceIndex(executable); err != nil {
+		fmt.Fprintf(os.Stderr, "[localkube] Error reading the line table of localkube, tagging glog lines as localkube: %s\n", err)
+	} else {
+		resolve = index.component
+	}
+
+	wg := sync.WaitGroup{}
+	for _, stream := range []struct{ in, out *os.File }{
+		{os.NewFile(3, "localkube-stdout"), os.Stdout},
+		{os.NewFile(4, "localkube-stderr"), os.Stderr},
+	} {
+		wg.Add(1)
+		go func(in, out *os.File) {
+			defer wg.Done()
+			tagger := &logTagger{resolve: resolve, last: "localkube"}
+			if err := tagger.Tag(in, out); err != nil {
+				// Keep reading, so that localkube doesn't block writing its logs
+				io.Copy(ioutil.Discard, in)
+			}
+		}(stream.in, stream.out)
+	}
+	wg.Wait()
+}
+
+var (
+	glogHeader = regexp.MustCompile(`^[IWEF]\d{4} [\d:.]+ +\d+ ([^ :]+):(\d+)\] `)
+	etcdHeader = regexp.MustCompile(`^\d{4}-\d{2}-\d{2} [\d:.]+ [CEWNIDT] \| `)
+)
+
+// logTagger tags the lines of one output stream of localkube.
+type logTagger struct {
+	// resolve returns the component of the source file and line in a glog header.
+	resolve func(file string, line int) string
+	// last is the component of the last line, which the continuation lines after it belong to.
+	last string
+}
+
+// Tag copies the lines of in to out, each prefixed with the component that logged it.
+func (t *logTagger) Tag(in io.Reader, out io.Writer) error {
+	r := bufio.NewReader(in)
+	for {
+		line, err := r.ReadString('\n')
+		if line != "" {
+			t.last = t.component(line)
+			if _, err := io.WriteString(out, "["+t.last+"] "+line); err != nil {
+				return err
+			}
+		}
+		if err == io.EOF {
+			return nil
+		}
+		if err != nil {
+			return err
+		}
+	}
+}
+
+// component returns the component that logged line. Glog lines are attributed by the source file
+// in their header, the lines of the etcd logger by their header, and the messages of localkube
+// about a server by their "<server>: " prefix. Empty and indented lines continue the line before.
+func (t *logTagger) component(line string) string {
+	if s := strings.TrimRight(line, "\r\n"); s == "" || s[0] == ' ' || s[0] == '\t' {
+		return t.last
+	}
+	if m := glogHeader.FindStringSubmatch(line); m != nil {
+		n, _ := strconv.Atoi(m[2])
+		return t.resolve(m[1], n)
+	}
+	if etcdHeader.MatchString(line) {
+		return "etcd"
+	}
+	for _, c := range util.LogComponents {
+		if strings.HasPrefix(line, c+": ") {
+			return c
+		}
+	}
+	return "localkube"
+}
+
+// logComponentSources are the source files of the servers of localkube, by the import path of their
+// package or the path of the file. The first match wins, and the remaining files belong to localkube,
+// like the client libraries every server uses.
+var logComponentSources = []struct {
+	component string
+	paths     []string
+}{
+	{"apiserver", []string{
+		"k8s.io/minikube/pkg/localkube/apiserver.go",
+		"k8s.io/kubernetes/cmd/kube-apiserver/",
+		"k8s.io/kubernetes/pkg/master/",
+		"k8s.io/kubernetes/pkg/registry/",
+		"k8s.io/kubernetes/pkg/kubeapiserver/",
+		"k8s.io/kubernetes/plugin/pkg/admission/",
+		"k8s.io/kubernetes/plugin/pkg/auth/",
+		"k8s.io/apiserver/",
+		"k8s.io/kube-aggregator/",
+		"k8s.io/apiextensions-apiserver/",
+	}},
+	{"controller-manager", []string{
+		"k8s.io/minikube/pkg/localkube/controller-manager.go",
+		"k8s.io/kubernetes/cmd/kube-controller-manager/",
+		"k8s.io/kubernetes/pkg/controller/",
+	}},
+	{"scheduler", []string{
+		"k8s.io/minikube/pkg/localkube/scheduler.go",
+		"k8s.io/kubernetes/plugin/cmd/kube-scheduler/",
+		"k8s.io/kubernetes/plugin/pkg/scheduler/",
+	}},
+	{"kubelet", []string{
+		"k8s.io/minikube/pkg/localkube/kubelet.go",
+		"k8s.io/kubernetes/cmd/kubelet/",
+		"k8s.io/kubernetes/pkg/kubelet/",
+		"github.com/google/cadvisor/",
+	}},
+	{"proxy", []string{
+		"k8s.io/minikube/pkg/localkube/proxy.go",
+		"k8s.io/kubernetes/cmd/kube-proxy/",
+		"k8s.io/kubernetes/pkg/proxy/",
+	}},
+	{"etcd", []string{
+		"k8s.io/minikube/pkg/localkube/etcd",
+		"github.com/coreos/etcd/",
+	}},
+	{"rbac-bootstrap", []string{
+		"k8s.io/minikube/pkg/localkube/authorization.go",
+	}},
+	{"storage-provisioner", []string{
+		"k8s.io/minikube/pkg/localkube/storage_provisioner.go",
+		"k8s.io/minikube/pkg/localkube/volume_snapshots.go",
+		"github.com/r2d4/external-storage/",
+	}},
+}
+
+// sourceComponent returns the component the source file at path belongs to.
+func sourceComponent(path string) string {
+	for _, s := range logComponentSources {
+		for _, p := range s.paths {
+			if strings.Contains(path, p) {
+				return s.component
+			}
+		}
+	}
+	return "localkube"
+}
+
+// sourceIndex attributes the source files in glog headers, which only have their base name, to
+// components, by looking them up in the line table of the localkube executable.
+type sourceIndex struct {
+	table *gosym.Table
+	// paths are the full paths of the source files compiled into localkube, by base name.
+	paths map[string][]string
+
+	mu         sync.Mutex
+	components map[string]string
+}
+
+func newSourceIndex(executable string) (*sourceIndex, error) {
+	f, err := elf.Open(executable)
+	if err != nil {
+		return nil, err
+	}
+	defer f.Close()
+	text, pclntab := f.Section(".text"), f.Section(".gopclntab")
+	if text == nil || pclntab == nil {
+		return nil, fmt.Errorf("%s has no Go line table", executable)
+	}
+	data, err := pclntab.Data()
+	if err != nil {
+		return nil, err
+	}
+	table, err := gosym.NewTable(nil, gosym.NewLineTable(data, text.Addr))
+	if err != nil {
+		return nil, err
+	}
+
+	paths := map[string][]string{}
+	seen := map[string]bool{}
+	for _, fn := range table.Funcs {
+		file, _, _ := table.PCToLine(fn.Entry)
+		if file == "" || seen[file] {
+			continue
+		}
+		seen[file] = true
+		base := filepath.Base(file)
+		paths[base] = append(paths[base], file)
+	}
+	return &sourceIndex{table: table, paths: paths, components: map[string]string{}}, nil
+}
+
+// component returns the component of the source file named file. When several files have that
+// name in different components, the one with code at line is picked, and the line is attributed to
+// localkube if that doesn't tell them apart.
+func (s *sourceIndex) component(file string, line int) string {
+	key := file + ":" + strconv.Itoa(line)
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	if c, ok := s.components[key]; ok {
+		return c
+	}
+
+	paths := s.paths[file]
+	c, ok := sameComponent(paths)
+	if !ok {
+		atLine := []string{}
+		for _, p := range paths {
+			if _, _, err := s.table.LineToPC(p, line); err == nil {
+				atLine = append(atLine, p)
+			}
+		}
+		if c, ok = sameComponent(atLine); !ok {
+			c = "localkube"
+		}
+	}
+	s.components[key] = c
+	return c
+}
+
+// sameComponent returns the component of the source files at paths, if they all belong to the same one.
+func sameComponent(paths []string) (string, bool) {
+	if len(paths) == 0 {
+		return "", false
+	}
+	c := sourceComponent(paths[0])
+	for _, p := range paths[1:] {
+		if sourceComponent(p) != c {
+			return "", false
+		}
+	}
+	return c, true
+}
@@ -0,0 +1,113 @@
+/*
+Copyright 2016 The Kubernetes Authors All rights reserved.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package localkube
+
+import (
+	"bytes"
+	"os"
+	"path/filepath"
+	"reflect"
+	"runtime"
+	"strconv"
+	"strings"
+	"testing"
+)
+
+func TestLogTagger(t *testing.T) {
+	logs := `Starting etcd...
+2017-10-16 12:00:01.000000 I | etcdserver: name = kubeetcd
+I1016 12:00:02.000000    1234 serve.go:85] Serving securely on 0.0.0.0:8443
+apiserver: Exit with error: bind: address already in use
+
+E1016 12:00:04.000000    1234 proxier.go:1032] Failed to execute iptables-restore: exit status 1
+  iptables-restore: line 7 failed
+W1016 12:00:06.000000    1234 reflector.go:323] watch of *v1.Pod ended with: too old resource version
+no newline`
+	expected := `[localkube] Starting etcd...
+[etcd] 2017-10-16 12:00:01.000000 I | etcdserver: name = kubeetcd
+[apiserver] I1016 12:00:02.000000    1234 serve.go:85] Serving securely on 0.0.0.0:8443
+[apiserver] apiserver: Exit with error: bind: address already in use
+[apiserver] 
+[proxy] E1016 12:00:04.000000    1234 proxier.go:1032] Failed to execute iptables-restore: exit status 1
+[proxy]   iptables-restore: line 7 failed
+[localkube] W1016 12:00:06.000000    1234 reflector.go:323] watch of *v1.Pod ended with: too old resource version
+[localkube] no newline`
+
+	files := map[string]string{"serve.go:85": "apiserver", "proxier.go:1032": "proxy"}
+	tagger := &logTagger{
+		resolve: func(file string, line int) string {
+			if c, ok := files[file+":"+strconv.Itoa(line)]; ok {
+				return c
+			}
+			return "localkube"
+		},
+		last: "localkube",
+	}
+	out := bytes.Buffer{}
+	if err := tagger.Tag(strings.NewReader(logs), &out); err != nil {
+		t.Fatalf("Error tagging logs: %s", err)
+	}
+	if out.String() != expected {
+		t.Errorf("Expected tagged logs:\n%s\ngot:\n%s", expected, out.String())
+	}
+}
+
+func TestSourceComponent(t *testing.T) {
+	for path, expected := range map[string]string{
+		"/go/src/k8s.io/minikube/vendor/k8s.io/apiserver/pkg/server/serve.go":                       "apiserver",
+		"/go/src/k8s.io/minikube/vendor/k8s.io/kubernetes/pkg/controller/replicaset/replica_set.go": "controller-manager",
+		"/go/src/k8s.io/minikube/vendor/k8s.io/kubernetes/pkg/kubelet/kubelet.go":                   "kubelet",
+		"/go/src/k8s.io/minikube/vendor/k8s.io/kubernetes/pkg/proxy/iptables/proxier.go":            "proxy",
+		"/go/src/k8s.io/minikube/vendor/github.com/coreos/etcd/etcdserver/server.go":                "etcd",
+		"/go/src/k8s.io/minikube/vendor/k8s.io/client-go/tools/cache/reflector.go":                  "localkube",
+		"/go/src/k8s.io/minikube/pkg/localkube/storage_provisioner.go":                              "storage-provisioner",
+		"/go/src/k8s.io/minikube/pkg/localkube/authorization.go":                                    "rbac-bootstrap",
+		"/go/src/k8s.io/minikube/pkg/localkube/supervisor.go":                                       "localkube",
+	} {
+		if c := sourceComponent(path); c != expected {
+			t.Errorf("Expected %s to belong to %s, got %s", path, expected, c)
+		}
+	}
+}
+
+func TestSourceIndex(t *testing.T) {
+	executable, err := os.Executable()
+	if err != nil {
+		t.Fatalf("Error finding the test executable: %s", err)
+	}
+	index, err := newSourceIndex(executable)
+	if err != nil {
+		t.Skipf("The test executable has no line table: %s", err)
+	}
+
+	for _, test := range []struct {
+		fn       interface{}
+		expected string
+	}{
+		{StartStorageProvisioner, "storage-provisioner"},
+		{StartKubeletServer, "kubelet"},
+		{ChangedFlags, "localkube"},
+	} {
+		file, line := runtime.FuncForPC(reflect.ValueOf(test.fn).Pointer()).FileLine(reflect.ValueOf(test.fn).Pointer())
+		if c := index.component(filepath.Base(file), line); c != test.expected {
+			t.Errorf("Expected %s:%d to belong to %s, got %s", file, line, test.expected, c)
+		}
+	}
+	if c := index.component("missing.go", 1); c != "localkube" {
+		t.Errorf("Expected a missing file to belong to localkube, got %s", c)
+	}
+}
@@ -0,0 +1,222 @@
+/*
+Copyright 2016 The Kubernetes Authors All rights reserved.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package localkube
+
+import (
+	"fmt"
+	"net/http"
+	"strings"
+	"time"
+
+	"github.com/golang/glog"
+	"github.com/golang/protobuf/proto"
+	"github.com/pkg/errors"
+	"github.com/prometheus/client_golang/prometheus"
+	"github.com/prometheus/client_golang/prometheus/promhttp"
+	dto "github.com/prometheus/client_model/go"
+	"github.com/prometheus/common/expfmt"
+	"k8s.io/kubernetes/pkg/master/ports"
+
+	"k8s.io/minikube/pkg/util"
+)
+
+// localkubeComponent is the component of the metrics of localkube itself, and of code shared by
+// the components, like the Go runtime and the REST clients.
+const localkubeComponent = "localkube"
+
+// metricScrapeTimeout is how long scraping the metrics of a component process may take.
+const metricScrapeTimeout = 5 * time.Second
+
+// componentMetricsURLs are the metrics endpoints of the components running in their own process,
+// which localkube scrapes.
+var componentMetricsURLs = map[string]string{
+	"kubelet":            fmt.Sprintf("http://127.0.0.1:%d/metrics", ports.KubeletReadOnlyPort),
+	"proxy":              fmt.Sprintf("http://127.0.0.1:%d/metrics", ports.ProxyStatusPort),
+	"scheduler":          fmt.Sprintf("http://127.0.0.1:%d/metrics", ports.SchedulerPort),
+	"controller-manager": fmt.Sprintf("http://127.0.0.1:%d/metrics", ports.ControllerManagerPort),
+}
+
+// metricComponents maps metric name prefixes to the component registering them, the first match
+// winning. The components running in the localkube process register their metrics in init
+// functions of the vendored packages, all with Prometheus's process-wide default registry, so
+// localkube can't give each component its own registry. The label is a best-effort guess from the
+// name: metrics without a component prefix are labelled localkube.
+var metricComponents = []struct {
+	prefix    string
+	component string
+}{
+	{"apiserver_", "apiserver"},
+	{"authenticated_user_requests", "apiserver"},
+	// The apiserver's etcd client, not the embedded etcd
+	{"etcd_helper_", "apiserver"},
+	{"etcd_request_", "apiserver"},
+	{"etcd_", EtcdName},
+	{"grpc_server_", EtcdName},
+	{"scheduler_", "scheduler"},
+	{"kubelet_", "kubelet"},
+	{"kubeproxy_", "proxy"},
+	{"node_collector_", "controller-manager"},
+}
+
+// metricComponent returns the component the metric named name most likely belongs to.
+func metricComponent(name string) string {
+	for _, m := range metricComponents {
+		if strings.HasPrefix(name, m.prefix) {
+			return m.component
+		}
+	}
+	return localkubeComponent
+}
+
+// labelComponents adds a component label to every metric that doesn't have one yet.
+func labelComponents(families []*dto.MetricFamily) {
+	for _, f := range families {
+		labelComponent(f, metricComponent(f.GetName()))
+	}
+}
+
+// labelComponent labels the metrics of f that have no component label yet with component.
+func labelComponent(f *dto.MetricFamily, component string) {
+	for _, m := range f.GetMetric() {
+		if hasLabel(m, util.MetricsComponentLabel) {
+			continue
+		}
+		m.Label = append(m.Label, &dto.LabelPair{
+			Name:  proto.String(util.MetricsComponentLabel),
+			Value: proto.String(component),
+		})
+	}
+}
+
+// componentGatherer scrapes the metrics of a component running in its own process, and labels
+// them with the component.
+type componentGatherer struct {
+	component string
+	url       string
+}
+
+func (g componentGatherer) Gather() ([]*dto.MetricFamily, error) {
+	client := http.Client{Timeout: metricScrapeTimeout}
+	resp, err := client.Get(g.url)
+	if err != nil {
+		return nil, errors.Wrapf(err, "Error scraping the metrics of %s", g.component)
+	}
+	defer resp.Body.Close()
+	if resp.StatusCode != http.StatusOK {
+		return nil, errors.Errorf("Error scraping the metrics of %s: %s", g.component, resp.Status)
+	}
+
+	parser := expfmt.TextParser{}
+	parsed, err := parser.TextToMetricFamilies(resp.Body)
+	if err != nil {
+		return nil, errors.Wrapf(err, "Error parsing the metrics of %s", g.component)
+	}
+	families := []*dto.MetricFamily{}
+	for _, f := range parsed {
+		labelComponent(f, g.component)
+		families = append(families, f)
+	}
+	return families, nil
+}
+
+func hasLabel(m *dto.Metric, name string) bool {
+	for _, l := range m.GetLabel() {
+		if l.GetName() == name {
+			return true
+		}
+	}
+	return false
+}
+
+var (
+	componentReadyDesc = prometheus.NewDesc("localkube_component_ready",
+		"Whether the localkube component is ready.", []string{util.MetricsComponentLabel}, nil)
+	componentFailedDesc = prometheus.NewDesc("localkube_component_failed",
+		"Whether the localkube component crash looped and is no longer restarted.", []string{util.MetricsComponentLabel}, nil)
+	componentRestartsDesc = prometheus.NewDesc("localkube_component_restarts_total",
+		"The number of times the localkube component was restarted after exiting.", []string{util.MetricsComponentLabel}, nil)
+	componentUptimeDesc = prometheus.NewDesc("localkube_component_uptime_seconds",
+		"How long the localkube component has been running since it was last started.", []string{util.MetricsComponentLabel}, nil)
+)
+
+// supervisorCollector collects the readiness and restarts of the localkube servers.
+type supervisorCollector struct {
+	servers Servers
+}
+
+func (c supervisorCollector) Describe(ch chan<- *prometheus.Desc) {
+	ch <- componentReadyDesc
+	ch <- componentFailedDesc
+	ch <- componentRestartsDesc
+	ch <- componentUptimeDesc
+}
+
+func (c supervisorCollector) Collect(ch chan<- prometheus.Metric) {
+	for _, server := range c.servers {
+		ready, err := server.Ready()
+		if err != nil {
+			glog.Errorf("Error checking if %s is ready: %s", server.Name(), err)
+		}
+		s := server.Status()
+		uptime := 0.0
+		if !s.StartTime.IsZero() {
+			uptime = time.Since(s.StartTime).Seconds()
+		}
+		ch <- prometheus.MustNewConstMetric(componentReadyDesc, prometheus.GaugeValue, boolToFloat(ready), server.Name())
+		ch <- prometheus.MustNewConstMetric(componentFailedDesc, prometheus.GaugeValue, boolToFloat(s.Failed), server.Name())
+		ch <- prometheus.MustNewConstMetric(componentRestartsDesc, prometheus.CounterValue, float64(s.Restarts), server.Name())
+		ch <- prometheus.MustNewConstMetric(componentUptimeDesc, prometheus.GaugeValue, uptime, server.Name())
+	}
+}
+
+func boolToFloat(b bool) float64 {
+	if b {
+		return 1
+	}
+	return 0
+}
+
+// MetricsHandler serves the Prometheus metrics of every component of localkube, and of its
+// supervision of the servers. Each metric is labelled with the component it belongs to, which is
+// exact for the supervision metrics and the components running in their own process, and guessed
+// from the name for the others.
+func (servers Servers) MetricsHandler() http.Handler {
+	registry := prometheus.NewRegistry()
+	registry.MustRegister(supervisorCollector{servers})
+	gatherers := prometheus.Gatherers{prometheus.DefaultGatherer, registry}
+	for _, server := range servers {
+		if url, ok := componentMetricsURLs[server.Name()]; ok {
+			gatherers = append(gatherers, componentGatherer{component: server.Name(), url: url})
+		}
+	}
+	gatherer := prometheus.GathererFunc(func() ([]*dto.MetricFamily, error) {
+		families, err := gatherers.Gather()
+		labelComponents(families)
+		return families, err
+	})
+	return promhttp.HandlerFor(gatherer, promhttp.HandlerOpts{
+		ErrorLog:      metricsErrorLog{},
+		ErrorHandling: promhttp.ContinueOnError,
+	})
+}
+
+// metricsErrorLog logs the errors gathering metrics, which are skipped.
+type metricsErrorLog struct{}
+
+func (metricsErrorLog) Println(v ...interface{}) {
+	glog.Warningln(v...)
+}
@@ -0,0 +1,114 @@
+/*
+Copyright 2016 The Kubernetes Authors All rights reserved.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package localkube
+
+import (
+	"io/ioutil"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+
+	"github.com/prometheus/client_golang/prometheus"
+
+	"k8s.io/minikube/pkg/util"
+)
+
+func TestMetricComponent(t *testing.T) {
+	var tests = []struct {
+		name      string
+		component string
+	}{
+		{"apiserver_request_count", "apiserver"},
+		{"etcd_request_latencies_summary", "apiserver"},
+		{"etcd_disk_wal_fsync_duration_seconds", EtcdName},
+		{"scheduler_e2e_scheduling_latency_microseconds", "scheduler"},
+		{"kubelet_pod_start_latency_microseconds", "kubelet"},
+		{"kubeproxy_sync_proxy_rules_latency_microseconds", "proxy"},
+		{"node_collector_evictions_number", "controller-manager"},
+		// Work queue metrics are named after the queue, not the component running it
+		{"deployment_queue_latency", localkubeComponent},
+		{"go_goroutines", localkubeComponent},
+		{"rest_client_request_latency_seconds", localkubeComponent},
+	}
+	for _, test := range tests {
+		if component := metricComponent(test.name); component != test.component {
+			t.Errorf("Expected metric %s to belong to %s, got %s", test.name, test.component, component)
+		}
+	}
+}
+
+func TestMetricsHandler(t *testing.T) {
+	requests := prometheus.NewCounter(prometheus.CounterOpts{
+		Name: "apiserver_test_requests_total",
+		Help: "Requests counted by the test.",
+	})
+	prometheus.MustRegister(requests)
+	defer prometheus.Unregister(requests)
+	requests.Inc()
+
+	apiserver := &fakeServer{name: "apiserver", ready: true}
+	apiserver.Start()
+	servers := Servers{apiserver, &fakeServer{name: "kubelet", ready: true}}
+	server := httptest.NewServer(servers.MetricsHandler())
+	defer server.Close()
+
+	resp, err := http.Get(server.URL + util.LocalkubeMetricsPath)
+	if err != nil {
+		t.Fatalf("Error getting metrics: %s", err)
+	}
+	defer resp.Body.Close()
+	body, err := ioutil.ReadAll(resp.Body)
+	if err != nil {
+		t.Fatalf("Error reading metrics: %s", err)
+	}
+	for _, sample := range []string{
+		`apiserver_test_requests_total{component="apiserver"} 1`,
+		`localkube_component_ready{component="apiserver"} 1`,
+		`localkube_component_ready{component="kubelet"} 0`,
+		`localkube_component_restarts_total{component="kubelet"} 0`,
+		`go_goroutines{component="localkube"}`,
+	} {
+		if !strings.Contains(string(body), sample) {
+			t.Errorf("Expected metrics to contain %s, got:\n%s", sample, body)
+		}
+	}
+}
+
+func TestComponentGatherer(t *testing.T) {
+	kubelet := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.Write([]byte("# HELP go_goroutines Number of goroutines that currently exist.\n# TYPE go_goroutines gauge\ngo_goroutines 42\n"))
+	}))
+	defer kubelet.Close()
+
+	families, err := componentGatherer{component: "kubelet", url: kubelet.URL}.Gather()
+	if err != nil {
+		t.Fatalf("Error gathering the metrics of the kubelet: %s", err)
+	}
+	if len(families) != 1 || len(families[0].GetMetric()) != 1 {
+		t.Fatalf("Expected one metric, got %v", families)
+	}
+	m := families[0].GetMetric()[0]
+	if !hasLabel(m, util.MetricsComponentLabel) || m.GetLabel()[0].GetValue() != "kubelet" {
+		t.Errorf("Expected the metric to be labelled with the kubelet, got %v", m.GetLabel())
+	}
+
+	kubelet.Close()
+	if _, err := (componentGatherer{component: "kubelet", url: kubelet.URL}).Gather(); err == nil {
+		t.Error("Expected an error scraping a component that isn't running")
+	}
+}
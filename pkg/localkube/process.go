@@ -0,0 +1,107 @@
+/*
+Copyright 2016 The Kubernetes Authors All rights reserved.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package localkube
+
+import (
+	"fmt"
+	"os"
+	"os/exec"
+	"runtime"
+	"syscall"
+	"time"
+
+	"github.com/pkg/errors"
+)
+
+// ComponentEnv is set in the environment of the localkube processes running a single component,
+// to the name of the component.
+const ComponentEnv = "LOCALKUBE_COMPONENT"
+
+// componentStopTimeout is how long a component process is given to exit after SIGTERM before it
+// is killed.
+const componentStopTimeout = 10 * time.Second
+
+// newComponentServer returns a server running the named component in a child localkube process,
+// started with the same arguments as localkube and ComponentEnv set. The Kubernetes components
+// can't be stopped once they run, so running them in their own process is what lets localkube
+// stop and restart them one by one.
+func newComponentServer(name string, ready HealthCheck, deps ...string) *SimpleServer {
+	return NewSimpleServer(name, serverInterval, func(stop <-chan struct{}) error {
+		return runComponentProcess(name, stop)
+	}, ready, deps...)
+}
+
+// RunComponent runs the named component in the foreground, in the process started for it by
+// localkube. It returns when the component exits.
+func (lk LocalkubeServer) RunComponent(name string) error {
+	switch name {
+	case "kubelet":
+		return StartKubeletServer(lk)()
+	case "proxy":
+		return StartProxyServer(lk)()
+	case "scheduler":
+		return StartSchedulerServer(lk)()
+	case "controller-manager":
+		return StartControllerManagerServer(lk)()
+	}
+	return fmt.Errorf("unknown component %s", name)
+}
+
+func runComponentProcess(name string, stop <-chan struct{}) error {
+	executable, err := os.Executable()
+	if err != nil {
+		return errors.Wrap(err, "Error finding the localkube executable")
+	}
+	cmd := exec.Command(executable, os.Args[1:]...)
+	cmd.Env = append(os.Environ(), fmt.Sprintf("%s=%s", ComponentEnv, name))
+	cmd.Stdout = os.Stdout
+	cmd.Stderr = os.Stderr
+	// Kill the component if localkube dies. The signal is sent when the thread that started the
+	// process exits, so keep this goroutine on its thread while the process runs.
+	cmd.SysProcAttr = &syscall.SysProcAttr{Pdeathsig: syscall.SIGKILL}
+	runtime.LockOSThread()
+	defer runtime.UnlockOSThread()
+	return runProcess(cmd, stop)
+}
+
+// runProcess runs cmd until it exits or stop is closed. Once stop is closed the process is sent
+// SIGTERM, and killed if it is still running after componentStopTimeout.
+func runProcess(cmd *exec.Cmd, stop <-chan struct{}) error {
+	if err := cmd.Start(); err != nil {
+		return err
+	}
+	exited := make(chan error, 1)
+	go func() {
+		exited <- cmd.Wait()
+	}()
+
+	select {
+	case err := <-exited:
+		return err
+	case <-stop:
+	}
+	if err := cmd.Process.Signal(syscall.SIGTERM); err != nil {
+		return <-exited
+	}
+	select {
+	case <-exited:
+	case <-time.After(componentStopTimeout):
+		cmd.Process.Kill()
+		<-exited
+	}
+	return nil
+}
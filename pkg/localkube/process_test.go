@@ -0,0 +1,50 @@
+/*
+Copyright 2016 The Kubernetes Authors All rights reserved.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package localkube
+
+import (
+	"os/exec"
+	"testing"
+	"time"
+)
+
+func TestRunProcessStop(t *testing.T) {
+	stop := make(chan struct{})
+	done := make(chan error, 1)
+	go func() {
+		done <- runProcess(exec.Command("sleep", "60"), stop)
+	}()
+	close(stop)
+
+	select {
+	case err := <-done:
+		if err != nil {
+			t.Errorf("Expected a stopped process to return no error, got %s", err)
+		}
+	case <-time.After(5 * time.Second):
+		t.Fatal("The process was not stopped")
+	}
+}
+
+func TestRunProcessExit(t *testing.T) {
+	if err := runProcess(exec.Command("false"), make(chan struct{})); err == nil {
+		t.Error("Expected an error when the process fails")
+	}
+	if err := runProcess(exec.Command("true"), make(chan struct{})); err != nil {
+		t.Errorf("Unexpected error when the process succeeds: %s", err)
+	}
+}
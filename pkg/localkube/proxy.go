@@ -19,12 +19,14 @@ package localkube
 import (
 	kubeproxy "k8s.io/kubernetes/cmd/kube-proxy/app"
 
+	"fmt"
 	"time"
 
 	"k8s.io/apimachinery/pkg/apis/meta/v1"
 	"k8s.io/apimachinery/pkg/runtime"
 	"k8s.io/kubernetes/pkg/apis/componentconfig"
 	"k8s.io/kubernetes/pkg/kubelet/qos"
+	"k8s.io/kubernetes/pkg/master/ports"
 )
 
 var (
@@ -33,7 +35,16 @@ var (
 )
 
 func (lk LocalkubeServer) NewProxyServer() Server {
-	return NewSimpleServer("proxy", serverInterval, StartProxyServer(lk), noop)
+	return newComponentServer("proxy", noop, lk.apiserverDependencies()...)
+}
+
+// GetClusterCIDR returns the CIDR of the pods in the cluster, which is the pod CIDR of the node
+// unless another one was set.
+func (lk LocalkubeServer) GetClusterCIDR() string {
+	if lk.ClusterCIDR != "" {
+		return lk.ClusterCIDR
+	}
+	return lk.PodCIDR.String()
 }
 
 func StartProxyServer(lk LocalkubeServer) func() error {
@@ -46,21 +57,32 @@ func StartProxyServer(lk LocalkubeServer) func() error {
 		ConfigSyncPeriod: v1.Duration{Duration: 15 * time.Minute},
 		IPTables: componentconfig.KubeProxyIPTablesConfiguration{
 			MasqueradeBit: &MasqueradeBit,
+			MasqueradeAll: lk.MasqueradeAll,
 			SyncPeriod:    v1.Duration{Duration: 30 * time.Second},
 			MinSyncPeriod: v1.Duration{Duration: 5 * time.Second},
 		},
 		BindAddress:  lk.APIServerInsecureAddress.String(),
-		Mode:         componentconfig.ProxyModeIPTables,
+		Mode:         componentconfig.ProxyMode(lk.ProxyMode),
+		ClusterCIDR:  lk.GetClusterCIDR(),
 		FeatureGates: lk.FeatureGates,
 		// Disable the healthz check
 		HealthzBindAddress: "0",
+		// Serve the metrics for localkube to scrape
+		MetricsBindAddress: fmt.Sprintf("127.0.0.1:%d", ports.ProxyStatusPort),
+	}
+
+	// A worker connects to the joined apiserver, there is none on its own node
+	master := lk.GetAPIServerInsecureURL()
+	if lk.IsWorker() {
+		config.ClientConnection.KubeConfigFile = lk.GetWorkerKubeconfigPath()
+		master = ""
 	}
 
 	lk.SetExtraConfigForComponent("proxy", &config)
 
 	return func() error {
 		// Creating this config requires the API Server to be up, so do it in the start function itself.
-		server, err := kubeproxy.NewProxyServer(config, false, runtime.NewScheme(), lk.GetAPIServerInsecureURL())
+		server, err := kubeproxy.NewProxyServer(config, false, runtime.NewScheme(), master)
 		if err != nil {
 			panic(err)
 		}
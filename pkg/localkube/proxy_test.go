@@ -0,0 +1,34 @@
+/*
+Copyright 2016 The Kubernetes Authors All rights reserved.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package localkube
+
+import (
+	"net"
+	"testing"
+)
+
+func TestGetClusterCIDR(t *testing.T) {
+	_, podCIDR, _ := net.ParseCIDR("10.180.1.0/24")
+	lk := LocalkubeServer{PodCIDR: *podCIDR}
+	if cidr := lk.GetClusterCIDR(); cidr != "10.180.1.0/24" {
+		t.Errorf("Expected the pod CIDR to be the cluster CIDR, got %s", cidr)
+	}
+	lk.ClusterCIDR = "10.180.0.0/16"
+	if cidr := lk.GetClusterCIDR(); cidr != "10.180.0.0/16" {
+		t.Errorf("Expected cluster CIDR 10.180.0.0/16, got %s", cidr)
+	}
+}
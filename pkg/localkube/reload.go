@@ -0,0 +1,150 @@
+/*
+Copyright 2016 The Kubernetes Authors All rights reserved.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package localkube
+
+import (
+	"reflect"
+	"sort"
+
+	flag "github.com/spf13/pflag"
+
+	"k8s.io/minikube/pkg/util"
+)
+
+// componentFlags maps the flags that configure a single component to the server running it. The
+// other flags configure several components, the certificates, etcd or localkube itself, except for
+// reloadedFlags.
+var componentFlags = map[string]string{
+	"containerized":                     "kubelet",
+	"dns-ip":                            "kubelet",
+	"node-ip":                           "kubelet",
+	"container-runtime":                 "kubelet",
+	"network-plugin":                    "kubelet",
+	"proxy-mode":                        "proxy",
+	"masquerade-all":                    "proxy",
+	"runtime-config":                    "apiserver",
+	"admission-control":                 "apiserver",
+	"authorization-webhook-config-file": "apiserver",
+	"audit-log-maxage":                  "apiserver",
+	"audit-log-maxbackup":               "apiserver",
+	"audit-log-maxsize":                 "apiserver",
+	"hostpath-provisioner-dir":          "storage-provisioner",
+	"hostpath-provisioner-enforce-size": "storage-provisioner",
+	"storage-class-dirs":                "storage-provisioner",
+}
+
+// reloadedFlags are the flags localkube applies without restarting any server.
+var reloadedFlags = map[string]bool{
+	"enable-dns":               true,
+	"ready-timeout":            true,
+	"component-ready-timeouts": true,
+	"shutdown-grace-period":    true,
+}
+
+// ChangedFlags returns the names of the flags whose value differs between current and updated,
+// which are flag sets of the same flags bound to two configurations. Flags missing from updated
+// are reported as changed.
+func ChangedFlags(current, updated *flag.FlagSet) []string {
+	changed := []string{}
+	current.VisitAll(func(f *flag.Flag) {
+		u := updated.Lookup(f.Name)
+		if u == nil || u.Value.String() != f.Value.String() {
+			changed = append(changed, f.Name)
+		}
+	})
+	return changed
+}
+
+// PlanReload returns the names of the servers to restart to go from the configuration current to
+// updated, given the flags that changed between them. full is set instead when a changed flag
+// isn't specific to one server, and localkube has to restart as a whole, etcd included.
+func PlanReload(current, updated LocalkubeServer, changed []string) (restart []string, full bool) {
+	servers := map[string]bool{}
+	for _, name := range changed {
+		switch {
+		case reloadedFlags[name]:
+		case name == "extra-config":
+			for _, component := range changedExtraConfig(current.ExtraConfig, updated.ExtraConfig) {
+				if component == EtcdName {
+					return nil, true
+				}
+				servers[component] = true
+			}
+		case componentFlags[name] != "":
+			servers[componentFlags[name]] = true
+		default:
+			return nil, true
+		}
+	}
+
+	restart = []string{}
+	for server := range servers {
+		restart = append(restart, server)
+	}
+	sort.Strings(restart)
+	return restart, false
+}
+
+// changedExtraConfig returns the components whose extra config differs between current and updated.
+func changedExtraConfig(current, updated util.ExtraOptionSlice) []string {
+	byComponent := func(options util.ExtraOptionSlice) map[string][]string {
+		m := map[string][]string{}
+		for _, o := range options {
+			m[o.Component] = append(m[o.Component], o.Key+"="+o.Value)
+		}
+		return m
+	}
+	c, u := byComponent(current), byComponent(updated)
+
+	components := map[string]bool{}
+	for component := range c {
+		components[component] = true
+	}
+	for component := range u {
+		components[component] = true
+	}
+	changed := []string{}
+	for component := range components {
+		if !reflect.DeepEqual(c[component], u[component]) {
+			changed = append(changed, component)
+		}
+	}
+	sort.Strings(changed)
+	return changed
+}
+
+// NewServer creates the named server from the configuration lk, or returns nil if localkube has
+// no such server.
+func (lk LocalkubeServer) NewServer(name string) Server {
+	switch name {
+	case "apiserver":
+		return lk.NewAPIServer()
+	case "controller-manager":
+		return lk.NewControllerManagerServer()
+	case "scheduler":
+		return lk.NewSchedulerServer()
+	case "kubelet":
+		return lk.NewKubeletServer()
+	case "proxy":
+		return lk.NewProxyServer()
+	case "storage-provisioner":
+		return lk.NewStorageProvisionerServer()
+	case "rbac-bootstrap":
+		return lk.NewRBACBootstrapServer()
+	}
+	return nil
+}
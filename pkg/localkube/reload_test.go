@@ -0,0 +1,149 @@
+/*
+Copyright 2016 The Kubernetes Authors All rights reserved.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package localkube
+
+import (
+	"reflect"
+	"testing"
+
+	flag "github.com/spf13/pflag"
+
+	"k8s.io/minikube/pkg/util"
+)
+
+func newReloadFlagSet(lk *LocalkubeServer) *flag.FlagSet {
+	fs := flag.NewFlagSet("test", flag.ContinueOnError)
+	fs.StringVar(&lk.DNSDomain, "dns-domain", lk.DNSDomain, "")
+	fs.Var(&lk.ExtraConfig, "extra-config", "")
+	return fs
+}
+
+func TestChangedFlags(t *testing.T) {
+	current := LocalkubeServer{
+		DNSDomain:   "cluster.local",
+		ExtraConfig: util.ExtraOptionSlice{{Component: "kubelet", Key: "MaxPods", Value: "10"}},
+	}
+
+	tests := []struct {
+		description string
+		args        []string
+		expected    []string
+	}{
+		{
+			description: "nothing changed",
+			args:        []string{"--dns-domain=cluster.local"},
+			expected:    []string{},
+		},
+		{
+			description: "extra config changed",
+			args:        []string{"--extra-config=apiserver.Authorization.Mode=RBAC"},
+			expected:    []string{"extra-config"},
+		},
+		{
+			description: "setting changed",
+			args:        []string{"--dns-domain=example.com"},
+			expected:    []string{"dns-domain"},
+		},
+	}
+
+	for _, test := range tests {
+		updated := current
+		updated.ExtraConfig = append(util.ExtraOptionSlice{}, current.ExtraConfig...)
+		fs := newReloadFlagSet(&updated)
+		if err := fs.Parse(test.args); err != nil {
+			t.Fatalf("Error parsing %v: %s", test.args, err)
+		}
+		if changed := ChangedFlags(newReloadFlagSet(&current), fs); !reflect.DeepEqual(changed, test.expected) {
+			t.Errorf("Expected %v to change when %s, got %v", test.expected, test.description, changed)
+		}
+	}
+}
+
+func TestPlanReload(t *testing.T) {
+	current := LocalkubeServer{
+		ExtraConfig: util.ExtraOptionSlice{{Component: "kubelet", Key: "MaxPods", Value: "10"}},
+	}
+
+	tests := []struct {
+		description string
+		changed     []string
+		extraConfig util.ExtraOptionSlice
+		restart     []string
+		full        bool
+	}{
+		{
+			description: "a kubelet setting changed",
+			changed:     []string{"network-plugin"},
+			restart:     []string{"kubelet"},
+		},
+		{
+			description: "settings of the proxy and the storage provisioner changed",
+			changed:     []string{"storage-class-dirs", "masquerade-all"},
+			restart:     []string{"proxy", "storage-provisioner"},
+		},
+		{
+			description: "a readiness timeout changed",
+			changed:     []string{"ready-timeout"},
+			restart:     []string{},
+		},
+		{
+			description: "extra config of the apiserver was added",
+			changed:     []string{"extra-config"},
+			extraConfig: util.ExtraOptionSlice{
+				{Component: "kubelet", Key: "MaxPods", Value: "10"},
+				{Component: "apiserver", Key: "Authorization.Mode", Value: "RBAC"},
+			},
+			restart: []string{"apiserver"},
+		},
+		{
+			description: "extra config of the kubelet was removed",
+			changed:     []string{"extra-config"},
+			restart:     []string{"kubelet"},
+		},
+		{
+			description: "extra config of etcd changed",
+			changed:     []string{"extra-config"},
+			extraConfig: util.ExtraOptionSlice{
+				{Component: "kubelet", Key: "MaxPods", Value: "10"},
+				{Component: EtcdName, Key: "SnapCount", Value: "100"},
+			},
+			full: true,
+		},
+		{
+			description: "a setting shared by the kubelet and the proxy changed",
+			changed:     []string{"network-plugin", "pod-cidr"},
+			full:        true,
+		},
+		{
+			description: "the certificates' names changed",
+			changed:     []string{"apiserver-extra-sans"},
+			full:        true,
+		},
+	}
+
+	for _, test := range tests {
+		updated := current
+		updated.ExtraConfig = test.extraConfig
+		restart, full := PlanReload(current, updated, test.changed)
+		if full != test.full {
+			t.Errorf("Expected a full restart to be %t when %s, got %t", test.full, test.description, full)
+		}
+		if !test.full && !reflect.DeepEqual(restart, test.restart) {
+			t.Errorf("Expected %v to restart when %s, got %v", test.restart, test.description, restart)
+		}
+	}
+}
@@ -17,10 +17,8 @@ limitations under the License.
 package localkube
 
 import (
-	"os"
+	"sync"
 	"time"
-
-	"k8s.io/minikube/pkg/util"
 )
 
 // Server represents a component that Kubernetes depends on. It allows for the management of
@@ -36,44 +34,101 @@ type Server interface {
 	Name() string
 
 	Ready() (bool, error)
+
+	// Dependencies returns the names of the components that must be ready before this one is started.
+	Dependencies() []string
+
+	// Status returns the restart history of the component.
+	Status() ServerStatus
 }
 
-// SimpleServer provides a minimal implementation of Server.
+// SimpleServer provides a minimal implementation of Server. The server routine is supervised:
+// when it exits it is restarted with an exponential backoff starting at Interval and capped at
+// MaxInterval, and after CrashLoopThreshold consecutive short-lived runs it is marked failed.
+//
+// The server routine must return once its stop channel is closed. Stop closes it and waits for the
+// routine to return, after which Start starts the server again with a fresh restart history.
 type SimpleServer struct {
-	ComponentName string
-	Interval      time.Duration
+	ComponentName      string
+	Interval           time.Duration
+	MaxInterval        time.Duration
+	CrashLoopThreshold int
+	Deps               []string
 
-	serverRoutine func() error
-	stopChannel   chan struct{}
+	serverRoutine func(stop <-chan struct{}) error
 	readyFunc     func() bool
+
+	// runLock guards stopChannel and done, which are set while the server is started.
+	runLock     sync.Mutex
+	stopChannel chan struct{}
+	done        chan struct{}
+
+	statusLock sync.Mutex
+	status     ServerStatus
 }
 
-func NewSimpleServer(componentName string, msInterval int32, serverRoutine func() error, ready HealthCheck) *SimpleServer {
+func NewSimpleServer(componentName string, msInterval int32, serverRoutine func(stop <-chan struct{}) error, ready HealthCheck, deps ...string) *SimpleServer {
 	return &SimpleServer{
-		ComponentName: componentName,
-		Interval:      time.Duration(msInterval) * time.Millisecond,
+		ComponentName:      componentName,
+		Interval:           time.Duration(msInterval) * time.Millisecond,
+		MaxInterval:        maxServerInterval,
+		CrashLoopThreshold: crashLoopThreshold,
+		Deps:               deps,
 
 		serverRoutine: serverRoutine,
-		stopChannel:   make(chan struct{}),
 		readyFunc:     ready,
 	}
 }
 
-// Start calls startup function.
+// Start starts supervising the server routine. Starting a started server is a no-op.
 func (s *SimpleServer) Start() {
-	go util.Until(s.serverRoutine, os.Stdout, s.ComponentName, s.Interval, s.stopChannel)
+	s.runLock.Lock()
+	defer s.runLock.Unlock()
+	if s.stopChannel != nil {
+		return
+	}
+	s.stopChannel = make(chan struct{})
+	s.done = make(chan struct{})
+	s.updateStatus(func(status *ServerStatus) {
+		*status = ServerStatus{}
+	})
+	go s.supervise(s.stopChannel, s.done)
 }
 
-// Stop calls shutdown function.
+// Stop stops the server routine and waits for it to return. Stopping a stopped server is a no-op.
 func (s *SimpleServer) Stop() {
-	close(s.stopChannel)
+	s.runLock.Lock()
+	stop, done := s.stopChannel, s.done
+	s.stopChannel, s.done = nil, nil
+	s.runLock.Unlock()
+	if stop == nil {
+		return
+	}
+	close(stop)
+	<-done
 }
 
 // Name returns the name of the service.
-func (s SimpleServer) Name() string {
+func (s *SimpleServer) Name() string {
 	return s.ComponentName
 }
 
-func (s SimpleServer) Ready() (bool, error) {
+// Ready returns false once the component has been marked failed, otherwise the result of its health check.
+func (s *SimpleServer) Ready() (bool, error) {
+	if s.Status().Failed {
+		return false, nil
+	}
 	return s.readyFunc(), nil
 }
+
+// Dependencies returns the names of the servers this one depends on.
+func (s *SimpleServer) Dependencies() []string {
+	return s.Deps
+}
+
+// Status returns a snapshot of the supervisor state of the service.
+func (s *SimpleServer) Status() ServerStatus {
+	s.statusLock.Lock()
+	defer s.statusLock.Unlock()
+	return s.status
+}
@@ -21,6 +21,8 @@ import (
 	"time"
 
 	"k8s.io/apimachinery/pkg/util/wait"
+
+	"k8s.io/minikube/pkg/util"
 )
 
 // Servers allows operations to be performed on many servers at once.
@@ -37,16 +39,107 @@ func (servers Servers) Get(name string) (Server, error) {
 	return nil, fmt.Errorf("server '%s' does not exist", name)
 }
 
-// StartAll starts all services, starting from 0th item and ascending.
-func (servers Servers) StartAll() {
+// startResult records the outcome of starting a single server. err must only be read once done is closed.
+type startResult struct {
+	done chan struct{}
+	err  error
+}
+
+// StartAll starts all services, launching each one as soon as the servers it depends on are ready.
+// Servers without a dependency between them are started concurrently. readyTimeout returns how long
+// the named server is given to become ready. Returns an aggregated error naming every server that
+// did not become ready.
+func (servers Servers) StartAll(readyTimeout func(name string) time.Duration) error {
+	if err := servers.validateDependencies(); err != nil {
+		return err
+	}
+
+	results := map[string]*startResult{}
+	for _, server := range servers {
+		results[server.Name()] = &startResult{done: make(chan struct{})}
+	}
+
+	for _, server := range servers {
+		go func(server Server) {
+			r := results[server.Name()]
+			r.err = startWhenReady(server, results, readyTimeout(server.Name()))
+			close(r.done)
+		}(server)
+	}
+
+	m := util.MultiError{}
+	for _, server := range servers {
+		r := results[server.Name()]
+		<-r.done
+		m.Collect(r.err)
+	}
+	return m.ToError()
+}
+
+// startWhenReady waits for the dependencies of server to be ready, starts it, then waits up to timeout for it to be ready.
+func startWhenReady(server Server, results map[string]*startResult, timeout time.Duration) error {
+	for _, dep := range server.Dependencies() {
+		r := results[dep]
+		<-r.done
+		if r.err != nil {
+			return fmt.Errorf("%s was not started because %s is not ready", server.Name(), dep)
+		}
+	}
+
+	return startAndWait(server, timeout)
+}
+
+// startAndWait starts server, then waits up to timeout for it to be ready.
+func startAndWait(server Server, timeout time.Duration) error {
+	fmt.Printf("Starting %s...\n", server.Name())
+	server.Start()
+	fmt.Printf("Waiting for %s to be healthy...\n", server.Name())
+	if err := wait.PollImmediate(time.Second, timeout, server.Ready); err != nil {
+		if err == wait.ErrWaitTimeout {
+			return fmt.Errorf("%s did not become ready within %s", server.Name(), timeout)
+		}
+		return fmt.Errorf("%s failed its readiness check: %s", server.Name(), err)
+	}
+	fmt.Printf("%s is ready!\n", server.Name())
+	return nil
+}
+
+// validateDependencies checks that every dependency refers to a known server and that there are no cycles.
+func (servers Servers) validateDependencies() error {
+	for _, server := range servers {
+		for _, dep := range server.Dependencies() {
+			if _, err := servers.Get(dep); err != nil {
+				return fmt.Errorf("%s depends on unknown server %s", server.Name(), dep)
+			}
+		}
+	}
 
+	visiting := map[string]bool{}
+	visited := map[string]bool{}
+	var visit func(server Server) error
+	visit = func(server Server) error {
+		if visited[server.Name()] {
+			return nil
+		}
+		if visiting[server.Name()] {
+			return fmt.Errorf("dependency cycle detected at %s", server.Name())
+		}
+		visiting[server.Name()] = true
+		for _, dep := range server.Dependencies() {
+			depServer, _ := servers.Get(dep)
+			if err := visit(depServer); err != nil {
+				return err
+			}
+		}
+		visited[server.Name()] = true
+		return nil
+	}
 	for _, server := range servers {
-		fmt.Printf("Starting %s...\n", server.Name())
-		server.Start()
-		fmt.Printf("Waiting for %s to be healthy...\n", server.Name())
-		wait.PollInfinite(time.Second, server.Ready)
-		fmt.Printf("%s is ready!\n", server.Name())
+		if err := visit(server); err != nil {
+			return err
+		}
 	}
+	return nil
 }
 
 // StopAll stops all services, starting with the last item.
@@ -58,6 +151,22 @@ func (servers Servers) StopAll() {
 	}
 }
 
+// Replace stops the server with the same name as server and starts server in its place, then waits
+// up to timeout for it to be ready. The servers depending on it keep running. Returns an error if
+// there is no such server or server doesn't become ready.
+func (servers Servers) Replace(server Server, timeout time.Duration) error {
+	for i, old := range servers {
+		if old.Name() != server.Name() {
+			continue
+		}
+		fmt.Printf("Stopping %s...\n", old.Name())
+		old.Stop()
+		servers[i] = server
+		return startAndWait(server, timeout)
+	}
+	return fmt.Errorf("server '%s' does not exist", server.Name())
+}
+
 // Start is a helper method to start the Server specified, returns error if server doesn't exist.
 func (servers Servers) Start(serverName string) error {
 	server, err := servers.Get(serverName)
@@ -0,0 +1,194 @@
+/*
+Copyright 2016 The Kubernetes Authors All rights reserved.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package localkube
+
+import (
+	"strings"
+	"sync"
+	"testing"
+	"time"
+)
+
+type fakeServer struct {
+	name  string
+	deps  []string
+	ready bool
+
+	mu      sync.Mutex
+	started bool
+	stopped bool
+}
+
+func (f *fakeServer) Start() {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+	f.started = true
+}
+
+func (f *fakeServer) Stop() {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+	f.stopped = true
+}
+
+func (f *fakeServer) Name() string { return f.name }
+
+func (f *fakeServer) Ready() (bool, error) {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+	return f.started && f.ready, nil
+}
+
+func (f *fakeServer) Dependencies() []string { return f.deps }
+
+func (f *fakeServer) Status() ServerStatus { return ServerStatus{} }
+
+func (f *fakeServer) wasStarted() bool {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+	return f.started
+}
+
+func (f *fakeServer) wasStopped() bool {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+	return f.stopped
+}
+
+func shortTimeout(string) time.Duration { return 10 * time.Millisecond }
+
+func TestStartAll(t *testing.T) {
+	apiserver := &fakeServer{name: "apiserver", ready: true}
+	kubelet := &fakeServer{name: "kubelet", deps: []string{"apiserver"}, ready: true}
+	proxy := &fakeServer{name: "proxy", deps: []string{"apiserver"}, ready: true}
+	servers := Servers{apiserver, kubelet, proxy}
+
+	if err := servers.StartAll(shortTimeout); err != nil {
+		t.Fatalf("Unexpected error starting servers: %s", err)
+	}
+	for _, s := range []*fakeServer{apiserver, kubelet, proxy} {
+		if !s.wasStarted() {
+			t.Fatalf("Expected %s to be started", s.name)
+		}
+	}
+}
+
+func TestStartAllReadyTimeout(t *testing.T) {
+	apiserver := &fakeServer{name: "apiserver", ready: false}
+	kubelet := &fakeServer{name: "kubelet", deps: []string{"apiserver"}, ready: true}
+	standalone := &fakeServer{name: "standalone", ready: true}
+	servers := Servers{apiserver, kubelet, standalone}
+
+	err := servers.StartAll(shortTimeout)
+	if err == nil {
+		t.Fatal("Expected an error when a server never becomes ready")
+	}
+	for _, name := range []string{"apiserver", "kubelet"} {
+		if !strings.Contains(err.Error(), name) {
+			t.Fatalf("Expected error to name %s, got: %s", name, err)
+		}
+	}
+	if kubelet.wasStarted() {
+		t.Fatal("kubelet should not be started when apiserver is not ready")
+	}
+	if !standalone.wasStarted() {
+		t.Fatal("Servers without dependencies should still be started")
+	}
+}
+
+func TestStartAllInvalidDependencies(t *testing.T) {
+	tests := []struct {
+		description string
+		servers     Servers
+	}{
+		{
+			description: "unknown dependency",
+			servers: Servers{
+				&fakeServer{name: "kubelet", deps: []string{"apiserver"}},
+			},
+		},
+		{
+			description: "dependency cycle",
+			servers: Servers{
+				&fakeServer{name: "a", deps: []string{"b"}},
+				&fakeServer{name: "b", deps: []string{"a"}},
+			},
+		},
+	}
+
+	for _, test := range tests {
+		if err := test.servers.StartAll(shortTimeout); err == nil {
+			t.Errorf("Expected an error for %s", test.description)
+		}
+		for _, s := range test.servers {
+			if s.(*fakeServer).wasStarted() {
+				t.Errorf("No server should be started for %s", test.description)
+			}
+		}
+	}
+}
+
+func TestGetReadyTimeout(t *testing.T) {
+	lk := LocalkubeServer{
+		ReadyTimeout: time.Minute,
+		ComponentReadyTimeouts: map[string]string{
+			"apiserver": "10m",
+			"kubelet":   "notaduration",
+		},
+	}
+
+	tests := map[string]time.Duration{
+		"apiserver": 10 * time.Minute,
+		"kubelet":   time.Minute,
+		"proxy":     time.Minute,
+	}
+	for name, expected := range tests {
+		if actual := lk.GetReadyTimeout(name); actual != expected {
+			t.Errorf("Expected ready timeout for %s to be %s, got %s", name, expected, actual)
+		}
+	}
+}
+
+func TestReplace(t *testing.T) {
+	apiserver := &fakeServer{name: "apiserver", ready: true}
+	kubelet := &fakeServer{name: "kubelet", deps: []string{"apiserver"}, ready: true}
+	servers := Servers{apiserver, kubelet}
+	if err := servers.StartAll(shortTimeout); err != nil {
+		t.Fatalf("Unexpected error starting servers: %s", err)
+	}
+
+	restarted := &fakeServer{name: "kubelet", deps: []string{"apiserver"}, ready: true}
+	if err := servers.Replace(restarted, shortTimeout("kubelet")); err != nil {
+		t.Fatalf("Unexpected error replacing the kubelet: %s", err)
+	}
+	if !kubelet.wasStopped() {
+		t.Error("Expected the old kubelet to be stopped")
+	}
+	if !restarted.wasStarted() {
+		t.Error("Expected the new kubelet to be started")
+	}
+	if apiserver.wasStopped() {
+		t.Error("Expected the apiserver to keep running")
+	}
+	if s, _ := servers.Get("kubelet"); s != restarted {
+		t.Errorf("Expected the new kubelet to replace the old one, got %v", s)
+	}
+
+	if err := servers.Replace(&fakeServer{name: "scheduler"}, shortTimeout("scheduler")); err == nil {
+		t.Error("Expected an error replacing a server that doesn't exist")
+	}
+}
@@ -0,0 +1,128 @@
+/*
+Copyright 2016 The Kubernetes Authors All rights reserved.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package localkube
+
+import (
+	"encoding/json"
+	"fmt"
+	"io/ioutil"
+	"net"
+	"net/http"
+	"os"
+	"path"
+	"strings"
+	"time"
+
+	"github.com/golang/glog"
+
+	"k8s.io/minikube/pkg/util"
+)
+
+const unixSocketPrefix = "unix://"
+
+// ComponentStatuses returns the health of every server.
+func (servers Servers) ComponentStatuses() []util.ComponentStatus {
+	statuses := []util.ComponentStatus{}
+	for _, server := range servers {
+		ready, err := server.Ready()
+		if err != nil {
+			glog.Errorf("Error checking if %s is ready: %s", server.Name(), err)
+		}
+		s := server.Status()
+		c := util.ComponentStatus{
+			Name:     server.Name(),
+			Ready:    ready,
+			Failed:   s.Failed,
+			Restarts: s.Restarts,
+		}
+		if !s.StartTime.IsZero() {
+			c.Uptime = (time.Since(s.StartTime) / time.Second * time.Second).String()
+		}
+		if s.LastError != nil {
+			c.LastError = s.LastError.Error()
+		}
+		statuses = append(statuses, c)
+	}
+	return statuses
+}
+
+// StatusHandler serves the health of every server as JSON on LocalkubeStatusPath, and an
+// aggregate health check on LocalkubeHealthzPath which responds "ok" only when all servers are ready.
+func (servers Servers) StatusHandler() http.Handler {
+	mux := http.NewServeMux()
+	mux.HandleFunc(util.LocalkubeStatusPath, func(w http.ResponseWriter, r *http.Request) {
+		w.Header().Set("Content-Type", "application/json")
+		if err := json.NewEncoder(w).Encode(servers.ComponentStatuses()); err != nil {
+			glog.Errorf("Error encoding localkube status: %s", err)
+		}
+	})
+	mux.HandleFunc(util.LocalkubeHealthzPath, func(w http.ResponseWriter, r *http.Request) {
+		unhealthy := []string{}
+		for _, c := range servers.ComponentStatuses() {
+			if !c.Ready {
+				unhealthy = append(unhealthy, c.Name)
+			}
+		}
+		if len(unhealthy) > 0 {
+			w.WriteHeader(http.StatusInternalServerError)
+			fmt.Fprintf(w, "not ready: %s", strings.Join(unhealthy, ", "))
+			return
+		}
+		fmt.Fprint(w, "ok")
+	})
+	return mux
+}
+
+// writeStatusAddress records StatusAddress in the localkube directory.
+func (lk LocalkubeServer) writeStatusAddress() error {
+	return ioutil.WriteFile(path.Join(lk.LocalkubeDirectory, util.LocalkubeStatusAddressFile), []byte(lk.StatusAddress), 0644)
+}
+
+// ServeStatus serves the status and metrics of the localkube servers, the audit log and snapshots of etcd on StatusAddress,
+// which is either a host:port pair or a unix socket path prefixed with unix://. etcd is nil when
+// an external etcd is used, which can't be snapshotted. The address is recorded in the localkube
+// directory for minikube to find. All servers must have been added before it is called. It blocks
+// until the listener fails.
+func (lk LocalkubeServer) ServeStatus(etcd *EtcdServer) error {
+	network, address := "tcp", lk.StatusAddress
+	if strings.HasPrefix(address, unixSocketPrefix) {
+		network, address = "unix", strings.TrimPrefix(address, unixSocketPrefix)
+		if err := os.Remove(address); err != nil && !os.IsNotExist(err) {
+			return err
+		}
+	}
+
+	l, err := net.Listen(network, address)
+	if err != nil {
+		return err
+	}
+	if err := lk.writeStatusAddress(); err != nil {
+		glog.Errorf("Error recording the status address, it can only be found at the default address: %s", err)
+	}
+	mux := http.NewServeMux()
+	mux.Handle("/", lk.Servers.StatusHandler())
+	mux.Handle(util.LocalkubeMetricsPath, lk.Servers.MetricsHandler())
+	mux.Handle(util.LocalkubeAuditPath, lk.AuditLogHandler())
+	if etcd != nil {
+		mux.Handle(util.LocalkubeEtcdSnapshotPath, etcd.SnapshotHandler())
+	} else {
+		mux.HandleFunc(util.LocalkubeEtcdSnapshotPath, func(w http.ResponseWriter, r *http.Request) {
+			http.Error(w, "etcd snapshots are not supported with an external etcd", http.StatusNotImplemented)
+		})
+	}
+	return http.Serve(l, mux)
+}
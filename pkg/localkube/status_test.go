@@ -0,0 +1,76 @@
+/*
+Copyright 2016 The Kubernetes Authors All rights reserved.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package localkube
+
+import (
+	"encoding/json"
+	"io/ioutil"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"k8s.io/minikube/pkg/util"
+)
+
+func TestStatusHandler(t *testing.T) {
+	apiserver := &fakeServer{name: "apiserver", ready: true}
+	kubelet := &fakeServer{name: "kubelet", ready: true}
+	apiserver.Start()
+	servers := Servers{apiserver, kubelet}
+
+	server := httptest.NewServer(servers.StatusHandler())
+	defer server.Close()
+
+	resp, err := http.Get(server.URL + util.LocalkubeStatusPath)
+	if err != nil {
+		t.Fatalf("Error getting status: %s", err)
+	}
+	defer resp.Body.Close()
+	components := []util.ComponentStatus{}
+	if err := json.NewDecoder(resp.Body).Decode(&components); err != nil {
+		t.Fatalf("Error decoding status: %s", err)
+	}
+	if len(components) != 2 {
+		t.Fatalf("Expected 2 components, got: %+v", components)
+	}
+	if !components[0].Ready || components[1].Ready {
+		t.Fatalf("Expected only apiserver to be ready, got: %+v", components)
+	}
+
+	tests := []struct {
+		description  string
+		startKubelet bool
+		statusCode   int
+	}{
+		{"kubelet not ready", false, http.StatusInternalServerError},
+		{"all ready", true, http.StatusOK},
+	}
+	for _, test := range tests {
+		if test.startKubelet {
+			kubelet.Start()
+		}
+		resp, err := http.Get(server.URL + util.LocalkubeHealthzPath)
+		if err != nil {
+			t.Fatalf("Error getting healthz: %s", err)
+		}
+		body, _ := ioutil.ReadAll(resp.Body)
+		resp.Body.Close()
+		if resp.StatusCode != test.statusCode {
+			t.Errorf("Expected status code %d when %s, got %d: %s", test.statusCode, test.description, resp.StatusCode, body)
+		}
+	}
+}
@@ -17,10 +17,13 @@ limitations under the License.
 package localkube
 
 import (
+	"encoding/json"
 	"errors"
 	"fmt"
+	"io/ioutil"
 	"os"
 	"path"
+	"strings"
 	"time"
 
 	"github.com/golang/glog"
@@ -29,10 +32,14 @@ import (
 	meta_v1 "k8s.io/apimachinery/pkg/apis/meta/v1"
 	"k8s.io/apimachinery/pkg/types"
 	"k8s.io/apimachinery/pkg/util/uuid"
-	"k8s.io/apimachinery/pkg/util/wait"
 	"k8s.io/client-go/kubernetes"
+	corev1 "k8s.io/client-go/kubernetes/typed/core/v1"
 	"k8s.io/client-go/pkg/api/v1"
 	"k8s.io/client-go/rest"
+	kubeletapis "k8s.io/kubernetes/pkg/kubelet/apis"
+	"k8s.io/kubernetes/pkg/util/exec"
+	"k8s.io/kubernetes/pkg/util/mount"
+	nodeutil "k8s.io/kubernetes/pkg/util/node"
 )
 
 const (
@@ -44,46 +51,235 @@ const (
 	retryPeriod               = leaderelection.DefaultRetryPeriod
 	renewDeadline             = leaderelection.DefaultRenewDeadline
 	termLimit                 = leaderelection.DefaultTermLimit
+
+	provisionerIdentityAnnotation = "hostPathProvisionerIdentity"
+	// provisionerIdentityFile is the file in the localkube directory holding the provisioner identity.
+	provisionerIdentityFile = "storage-provisioner-identity"
+	// reclaimPolicyParameter is the storage class parameter that sets the reclaim policy of
+	// the provisioned volumes. Storage classes have no reclaim policy field before 1.8.
+	reclaimPolicyParameter = "reclaimPolicy"
+	// volumeImagesDir is the directory in the provisioner directory holding the images of the
+	// volumes when their size is enforced.
+	volumeImagesDir = ".images"
+	// retainedVolumesDir is the directory in the provisioner directory holding a marker for each
+	// volume with the Retain policy, so it is kept after its PV is deleted.
+	retainedVolumesDir = ".retained"
+	// provisionedVolumePrefix starts the names the provision controller gives PVs, which Provision
+	// names the directories of the volumes after. Reconcile only removes the directories named so,
+	// as the directory of a storage class can be an existing directory holding other files.
+	provisionedVolumePrefix = "pvc-"
 )
 
 type hostPathProvisioner struct {
+	// The client used to find the volumes to snapshot and clone, and to record snapshots
+	client kubernetes.Interface
+
 	// The directory to create PV-backing directories in
 	pvDir string
 
-	// Identity of this hostPathProvisioner, generated. Used to identify "this"
-	// provisioner's PVs.
+	// The directories to create the PV-backing directories of specific storage classes in
+	classDirs map[string]string
+
+	// Identity of this hostPathProvisioner, persisted across localkube restarts.
+	// Used to identify "this" provisioner's PVs.
 	identity types.UID
+
+	// The name of the node the volumes are on
+	nodeName string
+
+	// If set, each volume is a loop mounted ext4 image of the requested size
+	mounter *mount.SafeFormatAndMount
+}
+
+// NewHostPathProvisioner creates a provisioner of volumes in pvDir on the node nodeName. The
+// volumes of the storage classes in classDirs are created in the class' directory instead. If
+// enforceSize is set, each volume is backed by an image of the requested size.
+func NewHostPathProvisioner(client kubernetes.Interface, pvDir string, classDirs map[string]string, identity types.UID, nodeName string, enforceSize bool) *hostPathProvisioner {
+	p := &hostPathProvisioner{
+		client:    client,
+		pvDir:     pvDir,
+		classDirs: classDirs,
+		identity:  identity,
+		nodeName:  nodeName,
+	}
+	if enforceSize {
+		p.mounter = &mount.SafeFormatAndMount{Interface: mount.New(""), Runner: exec.New()}
+	}
+	return p
+}
+
+// loadProvisionerIdentity returns the identity stored in path, generating and storing a new
+// identity if there is none yet.
+func loadProvisionerIdentity(path string) (types.UID, error) {
+	b, err := ioutil.ReadFile(path)
+	if err == nil && len(strings.TrimSpace(string(b))) > 0 {
+		return types.UID(strings.TrimSpace(string(b))), nil
+	}
+	if err != nil && !os.IsNotExist(err) {
+		return "", err
+	}
+	identity := uuid.NewUUID()
+	if err := ioutil.WriteFile(path, []byte(identity), 0644); err != nil {
+		return "", err
+	}
+	return identity, nil
+}
+
+// Reconcile adopts the PVs in the provisioner directories that were provisioned with an earlier
+// identity, so they can be deleted, mounts the images of the PVs that are not mounted after a
+// reboot, and removes the volume directories and images that no PV refers to anymore, unless they
+// are retained.
+func (p *hostPathProvisioner) Reconcile(client corev1.PersistentVolumeInterface) error {
+	pvs, err := client.List(meta_v1.ListOptions{})
+	if err != nil {
+		return fmt.Errorf("Error listing persistent volumes: %v", err)
+	}
+
+	inUse := map[string]bool{}
+	for i := range pvs.Items {
+		pv := &pvs.Items[i]
+		if pv.Spec.HostPath == nil || !p.isVolumeDir(path.Dir(pv.Spec.HostPath.Path)) {
+			continue
+		}
+		inUse[pv.Spec.HostPath.Path] = true
+		if err := p.mountImage(path.Dir(pv.Spec.HostPath.Path), path.Base(pv.Spec.HostPath.Path)); err != nil {
+			return err
+		}
+		ann, ok := pv.Annotations[provisionerIdentityAnnotation]
+		if !ok || ann == string(p.identity) {
+			continue
+		}
+		pv.Annotations[provisionerIdentityAnnotation] = string(p.identity)
+		if _, err := client.Update(pv); err != nil {
+			return fmt.Errorf("Error adopting persistent volume %s: %v", pv.Name, err)
+		}
+		glog.Infof("Adopted persistent volume %s", pv.Name)
+	}
+
+	for _, dir := range p.volumeDirs() {
+		if err := p.removeOrphanedVolumes(dir, inUse); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// removeOrphanedVolumes removes the volume directories and images in dir whose path is not in inUse.
+// Other files and directories in dir are left alone.
+func (p *hostPathProvisioner) removeOrphanedVolumes(dir string, inUse map[string]bool) error {
+	volumes, err := ioutil.ReadDir(dir)
+	if os.IsNotExist(err) {
+		return nil
+	}
+	if err != nil {
+		return err
+	}
+	for _, v := range volumes {
+		if !v.IsDir() || !strings.HasPrefix(v.Name(), provisionedVolumePrefix) || inUse[path.Join(dir, v.Name())] || p.isRetained(dir, v.Name()) {
+			continue
+		}
+		if err := p.removeVolume(dir, v.Name()); err != nil {
+			return fmt.Errorf("Error removing orphaned volume %s: %v", v.Name(), err)
+		}
+		glog.Infof("Removed orphaned volume directory %s", path.Join(dir, v.Name()))
+	}
+
+	images, err := ioutil.ReadDir(path.Join(dir, volumeImagesDir))
+	if os.IsNotExist(err) {
+		return nil
+	}
+	if err != nil {
+		return err
+	}
+	for _, i := range images {
+		name := strings.TrimSuffix(i.Name(), ".img")
+		if !strings.HasPrefix(name, provisionedVolumePrefix) || inUse[path.Join(dir, name)] || p.isRetained(dir, name) {
+			continue
+		}
+		if err := os.Remove(imagePath(dir, name)); err != nil {
+			return fmt.Errorf("Error removing orphaned volume image %s: %v", i.Name(), err)
+		}
+		glog.Infof("Removed orphaned volume image %s", imagePath(dir, name))
+	}
+	return nil
 }
 
-func NewHostPathProvisioner() controller.Provisioner {
-	return &hostPathProvisioner{
-		pvDir:    "/tmp/hostpath-provisioner",
-		identity: uuid.NewUUID(),
+// volumeDirs returns the directories volumes are created in, without duplicates.
+func (p *hostPathProvisioner) volumeDirs() []string {
+	seen := map[string]bool{p.pvDir: true}
+	dirs := []string{p.pvDir}
+	for _, dir := range p.classDirs {
+		if dir != "" && !seen[dir] {
+			seen[dir] = true
+			dirs = append(dirs, dir)
+		}
+	}
+	return dirs
+}
+
+// isVolumeDir returns whether volumes are created in dir.
+func (p *hostPathProvisioner) isVolumeDir(dir string) bool {
+	if dir == p.pvDir {
+		return true
 	}
+	for _, d := range p.classDirs {
+		if d != "" && dir == d {
+			return true
+		}
+	}
+	return false
+}
+
+// getVolumeDir returns the directory the volumes of the storage class are created in.
+func (p *hostPathProvisioner) getVolumeDir(class string) string {
+	if dir, ok := p.classDirs[class]; ok && dir != "" {
+		return dir
+	}
+	return p.pvDir
 }
 
 var _ controller.Provisioner = &hostPathProvisioner{}
 
 // Provision creates a storage asset and returns a PV object representing it.
 func (p *hostPathProvisioner) Provision(options controller.VolumeOptions) (*v1.PersistentVolume, error) {
-	path := path.Join(p.pvDir, options.PVName)
+	reclaimPolicy, err := getReclaimPolicy(options)
+	if err != nil {
+		return nil, err
+	}
 
+	dir := p.getVolumeDir(getClaimClass(options.PVC))
+	path := path.Join(dir, options.PVName)
 	if err := os.MkdirAll(path, 0777); err != nil {
 		return nil, err
 	}
 
+	capacity := options.PVC.Spec.Resources.Requests[v1.ResourceName(v1.ResourceStorage)]
+	if p.mounter != nil {
+		if err := p.createImage(dir, options.PVName, capacity.Value()); err != nil {
+			p.removeVolume(dir, options.PVName)
+			return nil, err
+		}
+	}
+	if err := p.populateVolume(options.PVC, path); err != nil {
+		p.removeVolume(dir, options.PVName)
+		return nil, err
+	}
+
 	pv := &v1.PersistentVolume{
 		ObjectMeta: meta_v1.ObjectMeta{
 			Name: options.PVName,
+			Labels: map[string]string{
+				kubeletapis.LabelHostname: p.nodeName,
+			},
 			Annotations: map[string]string{
-				"hostPathProvisionerIdentity": string(p.identity),
+				provisionerIdentityAnnotation: string(p.identity),
 			},
 		},
 		Spec: v1.PersistentVolumeSpec{
-			PersistentVolumeReclaimPolicy: options.PersistentVolumeReclaimPolicy,
+			PersistentVolumeReclaimPolicy: reclaimPolicy,
 			AccessModes:                   options.PVC.Spec.AccessModes,
 			Capacity: v1.ResourceList{
-				v1.ResourceName(v1.ResourceStorage): options.PVC.Spec.Resources.Requests[v1.ResourceName(v1.ResourceStorage)],
+				v1.ResourceName(v1.ResourceStorage): capacity,
 			},
 			PersistentVolumeSource: v1.PersistentVolumeSource{
 				HostPath: &v1.HostPathVolumeSource{
@@ -92,6 +288,16 @@ func (p *hostPathProvisioner) Provision(options controller.VolumeOptions) (*v1.P
 			},
 		},
 	}
+	if err := p.setNodeAffinity(pv); err != nil {
+		p.removeVolume(dir, options.PVName)
+		return nil, err
+	}
+	if reclaimPolicy == v1.PersistentVolumeReclaimRetain {
+		if err := p.retain(dir, options.PVName); err != nil {
+			p.removeVolume(dir, options.PVName)
+			return nil, err
+		}
+	}
 
 	return pv, nil
 }
@@ -99,32 +305,156 @@ func (p *hostPathProvisioner) Provision(options controller.VolumeOptions) (*v1.P
 // Delete removes the storage asset that was created by Provision represented
 // by the given PV.
 func (p *hostPathProvisioner) Delete(volume *v1.PersistentVolume) error {
-	ann, ok := volume.Annotations["hostPathProvisionerIdentity"]
+	ann, ok := volume.Annotations[provisionerIdentityAnnotation]
 	if !ok {
 		return errors.New("identity annotation not found on PV")
 	}
 	if ann != string(p.identity) {
 		return &controller.IgnoredError{"identity annotation on PV does not match ours"}
 	}
+	if volume.Spec.HostPath == nil || !p.isVolumeDir(path.Dir(volume.Spec.HostPath.Path)) {
+		return fmt.Errorf("PV %s is not in a provisioner directory", volume.Name)
+	}
 
-	path := path.Join(p.pvDir, volume.Name)
-	if err := os.RemoveAll(path); err != nil {
-		return err
+	return p.removeVolume(path.Dir(volume.Spec.HostPath.Path), path.Base(volume.Spec.HostPath.Path))
+}
+
+// getClaimClass returns the storage class the claim requests.
+func getClaimClass(claim *v1.PersistentVolumeClaim) string {
+	if class, ok := claim.Annotations[v1.BetaStorageClassAnnotation]; ok {
+		return class
+	}
+	if claim.Spec.StorageClassName != nil {
+		return *claim.Spec.StorageClassName
+	}
+	return ""
+}
+
+// getReclaimPolicy returns the reclaim policy set in the storage class parameters, or the policy
+// of the controller if there is none.
+func getReclaimPolicy(options controller.VolumeOptions) (v1.PersistentVolumeReclaimPolicy, error) {
+	policy, ok := options.Parameters[reclaimPolicyParameter]
+	if !ok {
+		return options.PersistentVolumeReclaimPolicy, nil
 	}
+	switch p := v1.PersistentVolumeReclaimPolicy(policy); p {
+	case v1.PersistentVolumeReclaimDelete, v1.PersistentVolumeReclaimRetain, v1.PersistentVolumeReclaimRecycle:
+		return p, nil
+	}
+	return "", fmt.Errorf("Invalid %s %q, must be one of Delete, Retain, Recycle", reclaimPolicyParameter, policy)
+}
 
+// setNodeAffinity restricts the pods using pv to the node the volume is on.
+func (p *hostPathProvisioner) setNodeAffinity(pv *v1.PersistentVolume) error {
+	affinity := v1.NodeAffinity{
+		RequiredDuringSchedulingIgnoredDuringExecution: &v1.NodeSelector{
+			NodeSelectorTerms: []v1.NodeSelectorTerm{{
+				MatchExpressions: []v1.NodeSelectorRequirement{{
+					Key:      kubeletapis.LabelHostname,
+					Operator: v1.NodeSelectorOpIn,
+					Values:   []string{p.nodeName},
+				}},
+			}},
+		},
+	}
+	b, err := json.Marshal(affinity)
+	if err != nil {
+		return fmt.Errorf("Error encoding node affinity: %v", err)
+	}
+	pv.Annotations[v1.AlphaStorageNodeAffinityAnnotation] = string(b)
 	return nil
 }
 
+func (p *hostPathProvisioner) retain(dir, name string) error {
+	if err := os.MkdirAll(path.Join(dir, retainedVolumesDir), 0700); err != nil {
+		return err
+	}
+	return ioutil.WriteFile(path.Join(dir, retainedVolumesDir, name), []byte{}, 0600)
+}
+
+func (p *hostPathProvisioner) isRetained(dir, name string) bool {
+	_, err := os.Stat(path.Join(dir, retainedVolumesDir, name))
+	return err == nil
+}
+
+func imagePath(dir, name string) string {
+	return path.Join(dir, volumeImagesDir, name+".img")
+}
+
+// createImage creates a sparse image of size bytes for the volume name in dir, and mounts it on
+// the volume directory.
+func (p *hostPathProvisioner) createImage(dir, name string, size int64) error {
+	if size <= 0 {
+		return fmt.Errorf("Volume %s has no storage request, its size cannot be enforced", name)
+	}
+	if err := os.MkdirAll(path.Join(dir, volumeImagesDir), 0700); err != nil {
+		return err
+	}
+	f, err := os.OpenFile(imagePath(dir, name), os.O_CREATE|os.O_EXCL|os.O_WRONLY, 0600)
+	if err != nil {
+		return err
+	}
+	defer f.Close()
+	if err := f.Truncate(size); err != nil {
+		return err
+	}
+	return p.mountImage(dir, name)
+}
+
+// mountImage mounts the image of the volume name in dir on the volume directory, formatting it
+// first if needed. Volumes without an image are left as they are.
+func (p *hostPathProvisioner) mountImage(dir, name string) error {
+	if p.mounter == nil {
+		return nil
+	}
+	image := imagePath(dir, name)
+	if _, err := os.Stat(image); os.IsNotExist(err) {
+		return nil
+	}
+	volume := path.Join(dir, name)
+	notMounted, err := p.mounter.IsLikelyNotMountPoint(volume)
+	if err != nil || !notMounted {
+		return err
+	}
+	if err := p.mounter.FormatAndMount(image, volume, "ext4", []string{"loop"}); err != nil {
+		return fmt.Errorf("Error mounting the image of volume %s: %v", name, err)
+	}
+	// The root of the new file system is only writable by root
+	return os.Chmod(volume, 0777)
+}
+
+// removeVolume removes the directory of the volume name in dir, its image and its retain marker.
+func (p *hostPathProvisioner) removeVolume(dir, name string) error {
+	volume := path.Join(dir, name)
+	image := imagePath(dir, name)
+	if _, err := os.Stat(image); err == nil {
+		if p.mounter != nil {
+			if notMounted, err := p.mounter.IsLikelyNotMountPoint(volume); err == nil && !notMounted {
+				if err := p.mounter.Unmount(volume); err != nil {
+					return err
+				}
+			}
+		}
+		if err := os.Remove(image); err != nil {
+			return err
+		}
+	}
+	if err := os.RemoveAll(path.Join(dir, retainedVolumesDir, name)); err != nil {
+		return err
+	}
+	return os.RemoveAll(volume)
+}
+
 func (lk LocalkubeServer) NewStorageProvisionerServer() Server {
-	return NewSimpleServer("storage-provisioner", serverInterval, StartStorageProvisioner(lk), noop)
+	return NewSimpleServer("storage-provisioner", serverInterval, StartStorageProvisioner(lk), noop, "apiserver")
 }
 
-func StartStorageProvisioner(lk LocalkubeServer) func() error {
+func StartStorageProvisioner(lk LocalkubeServer) func(stop <-chan struct{}) error {
 
 	// Create an InClusterConfig and use it to create a client for the controller
 	// to use to communicate with Kubernetes
 	config := rest.Config{Host: "http://localhost:8080"}
-	return func() error {
+	return func(stop <-chan struct{}) error {
 
 		clientset, err := kubernetes.NewForConfig(&config)
 		if err != nil {
@@ -138,15 +468,28 @@ func StartStorageProvisioner(lk LocalkubeServer) func() error {
 			return fmt.Errorf("Error getting server version: %v", err)
 		}
 
+		identity, err := loadProvisionerIdentity(path.Join(lk.LocalkubeDirectory, provisionerIdentityFile))
+		if err != nil {
+			return fmt.Errorf("Error loading provisioner identity: %v", err)
+		}
+
 		// Create the provisioner: it implements the Provisioner interface expected by
 		// the controller
-		hostPathProvisioner := NewHostPathProvisioner()
+		hostPathProvisioner := NewHostPathProvisioner(clientset, lk.HostPathProvisionerDir, lk.StorageClassDirs, identity, nodeutil.GetHostname(""), lk.HostPathProvisionerEnforceSize)
+
+		// Adopt the volumes provisioned before localkube restarted, and clean up leaked directories
+		if err := hostPathProvisioner.Reconcile(clientset.CoreV1().PersistentVolumes()); err != nil {
+			return err
+		}
 
 		// Start the provision controller which will dynamically provision hostPath
 		// PVs
 		pc := controller.NewProvisionController(clientset, resyncPeriod, provisionerName, hostPathProvisioner, serverVersion.GitVersion, exponentialBackOffOnError, failedRetryThreshold, leasePeriod, renewDeadline, retryPeriod, termLimit)
 
-		pc.Run(wait.NeverStop)
+		// Take the snapshots of volumes requested on claims
+		go hostPathProvisioner.RunSnapshotController(stop)
+
+		pc.Run(stop)
 		return nil
 	}
 }
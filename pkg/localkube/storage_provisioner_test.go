@@ -0,0 +1,281 @@
+/*
+Copyright 2016 The Kubernetes Authors All rights reserved.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package localkube
+
+import (
+	"encoding/json"
+	"io/ioutil"
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"path/filepath"
+	"strings"
+	"sync"
+	"testing"
+
+	"github.com/r2d4/external-storage/lib/controller"
+	"k8s.io/apimachinery/pkg/api/resource"
+	meta_v1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/client-go/kubernetes"
+	"k8s.io/client-go/pkg/api/v1"
+	"k8s.io/client-go/rest"
+)
+
+func TestLoadProvisionerIdentity(t *testing.T) {
+	tempDir, err := ioutil.TempDir("", "localkube")
+	if err != nil {
+		t.Fatalf("Error creating temp dir: %s", err)
+	}
+	defer os.RemoveAll(tempDir)
+	path := filepath.Join(tempDir, provisionerIdentityFile)
+
+	identity, err := loadProvisionerIdentity(path)
+	if err != nil {
+		t.Fatalf("Error loading provisioner identity: %s", err)
+	}
+	if identity == "" {
+		t.Fatalf("Expected an identity to be generated")
+	}
+	again, err := loadProvisionerIdentity(path)
+	if err != nil {
+		t.Fatalf("Error loading provisioner identity: %s", err)
+	}
+	if again != identity {
+		t.Fatalf("Expected identity %s to be kept, got %s", identity, again)
+	}
+}
+
+func hostPathPV(name, pvDir, identity string) v1.PersistentVolume {
+	pv := v1.PersistentVolume{
+		ObjectMeta: meta_v1.ObjectMeta{Name: name, Annotations: map[string]string{}},
+		Spec: v1.PersistentVolumeSpec{
+			PersistentVolumeSource: v1.PersistentVolumeSource{
+				HostPath: &v1.HostPathVolumeSource{Path: filepath.Join(pvDir, name)},
+			},
+		},
+	}
+	if identity != "" {
+		pv.Annotations[provisionerIdentityAnnotation] = identity
+	}
+	return pv
+}
+
+func TestReconcileHostPathVolumes(t *testing.T) {
+	pvDir, err := ioutil.TempDir("", "hostpath-provisioner")
+	if err != nil {
+		t.Fatalf("Error creating temp dir: %s", err)
+	}
+	defer os.RemoveAll(pvDir)
+	classDir, err := ioutil.TempDir("", "storage-class")
+	if err != nil {
+		t.Fatalf("Error creating temp dir: %s", err)
+	}
+	defer os.RemoveAll(classDir)
+	for _, d := range []string{"pvc-current", "pvc-old", "pvc-manual", "pvc-orphaned", "pvc-retained", "data"} {
+		os.Mkdir(filepath.Join(pvDir, d), 0777)
+	}
+	for _, d := range []string{"pvc-class-orphaned", "photos"} {
+		os.Mkdir(filepath.Join(classDir, d), 0777)
+	}
+	if err := ioutil.WriteFile(filepath.Join(classDir, "photos", "beach.jpg"), []byte("photo"), 0644); err != nil {
+		t.Fatalf("Error writing file: %s", err)
+	}
+	p := NewHostPathProvisioner(nil, pvDir, map[string]string{"fast": classDir}, "current", "minikube", false)
+	if err := p.retain(pvDir, "pvc-retained"); err != nil {
+		t.Fatalf("Error retaining volume: %s", err)
+	}
+
+	pvs := v1.PersistentVolumeList{Items: []v1.PersistentVolume{
+		hostPathPV("pvc-current", pvDir, "current"),
+		hostPathPV("pvc-old", pvDir, "old"),
+		hostPathPV("pvc-manual", pvDir, ""),
+	}}
+
+	var lock sync.Mutex
+	updated := []v1.PersistentVolume{}
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		lock.Lock()
+		defer lock.Unlock()
+		w.Header().Set("Content-Type", "application/json")
+		switch r.Method {
+		case "GET":
+			json.NewEncoder(w).Encode(pvs)
+		case "PUT":
+			pv := v1.PersistentVolume{}
+			if err := json.NewDecoder(r.Body).Decode(&pv); err != nil {
+				t.Errorf("Error decoding persistent volume: %s", err)
+			}
+			updated = append(updated, pv)
+			json.NewEncoder(w).Encode(pv)
+		default:
+			t.Errorf("Unexpected request %s %s", r.Method, r.URL)
+		}
+	}))
+	defer server.Close()
+
+	clientset, err := kubernetes.NewForConfig(&rest.Config{Host: server.URL})
+	if err != nil {
+		t.Fatalf("Error creating client: %s", err)
+	}
+	if err := p.Reconcile(clientset.CoreV1().PersistentVolumes()); err != nil {
+		t.Fatalf("Error reconciling volumes: %s", err)
+	}
+
+	if len(updated) != 1 || updated[0].Name != "pvc-old" || updated[0].Annotations[provisionerIdentityAnnotation] != "current" {
+		t.Fatalf("Expected only pvc-old to be adopted, got %+v", updated)
+	}
+	for _, d := range []string{"pvc-current", "pvc-old", "pvc-manual", "pvc-retained", "data"} {
+		if _, err := os.Stat(filepath.Join(pvDir, d)); err != nil {
+			t.Errorf("Expected %s to be kept: %s", d, err)
+		}
+	}
+	if _, err := os.Stat(filepath.Join(classDir, "photos", "beach.jpg")); err != nil {
+		t.Errorf("Expected the unrelated directory in the storage class directory to be kept: %s", err)
+	}
+	for _, d := range []string{filepath.Join(pvDir, "pvc-orphaned"), filepath.Join(classDir, "pvc-class-orphaned")} {
+		if _, err := os.Stat(d); !os.IsNotExist(err) {
+			t.Errorf("Expected %s to be removed", d)
+		}
+	}
+}
+
+func TestProvision(t *testing.T) {
+	pvDir, err := ioutil.TempDir("", "hostpath-provisioner")
+	if err != nil {
+		t.Fatalf("Error creating temp dir: %s", err)
+	}
+	defer os.RemoveAll(pvDir)
+	p := NewHostPathProvisioner(nil, pvDir, nil, "current", "minikube", false)
+
+	claim := &v1.PersistentVolumeClaim{
+		Spec: v1.PersistentVolumeClaimSpec{
+			AccessModes: []v1.PersistentVolumeAccessMode{v1.ReadWriteOnce},
+			Resources: v1.ResourceRequirements{
+				Requests: v1.ResourceList{v1.ResourceStorage: resource.MustParse("1Gi")},
+			},
+		},
+	}
+
+	var tests = []struct {
+		description   string
+		parameters    map[string]string
+		reclaimPolicy v1.PersistentVolumeReclaimPolicy
+		shouldErr     bool
+	}{
+		{description: "default", reclaimPolicy: v1.PersistentVolumeReclaimDelete},
+		{description: "retain", parameters: map[string]string{"reclaimPolicy": "Retain"}, reclaimPolicy: v1.PersistentVolumeReclaimRetain},
+		{description: "recycle", parameters: map[string]string{"reclaimPolicy": "Recycle"}, reclaimPolicy: v1.PersistentVolumeReclaimRecycle},
+		{description: "invalid", parameters: map[string]string{"reclaimPolicy": "Keep"}, shouldErr: true},
+	}
+
+	for _, test := range tests {
+		t.Run(test.description, func(t *testing.T) {
+			name := "pvc-" + test.description
+			pv, err := p.Provision(controller.VolumeOptions{
+				PersistentVolumeReclaimPolicy: v1.PersistentVolumeReclaimDelete,
+				PVName:                        name,
+				PVC:                           claim,
+				Parameters:                    test.parameters,
+			})
+			if err != nil {
+				if !test.shouldErr {
+					t.Fatalf("Unexpected error: %s", err)
+				}
+				return
+			}
+			if test.shouldErr {
+				t.Fatalf("Expected an error")
+			}
+
+			if pv.Spec.PersistentVolumeReclaimPolicy != test.reclaimPolicy {
+				t.Errorf("Expected reclaim policy %s, got %s", test.reclaimPolicy, pv.Spec.PersistentVolumeReclaimPolicy)
+			}
+			if pv.Labels["kubernetes.io/hostname"] != "minikube" {
+				t.Errorf("Expected the hostname label to be set, got %v", pv.Labels)
+			}
+			if !strings.Contains(pv.Annotations[v1.AlphaStorageNodeAffinityAnnotation], `"values":["minikube"]`) {
+				t.Errorf("Expected node affinity to minikube, got %v", pv.Annotations)
+			}
+			if _, err := os.Stat(filepath.Join(pvDir, name)); err != nil {
+				t.Errorf("Expected the volume directory to be created: %s", err)
+			}
+			if p.isRetained(pvDir, name) != (test.reclaimPolicy == v1.PersistentVolumeReclaimRetain) {
+				t.Errorf("Expected volume to be retained: %t", test.reclaimPolicy == v1.PersistentVolumeReclaimRetain)
+			}
+
+			if err := p.Delete(pv); err != nil {
+				t.Fatalf("Error deleting volume: %s", err)
+			}
+			if _, err := os.Stat(filepath.Join(pvDir, name)); !os.IsNotExist(err) {
+				t.Errorf("Expected the volume directory to be removed")
+			}
+			if p.isRetained(pvDir, name) {
+				t.Errorf("Expected the retain marker to be removed")
+			}
+		})
+	}
+}
+
+func TestProvisionStorageClassDir(t *testing.T) {
+	pvDir, err := ioutil.TempDir("", "hostpath-provisioner")
+	if err != nil {
+		t.Fatalf("Error creating temp dir: %s", err)
+	}
+	defer os.RemoveAll(pvDir)
+	fastDir, err := ioutil.TempDir("", "hostpath-provisioner-fast")
+	if err != nil {
+		t.Fatalf("Error creating temp dir: %s", err)
+	}
+	defer os.RemoveAll(fastDir)
+	p := NewHostPathProvisioner(nil, pvDir, map[string]string{"fast": fastDir}, "current", "minikube", false)
+
+	var tests = []struct {
+		class string
+		dir   string
+	}{
+		{"fast", fastDir},
+		{"standard", pvDir},
+		{"", pvDir},
+	}
+
+	for _, test := range tests {
+		class := test.class
+		name := "pvc-" + class
+		pv, err := p.Provision(controller.VolumeOptions{
+			PersistentVolumeReclaimPolicy: v1.PersistentVolumeReclaimDelete,
+			PVName:                        name,
+			PVC:                           &v1.PersistentVolumeClaim{Spec: v1.PersistentVolumeClaimSpec{StorageClassName: &class}},
+		})
+		if err != nil {
+			t.Fatalf("Error provisioning volume of class %q: %s", class, err)
+		}
+		if pv.Spec.HostPath.Path != filepath.Join(test.dir, name) {
+			t.Errorf("Expected volume of class %q in %s, got %s", class, test.dir, pv.Spec.HostPath.Path)
+		}
+		if err := p.Delete(pv); err != nil {
+			t.Fatalf("Error deleting volume: %s", err)
+		}
+		if _, err := os.Stat(pv.Spec.HostPath.Path); !os.IsNotExist(err) {
+			t.Errorf("Expected %s to be removed", pv.Spec.HostPath.Path)
+		}
+	}
+
+	outside := hostPathPV("pvc-outside", "/var/lib", "current")
+	if err := p.Delete(&outside); err == nil {
+		t.Errorf("Expected an error deleting a volume outside the provisioner directories")
+	}
+}
@@ -0,0 +1,120 @@
+/*
+Copyright 2016 The Kubernetes Authors All rights reserved.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package localkube
+
+import (
+	"fmt"
+	"time"
+
+	"k8s.io/minikube/pkg/util"
+)
+
+const (
+	// maxServerInterval caps the backoff between restarts of a component.
+	maxServerInterval = 30 * time.Second
+
+	// crashLoopThreshold is the number of consecutive short-lived runs after which a component is marked failed.
+	crashLoopThreshold = 10
+
+	// stableRunPeriod is how long a component must run before its backoff and crash loop count are reset.
+	stableRunPeriod = time.Minute
+)
+
+// ServerStatus describes the restart history of a supervised Server.
+type ServerStatus struct {
+	// StartTime is when the component was last started or restarted.
+	StartTime time.Time
+
+	// Restarts is the number of times the component has been restarted after exiting.
+	Restarts int
+
+	// LastError is the error the component last exited with, nil if it exited cleanly or never exited.
+	LastError error
+
+	// Failed is set once the component has crash looped and will no longer be restarted.
+	Failed bool
+}
+
+// supervise runs the server routine until stop is closed, restarting it with an exponential
+// backoff each time it exits, and closes done once the routine returned for good. After
+// CrashLoopThreshold consecutive runs shorter than stableRunPeriod the server is marked failed and
+// not restarted again.
+func (s *SimpleServer) supervise(stop <-chan struct{}, done chan<- struct{}) {
+	defer close(done)
+	backoff := s.Interval
+	failures := 0
+	for {
+		select {
+		case <-stop:
+			return
+		default:
+		}
+		s.updateStatus(func(status *ServerStatus) {
+			status.StartTime = time.Now()
+		})
+
+		err := s.serverRoutine(stop)
+		select {
+		case <-stop:
+			fmt.Printf(util.Pad("%s: Stopped."), s.ComponentName)
+			return
+		default:
+		}
+		if err == nil {
+			fmt.Printf(util.Pad("%s: Exited with no errors."), s.ComponentName)
+		} else {
+			fmt.Printf(util.Pad("%s: Exit with error: %v"), s.ComponentName, err)
+		}
+
+		if time.Since(s.Status().StartTime) >= stableRunPeriod {
+			backoff = s.Interval
+			failures = 0
+		}
+		failures++
+
+		failed := s.CrashLoopThreshold > 0 && failures >= s.CrashLoopThreshold
+		s.updateStatus(func(status *ServerStatus) {
+			status.LastError = err
+			status.Failed = failed
+		})
+		if failed {
+			fmt.Printf(util.Pad("%s: Exited %d times in a row, giving up."), s.ComponentName, failures)
+			return
+		}
+
+		fmt.Printf("%s: Restarting in %s\n", s.ComponentName, backoff)
+		select {
+		case <-stop:
+			return
+		case <-time.After(backoff):
+		}
+
+		s.updateStatus(func(status *ServerStatus) {
+			status.Restarts++
+		})
+		backoff *= 2
+		if backoff > s.MaxInterval {
+			backoff = s.MaxInterval
+		}
+	}
+}
+
+func (s *SimpleServer) updateStatus(update func(*ServerStatus)) {
+	s.statusLock.Lock()
+	defer s.statusLock.Unlock()
+	update(&s.status)
+}
@@ -0,0 +1,106 @@
+/*
+Copyright 2016 The Kubernetes Authors All rights reserved.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package localkube
+
+import (
+	"errors"
+	"testing"
+	"time"
+
+	"k8s.io/apimachinery/pkg/util/wait"
+)
+
+func TestSuperviseCrashLoop(t *testing.T) {
+	exitErr := errors.New("bad flag")
+	s := NewSimpleServer("crasher", 1, func(<-chan struct{}) error { return exitErr }, noop)
+	s.MaxInterval = 4 * time.Millisecond
+	s.CrashLoopThreshold = 3
+	s.Start()
+	defer s.Stop()
+
+	if err := wait.PollImmediate(time.Millisecond, 5*time.Second, func() (bool, error) {
+		return s.Status().Failed, nil
+	}); err != nil {
+		t.Fatalf("Expected server to be marked failed: %s", err)
+	}
+
+	status := s.Status()
+	if status.Restarts != 2 {
+		t.Errorf("Expected 2 restarts before giving up, got %d", status.Restarts)
+	}
+	if status.LastError != exitErr {
+		t.Errorf("Expected last error to be %v, got %v", exitErr, status.LastError)
+	}
+	if ready, _ := s.Ready(); ready {
+		t.Error("A failed server should not be ready")
+	}
+}
+
+func TestSuperviseStop(t *testing.T) {
+	runs := make(chan struct{}, 100)
+	s := NewSimpleServer("stopper", 1, func(<-chan struct{}) error {
+		runs <- struct{}{}
+		return nil
+	}, noop)
+	s.CrashLoopThreshold = 0
+	s.Start()
+
+	<-runs
+	s.Stop()
+	before := len(runs)
+	time.Sleep(50 * time.Millisecond)
+	if after := len(runs); after != before {
+		t.Fatalf("Server kept restarting after being stopped: %d runs became %d", before, after)
+	}
+	if s.Status().Failed {
+		t.Fatal("A stopped server should not be marked failed")
+	}
+
+	s.Stop()
+	s.Start()
+	defer s.Stop()
+	if err := wait.PollImmediate(time.Millisecond, 5*time.Second, func() (bool, error) {
+		return len(runs) > before, nil
+	}); err != nil {
+		t.Fatalf("Server did not run again after being started again: %s", err)
+	}
+}
+
+func TestSuperviseStopRoutine(t *testing.T) {
+	running := make(chan struct{})
+	s := NewSimpleServer("blocker", 1, func(stop <-chan struct{}) error {
+		close(running)
+		<-stop
+		return nil
+	}, noop)
+	s.Start()
+	<-running
+
+	stopped := make(chan struct{})
+	go func() {
+		s.Stop()
+		close(stopped)
+	}()
+	select {
+	case <-stopped:
+	case <-time.After(5 * time.Second):
+		t.Fatal("Stop did not return once the server routine was stopped")
+	}
+	if status := s.Status(); status.Failed || status.Restarts != 0 {
+		t.Errorf("Expected a stopped server not to be restarted or failed, got %+v", status)
+	}
+}
@@ -0,0 +1,338 @@
+/*
+Copyright 2016 The Kubernetes Authors All rights reserved.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package localkube
+
+import (
+	"encoding/json"
+	"fmt"
+	"os"
+	"path"
+	"strings"
+	"time"
+
+	"github.com/golang/glog"
+	apierrors "k8s.io/apimachinery/pkg/api/errors"
+	meta_v1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/runtime"
+	"k8s.io/apimachinery/pkg/util/validation"
+	"k8s.io/apimachinery/pkg/util/wait"
+	"k8s.io/apimachinery/pkg/watch"
+	"k8s.io/client-go/pkg/api/v1"
+	"k8s.io/client-go/tools/cache"
+	"k8s.io/client-go/util/workqueue"
+	"k8s.io/kubernetes/pkg/client/retry"
+	"k8s.io/kubernetes/pkg/util/exec"
+
+	"k8s.io/minikube/pkg/util"
+)
+
+// volumeSnapshotsDir is the directory in the provisioner directory holding the snapshots of volumes.
+const volumeSnapshotsDir = ".snapshots"
+
+// RunSnapshotController takes the snapshots requested on claims with the
+// VolumeSnapshotRequestAnnotation until stopCh is closed. Copying a volume can take a while, so
+// the informer only queues the claims and a worker takes the snapshots, retrying failures with
+// backoff.
+func (p *hostPathProvisioner) RunSnapshotController(stopCh <-chan struct{}) {
+	queue := workqueue.NewNamedRateLimitingQueue(workqueue.DefaultControllerRateLimiter(), "volume-snapshots")
+	defer queue.ShutDown()
+
+	claimSource := &cache.ListWatch{
+		ListFunc: func(options meta_v1.ListOptions) (runtime.Object, error) {
+			return p.client.CoreV1().PersistentVolumeClaims(v1.NamespaceAll).List(options)
+		},
+		WatchFunc: func(options meta_v1.ListOptions) (watch.Interface, error) {
+			return p.client.CoreV1().PersistentVolumeClaims(v1.NamespaceAll).Watch(options)
+		},
+	}
+	_, claimController := cache.NewInformer(
+		claimSource,
+		&v1.PersistentVolumeClaim{},
+		resyncPeriod,
+		cache.ResourceEventHandlerFuncs{
+			AddFunc: func(obj interface{}) {
+				enqueueSnapshotRequest(queue, obj)
+			},
+			UpdateFunc: func(oldObj, newObj interface{}) {
+				enqueueSnapshotRequest(queue, newObj)
+			},
+		},
+	)
+	glog.Infof("Starting volume snapshot controller")
+	go claimController.Run(stopCh)
+	go wait.Until(func() {
+		for p.processNextSnapshotRequest(queue) {
+		}
+	}, time.Second, stopCh)
+	<-stopCh
+}
+
+// enqueueSnapshotRequest queues the key of the claim if a snapshot is requested on it.
+func enqueueSnapshotRequest(queue workqueue.Interface, obj interface{}) {
+	claim, ok := obj.(*v1.PersistentVolumeClaim)
+	if !ok {
+		glog.Errorf("Expected PersistentVolumeClaim but enqueueSnapshotRequest received %+v", obj)
+		return
+	}
+	if _, ok := claim.Annotations[util.VolumeSnapshotRequestAnnotation]; !ok {
+		return
+	}
+	key, err := cache.MetaNamespaceKeyFunc(claim)
+	if err != nil {
+		glog.Errorf("Error getting key of claim %s/%s: %v", claim.Namespace, claim.Name, err)
+		return
+	}
+	queue.Add(key)
+}
+
+// processNextSnapshotRequest handles the next claim in the queue, and returns false once the
+// queue is shut down.
+func (p *hostPathProvisioner) processNextSnapshotRequest(queue workqueue.RateLimitingInterface) bool {
+	key, quit := queue.Get()
+	if quit {
+		return false
+	}
+	defer queue.Done(key)
+
+	if err := p.snapshotClaim(key.(string)); err != nil {
+		glog.Errorf("Error handling snapshot request of claim %s, retrying: %v", key, err)
+		queue.AddRateLimited(key)
+		return true
+	}
+	queue.Forget(key)
+	return true
+}
+
+// snapshotClaim takes the snapshot requested on the claim with the given namespace/name key, if
+// any, and records the outcome in the claim's annotations. It returns an error if the claim
+// couldn't be read, in which case the request should be retried. Failing to take the snapshot is
+// not retried, it is recorded on the claim instead.
+func (p *hostPathProvisioner) snapshotClaim(key string) error {
+	namespace, claimName, err := cache.SplitMetaNamespaceKey(key)
+	if err != nil {
+		return err
+	}
+	claims := p.client.CoreV1().PersistentVolumeClaims(namespace)
+	claim, err := claims.Get(claimName, meta_v1.GetOptions{})
+	if apierrors.IsNotFound(err) {
+		return nil
+	}
+	if err != nil {
+		return fmt.Errorf("Error getting claim for snapshot: %v", err)
+	}
+	name, ok := claim.Annotations[util.VolumeSnapshotRequestAnnotation]
+	if !ok {
+		return nil
+	}
+
+	snapshotErr := p.takeSnapshot(claim, name)
+	if snapshotErr != nil {
+		glog.Errorf("Error taking snapshot %s of claim %s: %v", name, key, snapshotErr)
+	} else {
+		glog.Infof("Took snapshot %s of claim %s", name, key)
+	}
+
+	// The claim may have changed while the volume was copied, so the outcome is recorded on the
+	// latest version of it
+	err = retry.RetryOnConflict(retry.DefaultRetry, func() error {
+		claim, err := claims.Get(claimName, meta_v1.GetOptions{})
+		if err != nil {
+			return err
+		}
+		if claim.Annotations == nil {
+			claim.Annotations = map[string]string{}
+		}
+		if claim.Annotations[util.VolumeSnapshotRequestAnnotation] == name {
+			delete(claim.Annotations, util.VolumeSnapshotRequestAnnotation)
+		}
+		if snapshotErr != nil {
+			claim.Annotations[util.VolumeSnapshotErrorAnnotation] = snapshotErr.Error()
+		} else {
+			claim.Annotations[util.VolumeSnapshotTakenAnnotation] = name
+			delete(claim.Annotations, util.VolumeSnapshotErrorAnnotation)
+		}
+		_, err = claims.Update(claim)
+		return err
+	})
+	if err != nil {
+		glog.Errorf("Error updating claim %s: %v", key, err)
+	}
+	return nil
+}
+
+// takeSnapshot copies the volume of the claim into the snapshot store and records the snapshot.
+func (p *hostPathProvisioner) takeSnapshot(claim *v1.PersistentVolumeClaim, name string) error {
+	if errs := validation.IsDNS1123Subdomain(name); len(errs) > 0 {
+		return fmt.Errorf("Invalid snapshot name %q: %s", name, strings.Join(errs, ", "))
+	}
+	existing, err := p.getSnapshot(name)
+	if err != nil {
+		return err
+	}
+	if existing != nil {
+		return fmt.Errorf("Snapshot %s already exists", name)
+	}
+	volume, err := p.getClaimVolume(claim)
+	if err != nil {
+		return err
+	}
+
+	dir := p.snapshotPath(name)
+	if err := os.MkdirAll(dir, 0777); err != nil {
+		return err
+	}
+	if err := copyDir(volume, dir); err != nil {
+		os.RemoveAll(dir)
+		return err
+	}
+
+	capacity := claim.Spec.Resources.Requests[v1.ResourceName(v1.ResourceStorage)]
+	snapshot := util.VolumeSnapshot{
+		Name:         name,
+		Namespace:    claim.Namespace,
+		Claim:        claim.Name,
+		StorageClass: getClaimClass(claim),
+		Capacity:     capacity.String(),
+		AccessModes:  []string{},
+		Time:         time.Now(),
+	}
+	for _, m := range claim.Spec.AccessModes {
+		snapshot.AccessModes = append(snapshot.AccessModes, string(m))
+	}
+	if err := p.recordSnapshot(snapshot); err != nil {
+		os.RemoveAll(dir)
+		return err
+	}
+	return nil
+}
+
+// populateVolume copies the snapshot or the volume of another claim that the claim asks to be
+// provisioned from into volume.
+func (p *hostPathProvisioner) populateVolume(claim *v1.PersistentVolumeClaim, volume string) error {
+	if name, ok := claim.Annotations[util.VolumeRestoreAnnotation]; ok {
+		snapshot, err := p.getSnapshot(name)
+		if err != nil {
+			return err
+		}
+		if snapshot == nil {
+			return fmt.Errorf("Snapshot %s not found", name)
+		}
+		return copyDir(p.snapshotPath(name), volume)
+	}
+	if source, ok := claim.Annotations[util.VolumeCloneAnnotation]; ok {
+		sourceClaim, err := p.client.CoreV1().PersistentVolumeClaims(claim.Namespace).Get(source, meta_v1.GetOptions{})
+		if err != nil {
+			return fmt.Errorf("Error getting claim %s to clone: %v", source, err)
+		}
+		sourceVolume, err := p.getClaimVolume(sourceClaim)
+		if err != nil {
+			return err
+		}
+		return copyDir(sourceVolume, volume)
+	}
+	return nil
+}
+
+// getClaimVolume returns the directory of the volume bound to the claim, which must have been
+// created by the provisioner.
+func (p *hostPathProvisioner) getClaimVolume(claim *v1.PersistentVolumeClaim) (string, error) {
+	if claim.Status.Phase != v1.ClaimBound || claim.Spec.VolumeName == "" {
+		return "", fmt.Errorf("Claim %s/%s is not bound to a volume", claim.Namespace, claim.Name)
+	}
+	pv, err := p.client.CoreV1().PersistentVolumes().Get(claim.Spec.VolumeName, meta_v1.GetOptions{})
+	if err != nil {
+		return "", fmt.Errorf("Error getting persistent volume %s: %v", claim.Spec.VolumeName, err)
+	}
+	if pv.Spec.HostPath == nil || !p.isVolumeDir(path.Dir(pv.Spec.HostPath.Path)) {
+		return "", fmt.Errorf("The volume of claim %s/%s was not provisioned by the storage provisioner", claim.Namespace, claim.Name)
+	}
+	return pv.Spec.HostPath.Path, nil
+}
+
+func (p *hostPathProvisioner) snapshotPath(name string) string {
+	return path.Join(p.pvDir, volumeSnapshotsDir, name)
+}
+
+// getSnapshot returns the snapshot recorded under name, or nil if there is none.
+func (p *hostPathProvisioner) getSnapshot(name string) (*util.VolumeSnapshot, error) {
+	cm, err := p.client.CoreV1().ConfigMaps(meta_v1.NamespaceSystem).Get(util.VolumeSnapshotsConfigMap, meta_v1.GetOptions{})
+	if apierrors.IsNotFound(err) {
+		return nil, nil
+	}
+	if err != nil {
+		return nil, fmt.Errorf("Error getting volume snapshots: %v", err)
+	}
+	data, ok := cm.Data[name]
+	if !ok {
+		return nil, nil
+	}
+	snapshot := &util.VolumeSnapshot{}
+	if err := json.Unmarshal([]byte(data), snapshot); err != nil {
+		return nil, fmt.Errorf("Error decoding snapshot %s: %v", name, err)
+	}
+	return snapshot, nil
+}
+
+// recordSnapshot adds the snapshot to the VolumeSnapshotsConfigMap, creating it if needed.
+func (p *hostPathProvisioner) recordSnapshot(snapshot util.VolumeSnapshot) error {
+	b, err := json.Marshal(snapshot)
+	if err != nil {
+		return fmt.Errorf("Error encoding snapshot %s: %v", snapshot.Name, err)
+	}
+	configMaps := p.client.CoreV1().ConfigMaps(meta_v1.NamespaceSystem)
+
+	// Snapshots of other claims may be recorded meanwhile, so the snapshot is added to the latest
+	// version of the config map
+	err = retry.RetryOnConflict(retry.DefaultRetry, func() error {
+		cm, err := configMaps.Get(util.VolumeSnapshotsConfigMap, meta_v1.GetOptions{})
+		if apierrors.IsNotFound(err) {
+			cm = &v1.ConfigMap{
+				ObjectMeta: meta_v1.ObjectMeta{Name: util.VolumeSnapshotsConfigMap, Namespace: meta_v1.NamespaceSystem},
+				Data:       map[string]string{snapshot.Name: string(b)},
+			}
+			_, err = configMaps.Create(cm)
+			if apierrors.IsAlreadyExists(err) {
+				// Created meanwhile, retry to update it instead
+				return apierrors.NewConflict(v1.Resource("configmaps"), util.VolumeSnapshotsConfigMap, err)
+			}
+			return err
+		}
+		if err != nil {
+			return err
+		}
+		if cm.Data == nil {
+			cm.Data = map[string]string{}
+		}
+		cm.Data[snapshot.Name] = string(b)
+		_, err = configMaps.Update(cm)
+		return err
+	})
+	if err != nil {
+		return fmt.Errorf("Error recording snapshot %s: %v", snapshot.Name, err)
+	}
+	return nil
+}
+
+// copyDir copies the contents of src into the existing directory dst, keeping their owners,
+// permissions and links.
+func copyDir(src, dst string) error {
+	out, err := exec.New().Command("cp", "-a", src+"/.", dst).CombinedOutput()
+	if err != nil {
+		return fmt.Errorf("Error copying %s to %s: %v: %s", src, dst, err, out)
+	}
+	return nil
+}
@@ -0,0 +1,302 @@
+/*
+Copyright 2016 The Kubernetes Authors All rights reserved.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package localkube
+
+import (
+	"encoding/json"
+	"io/ioutil"
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"path/filepath"
+	"strings"
+	"sync"
+	"testing"
+
+	"github.com/r2d4/external-storage/lib/controller"
+	meta_v1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/client-go/kubernetes"
+	"k8s.io/client-go/pkg/api/v1"
+	"k8s.io/client-go/rest"
+
+	"k8s.io/minikube/pkg/util"
+)
+
+// newFakeAPIServer serves the objects in objects, keyed by their path, and stores the objects
+// created and updated through it.
+func newFakeAPIServer(t *testing.T, objects map[string]interface{}) *httptest.Server {
+	return httptest.NewServer(fakeAPIHandler(t, objects))
+}
+
+func fakeAPIHandler(t *testing.T, objects map[string]interface{}) http.Handler {
+	var lock sync.Mutex
+	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		lock.Lock()
+		defer lock.Unlock()
+		w.Header().Set("Content-Type", "application/json")
+		switch r.Method {
+		case "GET":
+			obj, ok := objects[r.URL.Path]
+			if !ok {
+				w.WriteHeader(http.StatusNotFound)
+				json.NewEncoder(w).Encode(meta_v1.Status{
+					TypeMeta: meta_v1.TypeMeta{Kind: "Status", APIVersion: "v1"},
+					Status:   meta_v1.StatusFailure,
+					Reason:   meta_v1.StatusReasonNotFound,
+					Code:     http.StatusNotFound,
+				})
+				return
+			}
+			json.NewEncoder(w).Encode(obj)
+		case "POST", "PUT":
+			obj := map[string]interface{}{}
+			if err := json.NewDecoder(r.Body).Decode(&obj); err != nil {
+				t.Errorf("Error decoding %s: %s", r.URL.Path, err)
+			}
+			key := r.URL.Path
+			if r.Method == "POST" {
+				key = key + "/" + obj["metadata"].(map[string]interface{})["name"].(string)
+			}
+			objects[key] = obj
+			json.NewEncoder(w).Encode(obj)
+		default:
+			t.Errorf("Unexpected request %s %s", r.Method, r.URL)
+		}
+	})
+}
+
+func TestVolumeSnapshots(t *testing.T) {
+	pvDir, err := ioutil.TempDir("", "hostpath-provisioner")
+	if err != nil {
+		t.Fatalf("Error creating temp dir: %s", err)
+	}
+	defer os.RemoveAll(pvDir)
+	if err := os.Mkdir(filepath.Join(pvDir, "pvc-source"), 0777); err != nil {
+		t.Fatalf("Error creating volume: %s", err)
+	}
+	if err := ioutil.WriteFile(filepath.Join(pvDir, "pvc-source", "seed"), []byte("seeded"), 0644); err != nil {
+		t.Fatalf("Error writing volume: %s", err)
+	}
+
+	source := &v1.PersistentVolumeClaim{
+		ObjectMeta: meta_v1.ObjectMeta{Name: "source", Namespace: "default"},
+		Spec: v1.PersistentVolumeClaimSpec{
+			VolumeName:  "pvc-source",
+			AccessModes: []v1.PersistentVolumeAccessMode{v1.ReadWriteOnce},
+		},
+		Status: v1.PersistentVolumeClaimStatus{Phase: v1.ClaimBound},
+	}
+	pv := hostPathPV("pvc-source", pvDir, "current")
+	server := newFakeAPIServer(t, map[string]interface{}{
+		"/api/v1/persistentvolumes/pvc-source":                     pv,
+		"/api/v1/namespaces/default/persistentvolumeclaims/source": source,
+	})
+	defer server.Close()
+	clientset, err := kubernetes.NewForConfig(&rest.Config{Host: server.URL})
+	if err != nil {
+		t.Fatalf("Error creating client: %s", err)
+	}
+	p := NewHostPathProvisioner(clientset, pvDir, nil, "current", "minikube", false)
+
+	if err := p.takeSnapshot(source, "seeded"); err != nil {
+		t.Fatalf("Error taking snapshot: %s", err)
+	}
+	if b, err := ioutil.ReadFile(filepath.Join(pvDir, volumeSnapshotsDir, "seeded", "seed")); err != nil || string(b) != "seeded" {
+		t.Fatalf("Expected the volume to be copied into the snapshot: %s", err)
+	}
+	snapshot, err := p.getSnapshot("seeded")
+	if err != nil || snapshot == nil {
+		t.Fatalf("Expected snapshot to be recorded, got %v: %v", snapshot, err)
+	}
+	if snapshot.Namespace != "default" || snapshot.Claim != "source" || snapshot.AccessModes[0] != "ReadWriteOnce" {
+		t.Errorf("Unexpected snapshot %+v", snapshot)
+	}
+
+	unbound := &v1.PersistentVolumeClaim{ObjectMeta: meta_v1.ObjectMeta{Name: "unbound", Namespace: "default"}}
+	for name, claim := range map[string]*v1.PersistentVolumeClaim{"seeded": source, "Invalid_Name": source, "unbound": unbound} {
+		if err := p.takeSnapshot(claim, name); err == nil {
+			t.Errorf("Expected an error taking snapshot %s of claim %s", name, claim.Name)
+		}
+	}
+
+	var tests = []struct {
+		description string
+		annotations map[string]string
+		shouldErr   bool
+	}{
+		{"restore", map[string]string{util.VolumeRestoreAnnotation: "seeded"}, false},
+		{"clone", map[string]string{util.VolumeCloneAnnotation: "source"}, false},
+		{"missing-snapshot", map[string]string{util.VolumeRestoreAnnotation: "missing"}, true},
+		{"missing-claim", map[string]string{util.VolumeCloneAnnotation: "missing"}, true},
+	}
+	for _, test := range tests {
+		t.Run(test.description, func(t *testing.T) {
+			name := "pvc-" + test.description
+			_, err := p.Provision(controller.VolumeOptions{
+				PersistentVolumeReclaimPolicy: v1.PersistentVolumeReclaimDelete,
+				PVName:                        name,
+				PVC: &v1.PersistentVolumeClaim{
+					ObjectMeta: meta_v1.ObjectMeta{Namespace: "default", Annotations: test.annotations},
+				},
+			})
+			if test.shouldErr {
+				if err == nil {
+					t.Fatalf("Expected an error")
+				}
+				if _, err := os.Stat(filepath.Join(pvDir, name)); !os.IsNotExist(err) {
+					t.Errorf("Expected the volume directory to be removed")
+				}
+				return
+			}
+			if err != nil {
+				t.Fatalf("Unexpected error: %s", err)
+			}
+			if b, err := ioutil.ReadFile(filepath.Join(pvDir, name, "seed")); err != nil || string(b) != "seeded" {
+				t.Errorf("Expected the volume to be populated: %s", err)
+			}
+		})
+	}
+}
+
+func TestSnapshotClaimRetriesConflicts(t *testing.T) {
+	pvDir, err := ioutil.TempDir("", "hostpath-provisioner")
+	if err != nil {
+		t.Fatalf("Error creating temp dir: %s", err)
+	}
+	defer os.RemoveAll(pvDir)
+	if err := os.Mkdir(filepath.Join(pvDir, "pvc-source"), 0777); err != nil {
+		t.Fatalf("Error creating volume: %s", err)
+	}
+
+	claimPath := "/api/v1/namespaces/default/persistentvolumeclaims/source"
+	objects := map[string]interface{}{
+		"/api/v1/persistentvolumes/pvc-source": hostPathPV("pvc-source", pvDir, "current"),
+		claimPath: &v1.PersistentVolumeClaim{
+			ObjectMeta: meta_v1.ObjectMeta{
+				Name:        "source",
+				Namespace:   "default",
+				Annotations: map[string]string{util.VolumeSnapshotRequestAnnotation: "requested"},
+			},
+			Spec:   v1.PersistentVolumeClaimSpec{VolumeName: "pvc-source"},
+			Status: v1.PersistentVolumeClaimStatus{Phase: v1.ClaimBound},
+		},
+	}
+	handler := fakeAPIHandler(t, objects)
+	conflicts := 0
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		// Reject the first update of the claim, as if it changed while the volume was copied
+		if r.Method == "PUT" && r.URL.Path == claimPath && conflicts == 0 {
+			conflicts++
+			w.Header().Set("Content-Type", "application/json")
+			w.WriteHeader(http.StatusConflict)
+			json.NewEncoder(w).Encode(meta_v1.Status{
+				TypeMeta: meta_v1.TypeMeta{Kind: "Status", APIVersion: "v1"},
+				Status:   meta_v1.StatusFailure,
+				Reason:   meta_v1.StatusReasonConflict,
+				Code:     http.StatusConflict,
+			})
+			return
+		}
+		handler.ServeHTTP(w, r)
+	}))
+	defer server.Close()
+	clientset, err := kubernetes.NewForConfig(&rest.Config{Host: server.URL})
+	if err != nil {
+		t.Fatalf("Error creating client: %s", err)
+	}
+	p := NewHostPathProvisioner(clientset, pvDir, nil, "current", "minikube", false)
+
+	if err := p.snapshotClaim("default/source"); err != nil {
+		t.Fatalf("Error handling snapshot request: %s", err)
+	}
+	if conflicts != 1 {
+		t.Fatalf("Expected the claim update to conflict once, got %d conflicts", conflicts)
+	}
+	claim, err := clientset.CoreV1().PersistentVolumeClaims("default").Get("source", meta_v1.GetOptions{})
+	if err != nil {
+		t.Fatalf("Error getting claim: %s", err)
+	}
+	if _, ok := claim.Annotations[util.VolumeSnapshotRequestAnnotation]; ok {
+		t.Errorf("Expected the snapshot request to be removed, got annotations %v", claim.Annotations)
+	}
+	if taken := claim.Annotations[util.VolumeSnapshotTakenAnnotation]; taken != "requested" {
+		t.Errorf("Expected snapshot requested to be recorded as taken, got annotations %v", claim.Annotations)
+	}
+
+	if err := p.snapshotClaim("default/missing"); err != nil {
+		t.Errorf("Expected a deleted claim to be ignored, got %s", err)
+	}
+}
+
+func TestRecordSnapshotRetriesConflicts(t *testing.T) {
+	snapshotsPath := "/api/v1/namespaces/kube-system/configmaps/" + util.VolumeSnapshotsConfigMap
+	for _, test := range []struct {
+		description string
+		// method is the request that conflicts, as another snapshot is recorded first
+		method string
+		reason meta_v1.StatusReason
+	}{
+		{"the config map is updated meanwhile", "PUT", meta_v1.StatusReasonConflict},
+		{"the config map is created meanwhile", "POST", meta_v1.StatusReasonAlreadyExists},
+	} {
+		objects := map[string]interface{}{}
+		if test.method == "PUT" {
+			objects[snapshotsPath] = &v1.ConfigMap{
+				ObjectMeta: meta_v1.ObjectMeta{Name: util.VolumeSnapshotsConfigMap, Namespace: meta_v1.NamespaceSystem},
+				Data:       map[string]string{},
+			}
+		}
+		handler := fakeAPIHandler(t, objects)
+		conflicts := 0
+		server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			if r.Method == test.method && strings.HasPrefix(r.URL.Path, "/api/v1/namespaces/kube-system/configmaps") && conflicts == 0 {
+				conflicts++
+				objects[snapshotsPath] = &v1.ConfigMap{
+					ObjectMeta: meta_v1.ObjectMeta{Name: util.VolumeSnapshotsConfigMap, Namespace: meta_v1.NamespaceSystem},
+					Data:       map[string]string{"concurrent": `{"name":"concurrent"}`},
+				}
+				w.Header().Set("Content-Type", "application/json")
+				w.WriteHeader(http.StatusConflict)
+				json.NewEncoder(w).Encode(meta_v1.Status{
+					TypeMeta: meta_v1.TypeMeta{Kind: "Status", APIVersion: "v1"},
+					Status:   meta_v1.StatusFailure,
+					Reason:   test.reason,
+					Code:     http.StatusConflict,
+				})
+				return
+			}
+			handler.ServeHTTP(w, r)
+		}))
+		clientset, err := kubernetes.NewForConfig(&rest.Config{Host: server.URL})
+		if err != nil {
+			t.Fatalf("Error creating client: %s", err)
+		}
+		p := NewHostPathProvisioner(clientset, "", nil, "current", "minikube", false)
+
+		if err := p.recordSnapshot(util.VolumeSnapshot{Name: "recorded"}); err != nil {
+			t.Errorf("Error recording snapshot when %s: %s", test.description, err)
+		}
+		cm, err := clientset.CoreV1().ConfigMaps(meta_v1.NamespaceSystem).Get(util.VolumeSnapshotsConfigMap, meta_v1.GetOptions{})
+		if err != nil {
+			t.Fatalf("Error getting volume snapshots: %s", err)
+		}
+		if _, ok := cm.Data["recorded"]; !ok || len(cm.Data) != 2 {
+			t.Errorf("Expected both snapshots to be recorded when %s, got %v", test.description, cm.Data)
+		}
+		server.Close()
+	}
+}
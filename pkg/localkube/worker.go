@@ -0,0 +1,105 @@
+/*
+Copyright 2016 The Kubernetes Authors All rights reserved.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package localkube
+
+import (
+	"net/url"
+	"os"
+	"path"
+
+	"github.com/pkg/errors"
+	"k8s.io/client-go/tools/clientcmd"
+	clientcmdapi "k8s.io/client-go/tools/clientcmd/api"
+)
+
+const workerContext = "localkube"
+
+// IsWorker returns whether localkube joins the apiserver of another localkube as a worker node,
+// running only the kubelet and proxy.
+func (lk LocalkubeServer) IsWorker() bool {
+	return lk.JoinAPIServer != ""
+}
+
+// GetNodeCertPath returns the client certificate a worker authenticates to the apiserver with.
+func (lk LocalkubeServer) GetNodeCertPath() string {
+	return path.Join(lk.GetCertificateDirectory(), "node.crt")
+}
+func (lk LocalkubeServer) GetNodeKeyPath() string {
+	return path.Join(lk.GetCertificateDirectory(), "node.key")
+}
+
+// GetWorkerKubeconfigPath returns the kubeconfig the kubelet and proxy of a worker connect to
+// the apiserver with.
+func (lk LocalkubeServer) GetWorkerKubeconfigPath() string {
+	return path.Join(lk.LocalkubeDirectory, "kubeconfig")
+}
+
+// apiserverDependencies returns the servers that have to be ready before the ones talking to the
+// apiserver start. A worker has none, as its apiserver runs on another node.
+func (lk LocalkubeServer) apiserverDependencies() []string {
+	if lk.IsWorker() {
+		return nil
+	}
+	return []string{"apiserver"}
+}
+
+// ValidateWorkerConfig checks the apiserver to join is an https URL, and that the CA and node
+// certificates it is verified and authenticated with exist.
+func (lk LocalkubeServer) ValidateWorkerConfig() error {
+	if !lk.IsWorker() {
+		return nil
+	}
+	u, err := url.Parse(lk.JoinAPIServer)
+	if err != nil {
+		return errors.Wrapf(err, "Invalid apiserver %q", lk.JoinAPIServer)
+	}
+	if u.Scheme != "https" || u.Host == "" {
+		return errors.Errorf("Invalid apiserver %q, must be an https URL", lk.JoinAPIServer)
+	}
+	for _, p := range []string{lk.GetCAPublicKeyCertPath(), lk.GetNodeCertPath(), lk.GetNodeKeyPath()} {
+		if _, err := os.Stat(p); err != nil {
+			return errors.Wrap(err, "Error reading the certificates to join the apiserver with")
+		}
+	}
+	return nil
+}
+
+// WriteWorkerKubeconfig writes the kubeconfig of a worker, which connects to the joined apiserver
+// with the node certificate.
+func (lk LocalkubeServer) WriteWorkerKubeconfig() error {
+	config := clientcmdapi.NewConfig()
+	cluster := clientcmdapi.NewCluster()
+	cluster.Server = lk.JoinAPIServer
+	cluster.CertificateAuthority = lk.GetCAPublicKeyCertPath()
+	config.Clusters[workerContext] = cluster
+
+	user := clientcmdapi.NewAuthInfo()
+	user.ClientCertificate = lk.GetNodeCertPath()
+	user.ClientKey = lk.GetNodeKeyPath()
+	config.AuthInfos[workerContext] = user
+
+	context := clientcmdapi.NewContext()
+	context.Cluster = workerContext
+	context.AuthInfo = workerContext
+	config.Contexts[workerContext] = context
+	config.CurrentContext = workerContext
+
+	if err := clientcmd.WriteToFile(*config, lk.GetWorkerKubeconfigPath()); err != nil {
+		return errors.Wrap(err, "Error writing worker kubeconfig")
+	}
+	return nil
+}
@@ -0,0 +1,129 @@
+/*
+Copyright 2016 The Kubernetes Authors All rights reserved.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package localkube
+
+import (
+	"io/ioutil"
+	"os"
+	"reflect"
+	"testing"
+
+	"k8s.io/client-go/tools/clientcmd"
+)
+
+func TestApiserverDependencies(t *testing.T) {
+	lk := LocalkubeServer{}
+	if lk.IsWorker() || !reflect.DeepEqual(lk.apiserverDependencies(), []string{"apiserver"}) {
+		t.Fatalf("Expected the kubelet and proxy to depend on the apiserver, got %v", lk.apiserverDependencies())
+	}
+
+	lk.JoinAPIServer = "https://192.168.99.100:8443"
+	if !lk.IsWorker() || len(lk.apiserverDependencies()) != 0 {
+		t.Fatalf("Expected a worker to have no dependencies, got %v", lk.apiserverDependencies())
+	}
+}
+
+func TestValidateWorkerConfig(t *testing.T) {
+	dir, err := ioutil.TempDir("", "worker")
+	if err != nil {
+		t.Fatalf("Error creating temp dir: %s", err)
+	}
+	defer os.RemoveAll(dir)
+	noCerts, err := ioutil.TempDir("", "worker")
+	if err != nil {
+		t.Fatalf("Error creating temp dir: %s", err)
+	}
+	defer os.RemoveAll(noCerts)
+	lk := LocalkubeServer{LocalkubeDirectory: dir}
+	if err := os.MkdirAll(lk.GetCertificateDirectory(), 0700); err != nil {
+		t.Fatalf("Error creating certificate dir: %s", err)
+	}
+	for _, f := range []string{lk.GetCAPublicKeyCertPath(), lk.GetNodeCertPath(), lk.GetNodeKeyPath()} {
+		if err := ioutil.WriteFile(f, []byte{}, 0600); err != nil {
+			t.Fatalf("Error writing %s: %s", f, err)
+		}
+	}
+
+	var tests = []struct {
+		description string
+		lk          LocalkubeServer
+		shouldErr   bool
+	}{
+		{
+			description: "not a worker",
+			lk:          LocalkubeServer{LocalkubeDirectory: noCerts},
+		},
+		{
+			description: "worker",
+			lk:          LocalkubeServer{LocalkubeDirectory: dir, JoinAPIServer: "https://192.168.99.100:8443"},
+		},
+		{
+			description: "apiserver is not https",
+			lk:          LocalkubeServer{LocalkubeDirectory: dir, JoinAPIServer: "http://192.168.99.100:8080"},
+			shouldErr:   true,
+		},
+		{
+			description: "apiserver is not a URL",
+			lk:          LocalkubeServer{LocalkubeDirectory: dir, JoinAPIServer: "192.168.99.100:8443"},
+			shouldErr:   true,
+		},
+		{
+			description: "missing certificates",
+			lk:          LocalkubeServer{LocalkubeDirectory: noCerts, JoinAPIServer: "https://192.168.99.100:8443"},
+			shouldErr:   true,
+		},
+	}
+
+	for _, test := range tests {
+		t.Run(test.description, func(t *testing.T) {
+			err := test.lk.ValidateWorkerConfig()
+			if err != nil && !test.shouldErr {
+				t.Errorf("Unexpected error: %s", err)
+			}
+			if err == nil && test.shouldErr {
+				t.Errorf("Expected an error")
+			}
+		})
+	}
+}
+
+func TestWriteWorkerKubeconfig(t *testing.T) {
+	dir, err := ioutil.TempDir("", "worker")
+	if err != nil {
+		t.Fatalf("Error creating temp dir: %s", err)
+	}
+	defer os.RemoveAll(dir)
+	lk := LocalkubeServer{LocalkubeDirectory: dir, JoinAPIServer: "https://192.168.99.100:8443"}
+
+	if err := lk.WriteWorkerKubeconfig(); err != nil {
+		t.Fatalf("Error writing kubeconfig: %s", err)
+	}
+	config, err := clientcmd.LoadFromFile(lk.GetWorkerKubeconfigPath())
+	if err != nil {
+		t.Fatalf("Error loading kubeconfig: %s", err)
+	}
+	context := config.Contexts[config.CurrentContext]
+	if context == nil {
+		t.Fatalf("Expected a current context, got %q", config.CurrentContext)
+	}
+	if cluster := config.Clusters[context.Cluster]; cluster.Server != lk.JoinAPIServer || cluster.CertificateAuthority != lk.GetCAPublicKeyCertPath() {
+		t.Errorf("Unexpected cluster %+v", cluster)
+	}
+	if user := config.AuthInfos[context.AuthInfo]; user.ClientCertificate != lk.GetNodeCertPath() || user.ClientKey != lk.GetNodeKeyPath() {
+		t.Errorf("Unexpected user %+v", user)
+	}
+}
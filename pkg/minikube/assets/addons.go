@@ -17,11 +17,14 @@ limitations under the License.
 package assets
 
 import (
+	"bytes"
 	"os"
 	"path/filepath"
 	"strconv"
+	"text/template"
 
 	"github.com/golang/glog"
+	"github.com/pkg/errors"
 	"k8s.io/minikube/pkg/minikube/config"
 	"k8s.io/minikube/pkg/minikube/constants"
 	"k8s.io/minikube/pkg/util"
@@ -81,6 +84,8 @@ var Addons = map[string]*Addon{
 			"storageclass.yaml",
 			"0640"),
 	}, true, "default-storageclass"),
+	// The assets of the storage classes declared in the minikube config are generated when the addon is transferred
+	constants.CustomStorageClassesAddon: NewAddon([]*MemoryAsset{}, true, constants.CustomStorageClassesAddon),
 	"kube-dns": NewAddon([]*MemoryAsset{
 		NewMemoryAsset(
 			"deploy/addons/kube-dns/kube-dns-controller.yaml",
@@ -163,6 +168,33 @@ var Addons = map[string]*Addon{
 	}, false, "registry-creds"),
 }
 
+// kubeDNSServiceManifest is the kube-dns service manifest, a template of the cluster IP of the service.
+const kubeDNSServiceManifest = "kube-dns-svc.yaml"
+
+// LoadKubeDNSAddon renders the kube-dns service manifest of the kube-dns addon with the cluster IP dnsIP.
+func LoadKubeDNSAddon(dnsIP string) error {
+	contents, err := Asset("deploy/addons/kube-dns/" + kubeDNSServiceManifest)
+	if err != nil {
+		return errors.Wrap(err, "Error reading kube-dns service manifest")
+	}
+	tmpl, err := template.New(kubeDNSServiceManifest).Parse(string(contents))
+	if err != nil {
+		return errors.Wrap(err, "Error parsing kube-dns service manifest")
+	}
+	var manifest bytes.Buffer
+	if err := tmpl.Execute(&manifest, struct{ DNSIP string }{dnsIP}); err != nil {
+		return errors.Wrap(err, "Error rendering kube-dns service manifest")
+	}
+
+	addon := Addons["kube-dns"]
+	for i, a := range addon.Assets {
+		if a.GetTargetName() == kubeDNSServiceManifest {
+			addon.Assets[i] = NewMemoryAssetFromBytes(manifest.Bytes(), a.GetTargetDir(), a.GetTargetName(), a.GetPermissions())
+		}
+	}
+	return nil
+}
+
 func AddMinikubeAddonsDirToAssets(assetList *[]CopyableFile) {
 	// loop over .minikube/addons and add them to assets
 	searchDir := constants.MakeMiniPath("addons")
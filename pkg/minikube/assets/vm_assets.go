@@ -119,6 +119,21 @@ func NewMemoryAsset(assetName, targetDir, targetName, permissions string) *Memor
 	return m
 }
 
+// NewMemoryAssetFromBytes creates an asset of data, which is generated rather than bundled.
+func NewMemoryAssetFromBytes(data []byte, targetDir, targetName, permissions string) *MemoryAsset {
+	m := &MemoryAsset{
+		BaseAsset{
+			data:        data,
+			reader:      bytes.NewReader(data),
+			Length:      len(data),
+			TargetDir:   targetDir,
+			TargetName:  targetName,
+			Permissions: permissions,
+		},
+	}
+	return m
+}
+
 func (m *MemoryAsset) loadData() error {
 	contents, err := Asset(m.AssetName)
 	if err != nil {
@@ -130,6 +145,11 @@ func (m *MemoryAsset) loadData() error {
 	return nil
 }
 
+// GetData returns the contents of the asset without reading it.
+func (m *MemoryAsset) GetData() []byte {
+	return m.data
+}
+
 func (m *MemoryAsset) GetLength() int {
 	return m.Length
 }
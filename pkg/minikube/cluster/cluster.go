@@ -20,6 +20,7 @@ import (
 	"encoding/json"
 	"flag"
 	"fmt"
+	"io/ioutil"
 	"net"
 	"os"
 	"os/exec"
@@ -42,6 +43,7 @@ import (
 	cfg "k8s.io/minikube/pkg/minikube/config"
 	"k8s.io/minikube/pkg/minikube/constants"
 	"k8s.io/minikube/pkg/minikube/sshutil"
+	"k8s.io/minikube/pkg/minikube/storageclass"
 	"k8s.io/minikube/pkg/util"
 )
 
@@ -60,16 +62,17 @@ func init() {
 
 // StartHost starts a host VM.
 func StartHost(api libmachine.API, config MachineConfig) (*host.Host, error) {
-	exists, err := api.Exists(cfg.GetMachineName())
+	name := config.GetMachineName()
+	exists, err := api.Exists(name)
 	if err != nil {
-		return nil, errors.Wrapf(err, "Error checking if host exists: %s", cfg.GetMachineName())
+		return nil, errors.Wrapf(err, "Error checking if host exists: %s", name)
 	}
 	if !exists {
 		return createHost(api, config)
 	}
 
 	glog.Infoln("Machine exists!")
-	h, err := api.Load(cfg.GetMachineName())
+	h, err := api.Load(name)
 	if err != nil {
 		return nil, errors.Wrap(err, "Error loading existing host. Please try running [minikube delete], then run [minikube start] again.")
 	}
@@ -99,45 +102,57 @@ func StartHost(api libmachine.API, config MachineConfig) (*host.Host, error) {
 
 // StopHost stops the host VM.
 func StopHost(api libmachine.API) error {
-	host, err := api.Load(cfg.GetMachineName())
+	return stopHost(api, cfg.GetMachineName())
+}
+
+func stopHost(api libmachine.API, name string) error {
+	host, err := api.Load(name)
 	if err != nil {
-		return errors.Wrapf(err, "Error loading host: %s", cfg.GetMachineName())
+		return errors.Wrapf(err, "Error loading host: %s", name)
 	}
 	if err := host.Stop(); err != nil {
 		alreadyInStateError, ok := err.(mcnerror.ErrHostAlreadyInState)
 		if ok && alreadyInStateError.State == state.Stopped {
 			return nil
 		}
-		return errors.Wrapf(err, "Error stopping host: %s", cfg.GetMachineName())
+		return errors.Wrapf(err, "Error stopping host: %s", name)
 	}
 	return nil
 }
 
 // DeleteHost deletes the host VM.
 func DeleteHost(api libmachine.API) error {
-	host, err := api.Load(cfg.GetMachineName())
+	return deleteHost(api, cfg.GetMachineName())
+}
+
+func deleteHost(api libmachine.API, name string) error {
+	host, err := api.Load(name)
 	if err != nil {
-		return errors.Wrapf(err, "Error deleting host: %s", cfg.GetMachineName())
+		return errors.Wrapf(err, "Error deleting host: %s", name)
 	}
 	m := util.MultiError{}
 	m.Collect(host.Driver.Remove())
-	m.Collect(api.Remove(cfg.GetMachineName()))
+	m.Collect(api.Remove(name))
 	return m.ToError()
 }
 
 // GetHostStatus gets the status of the host VM.
 func GetHostStatus(api libmachine.API) (string, error) {
-	exists, err := api.Exists(cfg.GetMachineName())
+	return getHostStatus(api, cfg.GetMachineName())
+}
+
+func getHostStatus(api libmachine.API, name string) (string, error) {
+	exists, err := api.Exists(name)
 	if err != nil {
-		return "", errors.Wrapf(err, "Error checking that api exists for: %s", cfg.GetMachineName())
+		return "", errors.Wrapf(err, "Error checking that api exists for: %s", name)
 	}
 	if !exists {
 		return state.None.String(), nil
 	}
 
-	host, err := api.Load(cfg.GetMachineName())
+	host, err := api.Load(name)
 	if err != nil {
-		return "", errors.Wrapf(err, "Error loading api for: %s", cfg.GetMachineName())
+		return "", errors.Wrapf(err, "Error loading api for: %s", name)
 	}
 
 	s, err := host.Driver.GetState()
@@ -148,25 +163,161 @@ func GetHostStatus(api libmachine.API) (string, error) {
 }
 
 // GetLocalkubeStatus gets the status of localkube from the host VM.
+// A running localkube with components that are not ready is reported as unhealthy.
 func GetLocalkubeStatus(api libmachine.API) (string, error) {
 	h, err := CheckIfApiExistsAndLoad(api)
 	if err != nil {
 		return "", err
 	}
+	return getLocalkubeStatus(h)
+}
+
+func getLocalkubeStatus(h *host.Host) (string, error) {
 	s, err := RunCommand(h, localkubeStatusCommand, false)
 	if err != nil {
 		return "", err
 	}
 	s = strings.TrimSpace(s)
 	if state.Running.String() == s {
+		components, err := getLocalkubeComponentStatus(h)
+		if err != nil {
+			// Older localkube versions don't serve their status, so fall back to the process state.
+			glog.Infoln("Unable to get localkube component status: ", err)
+			return state.Running.String(), nil
+		}
+		for _, c := range components {
+			if !c.Ready {
+				return constants.LocalkubeUnhealthy, nil
+			}
+		}
 		return state.Running.String(), nil
 	} else if state.Stopped.String() == s {
 		return state.Stopped.String(), nil
+	} else if constants.LocalkubePaused == s {
+		return constants.LocalkubePaused, nil
 	} else {
 		return "", fmt.Errorf("Error: Unrecognize output from GetLocalkubeStatus: %s", s)
 	}
 }
 
+// PauseCluster freezes localkube and the containers running in the host VM, which keeps running.
+func PauseCluster(api libmachine.API) error {
+	h, err := CheckIfApiExistsAndLoad(api)
+	if err != nil {
+		return err
+	}
+	if _, err := RunCommand(h, pauseCommand, false); err != nil {
+		return errors.Wrap(err, "Error pausing cluster")
+	}
+	return nil
+}
+
+// UnpauseCluster resumes the containers and localkube frozen by PauseCluster.
+func UnpauseCluster(api libmachine.API) error {
+	h, err := CheckIfApiExistsAndLoad(api)
+	if err != nil {
+		return err
+	}
+	if _, err := RunCommand(h, unpauseCommand, false); err != nil {
+		return errors.Wrap(err, "Error unpausing cluster")
+	}
+	return nil
+}
+
+// GetLocalkubeComponentStatus gets the health of each localkube component from the host VM.
+func GetLocalkubeComponentStatus(api libmachine.API) ([]util.ComponentStatus, error) {
+	h, err := CheckIfApiExistsAndLoad(api)
+	if err != nil {
+		return nil, err
+	}
+	return getLocalkubeComponentStatus(h)
+}
+
+func getLocalkubeComponentStatus(h *host.Host) ([]util.ComponentStatus, error) {
+	out, err := RunCommand(h, localkubeComponentStatusCommand, false)
+	if err != nil {
+		return nil, errors.Wrap(err, "Error getting localkube component status")
+	}
+	components := []util.ComponentStatus{}
+	if err := json.Unmarshal([]byte(out), &components); err != nil {
+		return nil, errors.Wrapf(err, "Error parsing localkube component status: %s", out)
+	}
+	return components, nil
+}
+
+// GetLocalkubeMetrics scrapes the Prometheus metrics of the localkube components once, and returns
+// them in the Prometheus text format.
+func GetLocalkubeMetrics(api libmachine.API) (string, error) {
+	h, err := CheckIfApiExistsAndLoad(api)
+	if err != nil {
+		return "", err
+	}
+	out, err := RunCommand(h, localkubeMetricsCommand, false)
+	if err != nil {
+		return "", errors.Wrap(err, "Error getting localkube metrics")
+	}
+	return out, nil
+}
+
+// GetEtcdSnapshot asks localkube for a snapshot of the cluster's etcd and returns it.
+func GetEtcdSnapshot(api libmachine.API) ([]byte, error) {
+	h, err := CheckIfApiExistsAndLoad(api)
+	if err != nil {
+		return nil, err
+	}
+	out, err := RunCommand(h, etcdSnapshotCommand, false)
+	if err != nil {
+		return nil, errors.Wrap(err, "Error getting etcd snapshot")
+	}
+	if err := json.Unmarshal([]byte(out), &util.EtcdSnapshot{}); err != nil {
+		return nil, errors.Wrap(err, "Error parsing etcd snapshot")
+	}
+	return []byte(out), nil
+}
+
+// RestoreEtcdSnapshot copies the etcd snapshot at snapshotPath into the running VM and makes
+// localkube restart, restoring it before the cluster components start again.
+func RestoreEtcdSnapshot(api libmachine.API, snapshotPath string) error {
+	h, err := CheckIfApiExistsAndLoad(api)
+	if err != nil {
+		return err
+	}
+	f, err := assets.NewFileAsset(snapshotPath, util.DefaultLocalkubeDirectory, util.EtcdRestoreFile, "0640")
+	if err != nil {
+		return errors.Wrap(err, "Error reading etcd snapshot")
+	}
+	if err := transferFiles(h.Driver, []assets.CopyableFile{f}); err != nil {
+		return errors.Wrap(err, "Error copying etcd snapshot to the VM")
+	}
+
+	if _, err := RunCommand(h, localkubeReloadCommand, false); err != nil {
+		return errors.Wrap(err, "Error restarting localkube")
+	}
+	return nil
+}
+
+// getStagedEtcdRestorePath returns where a snapshot restored into a stopped cluster waits for
+// the cluster to start.
+func getStagedEtcdRestorePath() string {
+	return constants.MakeMiniPath("profiles", cfg.GetMachineName(), util.EtcdRestoreFile)
+}
+
+// StageEtcdRestore copies the etcd snapshot at snapshotPath next to the profile, for the next
+// UpdateCluster to copy into the VM, so that localkube restores it before the cluster starts.
+func StageEtcdRestore(snapshotPath string) error {
+	contents, err := ioutil.ReadFile(snapshotPath)
+	if err != nil {
+		return errors.Wrap(err, "Error reading etcd snapshot")
+	}
+	if err := os.MkdirAll(filepath.Dir(getStagedEtcdRestorePath()), 0700); err != nil {
+		return errors.Wrap(err, "Error creating profile directory")
+	}
+	if err := ioutil.WriteFile(getStagedEtcdRestorePath(), contents, 0600); err != nil {
+		return errors.Wrap(err, "Error staging etcd snapshot")
+	}
+	return nil
+}
+
 // GetHostDriverIP gets the ip address of the current minikube cluster
 func GetHostDriverIP(api libmachine.API) (net.IP, error) {
 	host, err := CheckIfApiExistsAndLoad(api)
@@ -191,7 +342,18 @@ func StartCluster(api libmachine.API, kubernetesConfig KubernetesConfig) error {
 	if err != nil {
 		return errors.Wrap(err, "Error checking that api exists and loading it")
 	}
+	nodes, err := GetNodes()
+	if err != nil {
+		return err
+	}
+	kubernetesConfig, err = withNodeNetwork(kubernetesConfig, nodes)
+	if err != nil {
+		return err
+	}
+	return startLocalkube(h, kubernetesConfig)
+}
 
+func startLocalkube(h *host.Host, kubernetesConfig KubernetesConfig) error {
 	startCommand, err := GetStartCommand(kubernetesConfig)
 	if err != nil {
 		return errors.Wrapf(err, "Error generating start command: %s", err)
@@ -207,28 +369,64 @@ func StartCluster(api libmachine.API, kubernetesConfig KubernetesConfig) error {
 
 func UpdateCluster(d drivers.Driver, config KubernetesConfig) error {
 	copyableFiles := []assets.CopyableFile{}
-	var localkubeFile assets.CopyableFile
-	var err error
 
 	//add url/file/bundled localkube to file list
-	if localkubeURIWasSpecified(config) && config.KubernetesVersion != constants.DefaultKubernetesVersion {
-		lCacher := localkubeCacher{config}
-		localkubeFile, err = lCacher.fetchLocalkubeFromURI()
+	localkubeFile, err := getLocalkubeFile(config)
+	if err != nil {
+		return err
+	}
+	copyableFiles = append(copyableFiles, localkubeFile)
+
+	// add the external etcd's TLS files to file list
+	for _, f := range getEtcdTLSFiles(config) {
+		tlsFile, err := assets.NewFileAsset(f.hostPath, util.DefaultCertPath, f.name, f.permissions)
 		if err != nil {
-			return errors.Wrap(err, "Error updating localkube from uri")
+			return errors.Wrapf(err, "Error reading --%s", f.flag)
 		}
-	} else {
-		localkubeFile = assets.NewMemoryAsset("out/localkube", "/usr/local/bin", "localkube", "0777")
+		copyableFiles = append(copyableFiles, tlsFile)
+	}
+
+	// add the authorization webhook's kubeconfig to file list
+	if config.AuthorizationWebhookFile != "" {
+		webhookFile, err := assets.NewFileAsset(config.AuthorizationWebhookFile, util.DefaultLocalkubeDirectory, constants.AuthorizationWebhookFileName, "0600")
+		if err != nil {
+			return errors.Wrap(err, "Error reading --authorization-webhook-config-file")
+		}
+		copyableFiles = append(copyableFiles, webhookFile)
 	}
-	copyableFiles = append(copyableFiles, localkubeFile)
 
 	// add addons to file list
 	// custom addons
 	assets.AddMinikubeAddonsDirToAssets(&copyableFiles)
 	// bundled addons
+	if err := storageclass.LoadAddon(config.StorageClasses); err != nil {
+		return errors.Wrap(err, "Error loading storage classes")
+	}
+	dnsIP := config.DNSIP
+	if dnsIP == "" {
+		dnsIP = util.DefaultDNSIP
+	}
+	if err := assets.LoadKubeDNSAddon(dnsIP); err != nil {
+		return errors.Wrap(err, "Error loading kube-dns addon")
+	}
+	// add the etcd snapshot staged while the cluster was stopped to file list
+	restorePath := getStagedEtcdRestorePath()
+	restoreStaged := util.CanReadFile(restorePath)
+	if restoreStaged {
+		restoreFile, err := assets.NewFileAsset(restorePath, util.DefaultLocalkubeDirectory, util.EtcdRestoreFile, "0640")
+		if err != nil {
+			return errors.Wrap(err, "Error reading the staged etcd snapshot")
+		}
+		copyableFiles = append(copyableFiles, restoreFile)
+	}
+
 	for _, addonBundle := range assets.Addons {
 		if isEnabled, err := addonBundle.IsEnabled(); err == nil && isEnabled {
 			for _, addon := range addonBundle.Assets {
+				// skip manifests without content, e.g. when no storage classes are declared
+				if addon.GetLength() == 0 {
+					continue
+				}
 				copyableFiles = append(copyableFiles, addon)
 			}
 		} else if err != nil {
@@ -236,6 +434,34 @@ func UpdateCluster(d drivers.Driver, config KubernetesConfig) error {
 		}
 	}
 
+	if err := transferFiles(d, copyableFiles); err != nil {
+		return err
+	}
+	if restoreStaged {
+		// localkube restores the copy in the VM when it starts
+		if err := os.Remove(restorePath); err != nil {
+			return errors.Wrap(err, "Error removing the staged etcd snapshot")
+		}
+	}
+	return nil
+}
+
+// getLocalkubeFile returns the localkube binary of the Kubernetes version, which is downloaded
+// unless it is the bundled one.
+func getLocalkubeFile(config KubernetesConfig) (assets.CopyableFile, error) {
+	if localkubeURIWasSpecified(config) && config.KubernetesVersion != constants.DefaultKubernetesVersion {
+		lCacher := localkubeCacher{config}
+		localkubeFile, err := lCacher.fetchLocalkubeFromURI()
+		if err != nil {
+			return nil, errors.Wrap(err, "Error updating localkube from uri")
+		}
+		return localkubeFile, nil
+	}
+	return assets.NewMemoryAsset("out/localkube", "/usr/local/bin", "localkube", "0777"), nil
+}
+
+// transferFiles copies the files into the VM, or to their place on the host for the none driver.
+func transferFiles(d drivers.Driver, copyableFiles []assets.CopyableFile) error {
 	if d.DriverName() == "none" {
 		// transfer files to correct place on filesystem
 		for _, f := range copyableFiles {
@@ -260,13 +486,36 @@ func UpdateCluster(d drivers.Driver, config KubernetesConfig) error {
 	return nil
 }
 
+// etcdTLSFile is a TLS file for an external etcd that is copied into the VM and passed to localkube.
+type etcdTLSFile struct {
+	flag        string
+	hostPath    string
+	name        string
+	permissions string
+}
+
+func getEtcdTLSFiles(config KubernetesConfig) []etcdTLSFile {
+	files := []etcdTLSFile{}
+	for _, f := range []etcdTLSFile{
+		{"etcd-cafile", config.EtcdCAFile, constants.EtcdCAFileName, "0644"},
+		{"etcd-certfile", config.EtcdCertFile, constants.EtcdCertFileName, "0644"},
+		{"etcd-keyfile", config.EtcdKeyFile, constants.EtcdKeyFileName, "0600"},
+	} {
+		if f.hostPath != "" {
+			files = append(files, f)
+		}
+	}
+	return files
+}
+
 func localkubeURIWasSpecified(config KubernetesConfig) bool {
 	// see if flag is different than default -> it was passed by user
 	return config.KubernetesVersion != constants.DefaultKubernetesVersion
 }
 
-// SetupCerts gets the generated credentials required to talk to the APIServer.
-func SetupCerts(d drivers.Driver, apiServerName string, clusterDnsDomain string) error {
+// SetupCerts gets the generated credentials required to talk to the APIServer. The apiserver
+// certificate is regenerated each time, so it follows changes to extraSANs.
+func SetupCerts(d drivers.Driver, apiServerName string, clusterDnsDomain string, serviceCIDR string, extraSANs []string) error {
 	localPath := constants.GetMinipath()
 	ipStr, err := d.GetIP()
 	if err != nil {
@@ -275,14 +524,85 @@ func SetupCerts(d drivers.Driver, apiServerName string, clusterDnsDomain string)
 	glog.Infoln("Setting up certificates for IP: %s", ipStr)
 
 	ip := net.ParseIP(ipStr)
+	if serviceCIDR == "" {
+		serviceCIDR = util.DefaultServiceCIDR
+	}
+	_, serviceIPRange, err := net.ParseCIDR(serviceCIDR)
+	if err != nil {
+		return errors.Wrap(err, "Error parsing service cluster IP range")
+	}
 	caCert := filepath.Join(localPath, "ca.crt")
 	caKey := filepath.Join(localPath, "ca.key")
 	publicPath := filepath.Join(localPath, "apiserver.crt")
 	privatePath := filepath.Join(localPath, "apiserver.key")
-	if err := GenerateCerts(caCert, caKey, publicPath, privatePath, ip, apiServerName, clusterDnsDomain); err != nil {
+	if err := GenerateCerts(caCert, caKey, publicPath, privatePath, ip, util.GetServiceClusterIP(*serviceIPRange), apiServerName, clusterDnsDomain, extraSANs); err != nil {
 		return errors.Wrap(err, "Error generating certs")
 	}
+	return transferCerts(d)
+}
+
+// RotateCerts replaces the apiserver certificate, which is also the client certificate of the
+// kubeconfig, with a new one for the same IPs and names, and the CA before it if rotateCA is set.
+// The apiserver key is kept, as the service account tokens are signed with it. The certificates
+// are copied into the VM and localkube restarts to load them.
+func RotateCerts(api libmachine.API, rotateCA bool) error {
+	h, err := CheckIfApiExistsAndLoad(api)
+	if err != nil {
+		return errors.Wrap(err, "Error checking that api exists and loading it")
+	}
+	localPath := constants.GetMinipath()
+	caCert := filepath.Join(localPath, "ca.crt")
+	caKey := filepath.Join(localPath, "ca.key")
+	publicPath := filepath.Join(localPath, "apiserver.crt")
+	privatePath := filepath.Join(localPath, "apiserver.key")
+
+	cert, err := util.LoadCert(publicPath)
+	if err != nil {
+		return errors.Wrap(err, "Error loading the apiserver certificate, run minikube start to create it")
+	}
+	ips := cert.IPAddresses
+	ipStr, err := h.Driver.GetIP()
+	if err != nil {
+		return errors.Wrap(err, "Error getting ip from driver")
+	}
+	if ip := net.ParseIP(ipStr); ip != nil && !containsIP(ips, ip) {
+		ips = append(ips, ip)
+	}
+
+	if rotateCA {
+		name := constants.APIServerName
+		if ca, err := util.LoadCert(caCert); err == nil {
+			name = ca.Subject.CommonName
+		}
+		if err := util.GenerateCACert(caCert, caKey, name); err != nil {
+			return errors.Wrap(err, "Error generating CA certificate")
+		}
+	}
+	if err := util.GenerateSignedCert(publicPath, privatePath, ips, cert.DNSNames, caCert, caKey); err != nil {
+		return errors.Wrap(err, "Error generating apiserver certificate")
+	}
+
+	if err := transferCerts(h.Driver); err != nil {
+		return errors.Wrap(err, "Error copying certificates to the VM")
+	}
+	if _, err := RunCommand(h, localkubeReloadCommand, false); err != nil {
+		return errors.Wrap(err, "Error restarting localkube")
+	}
+	return nil
+}
 
+func containsIP(ips []net.IP, ip net.IP) bool {
+	for _, i := range ips {
+		if i.Equal(ip) {
+			return true
+		}
+	}
+	return false
+}
+
+// transferCerts copies the certificates in the minikube directory into the VM.
+func transferCerts(d drivers.Driver) error {
+	localPath := constants.GetMinipath()
 	copyableFiles := []assets.CopyableFile{}
 
 	for _, cert := range certs {
@@ -298,28 +618,7 @@ func SetupCerts(d drivers.Driver, apiServerName string, clusterDnsDomain string)
 		copyableFiles = append(copyableFiles, certFile)
 	}
 
-	if d.DriverName() == "none" {
-		// transfer files to correct place on filesystem
-		for _, f := range copyableFiles {
-			if err := assets.CopyFileLocal(f); err != nil {
-				return err
-			}
-		}
-		return nil
-	}
-
-	// transfer files to vm via SSH
-	client, err := sshutil.NewSSHClient(d)
-	if err != nil {
-		return errors.Wrap(err, "Error creating new ssh client")
-	}
-
-	for _, f := range copyableFiles {
-		if err := sshutil.TransferFile(f, client); err != nil {
-			return err
-		}
-	}
-	return nil
+	return transferFiles(d, copyableFiles)
 }
 
 func engineOptions(config MachineConfig) *engine.Options {
@@ -334,7 +633,7 @@ func engineOptions(config MachineConfig) *engine.Options {
 }
 
 func createVirtualboxHost(config MachineConfig) drivers.Driver {
-	d := virtualbox.NewDriver(cfg.GetMachineName(), constants.GetMinipath())
+	d := virtualbox.NewDriver(config.GetMachineName(), constants.GetMinipath())
 	d.Boot2DockerURL = config.Downloader.GetISOFileURI(config.MinikubeISO)
 	d.Memory = config.Memory
 	d.CPU = config.CPUs
@@ -418,18 +717,18 @@ func GetHostDockerEnv(api libmachine.API) (map[string]string, error) {
 	return envMap, nil
 }
 
-// GetHostLogs gets the localkube logs of the host VM.
-// If follow is specified, it will tail the logs
-func GetHostLogs(api libmachine.API, follow bool) (string, error) {
+// GetHostLogs gets the localkube logs of the host VM selected by opts.
+// If opts.Follow is set, it will tail the logs
+func GetHostLogs(api libmachine.API, opts LogsOptions) (string, error) {
 	h, err := CheckIfApiExistsAndLoad(api)
 	if err != nil {
 		return "", errors.Wrap(err, "Error checking that api exists and loading it")
 	}
-	logsCommand, err := GetLogsCommand(follow)
+	logsCommand, err := GetLogsCommand(opts)
 	if err != nil {
 		return "", errors.Wrap(err, "Error getting logs command")
 	}
-	if follow {
+	if opts.Follow {
 		c, err := h.CreateSSHClient()
 		if err != nil {
 			return "", errors.Wrap(err, "Error creating ssh client")
@@ -448,6 +747,33 @@ func GetHostLogs(api libmachine.API, follow bool) (string, error) {
 	return s, nil
 }
 
+// GetAuditLog returns the apiserver audit log, including rotated logs, which localkube serves
+// from the VM, or from the host with the none driver.
+func GetAuditLog(api libmachine.API) (string, error) {
+	h, err := CheckIfApiExistsAndLoad(api)
+	if err != nil {
+		return "", errors.Wrap(err, "Error checking that api exists and loading it")
+	}
+	out, err := RunCommand(h, auditLogCommand, false)
+	if err != nil {
+		return "", errors.Wrap(err, "Error getting audit log, auditing is only enabled when minikube start is run with --audit-log-path")
+	}
+	return out, nil
+}
+
+// GetProxyRules returns the iptables rules of the VM, in the iptables-save format.
+func GetProxyRules(api libmachine.API) (string, error) {
+	h, err := CheckIfApiExistsAndLoad(api)
+	if err != nil {
+		return "", errors.Wrap(err, "Error checking that api exists and loading it")
+	}
+	out, err := RunCommand(h, proxyRulesCommand, false)
+	if err != nil {
+		return "", errors.Wrap(err, "Error getting proxy rules")
+	}
+	return out, nil
+}
+
 // MountHost runs the mount command from the 9p client on the VM to the 9p server on the host
 func MountHost(api libmachine.API, ip net.IP, path, port, mountVersion string, uid, gid, msize int) error {
 	host, err := CheckIfApiExistsAndLoad(api)
@@ -520,17 +846,21 @@ func getIPForInterface(name string) (net.IP, error) {
 }
 
 func CheckIfApiExistsAndLoad(api libmachine.API) (*host.Host, error) {
-	exists, err := api.Exists(cfg.GetMachineName())
+	return loadHost(api, cfg.GetMachineName())
+}
+
+func loadHost(api libmachine.API, name string) (*host.Host, error) {
+	exists, err := api.Exists(name)
 	if err != nil {
-		return nil, errors.Wrapf(err, "Error checking that api exists for: %s", cfg.GetMachineName())
+		return nil, errors.Wrapf(err, "Error checking that api exists for: %s", name)
 	}
 	if !exists {
-		return nil, errors.Errorf("Machine does not exist for api.Exists(%s)", cfg.GetMachineName())
+		return nil, errors.Errorf("Machine does not exist for api.Exists(%s)", name)
 	}
 
-	host, err := api.Load(cfg.GetMachineName())
+	host, err := api.Load(name)
 	if err != nil {
-		return nil, errors.Wrapf(err, "Error loading api for: %s", cfg.GetMachineName())
+		return nil, errors.Wrapf(err, "Error loading api for: %s", name)
 	}
 	return host, nil
 }
@@ -540,14 +870,17 @@ func CreateSSHShell(api libmachine.API, args []string) error {
 	if err != nil {
 		return errors.Wrap(err, "Error checking if api exist and loading it")
 	}
+	return createSSHShell(host, args)
+}
 
+func createSSHShell(host *host.Host, args []string) error {
 	currentState, err := host.Driver.GetState()
 	if err != nil {
 		return errors.Wrap(err, "Error getting state of host")
 	}
 
 	if currentState != state.Running {
-		return errors.Errorf("Error: Cannot run ssh command: Host %q is not running", cfg.GetMachineName())
+		return errors.Errorf("Error: Cannot run ssh command: Host %q is not running", host.Name)
 	}
 
 	client, err := host.CreateSSHClient()
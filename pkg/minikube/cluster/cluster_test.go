@@ -20,12 +20,14 @@ import (
 	"bytes"
 	"io"
 	"io/ioutil"
+	"net"
 	"net/http"
 	"net/http/httptest"
 	"os"
 	"path/filepath"
 	"strings"
 	"testing"
+	"time"
 
 	"github.com/docker/machine/libmachine/drivers"
 	"github.com/docker/machine/libmachine/host"
@@ -35,6 +37,7 @@ import (
 	"k8s.io/minikube/pkg/minikube/config"
 	"k8s.io/minikube/pkg/minikube/constants"
 	"k8s.io/minikube/pkg/minikube/tests"
+	"k8s.io/minikube/pkg/util"
 )
 
 type MockDownloader struct{}
@@ -410,12 +413,223 @@ func TestGetLocalkubeStatus(t *testing.T) {
 		t.Fatalf("Error getting localkube status: %s", err)
 	}
 
+	s.SetCommandToOutput(map[string]string{
+		localkubeStatusCommand: constants.LocalkubePaused,
+	})
+	if ls, err := GetLocalkubeStatus(api); err != nil || ls != constants.LocalkubePaused {
+		t.Fatalf("Expected localkube to be paused, got: %s, %v", ls, err)
+	}
+
 	s.SetCommandToOutput(map[string]string{
 		localkubeStatusCommand: "Bad Output",
 	})
 	if _, err := GetLocalkubeStatus(api); err == nil {
 		t.Fatalf("Expected error in getting localkube status as ssh returned bad output")
 	}
+
+	s.SetCommandToOutput(map[string]string{
+		localkubeStatusCommand:          state.Running.String(),
+		localkubeComponentStatusCommand: `[{"name":"apiserver","ready":true},{"name":"kubelet","ready":false}]`,
+	})
+	if ls, err := GetLocalkubeStatus(api); err != nil || ls != constants.LocalkubeUnhealthy {
+		t.Fatalf("Expected localkube to be unhealthy, got: %s, %v", ls, err)
+	}
+}
+
+func TestPauseUnpauseCluster(t *testing.T) {
+	api := tests.NewMockAPI()
+	s, _ := tests.NewSSHServer()
+	port, err := s.Start()
+	if err != nil {
+		t.Fatalf("Error starting ssh server: %s", err)
+	}
+
+	d := &tests.MockDriver{
+		Port: port,
+		BaseDriver: drivers.BaseDriver{
+			IPAddress:  "127.0.0.1",
+			SSHKeyPath: "",
+		},
+	}
+	api.Hosts[config.GetMachineName()] = &host.Host{Driver: d}
+
+	if err := PauseCluster(api); err != nil {
+		t.Fatalf("Error pausing cluster: %s", err)
+	}
+	if _, ok := s.Commands[pauseCommand]; !ok {
+		t.Fatalf("Expected the pause command to run, got %v", s.Commands)
+	}
+	if err := UnpauseCluster(api); err != nil {
+		t.Fatalf("Error unpausing cluster: %s", err)
+	}
+	if _, ok := s.Commands[unpauseCommand]; !ok {
+		t.Fatalf("Expected the unpause command to run, got %v", s.Commands)
+	}
+}
+
+func TestGetLocalkubeComponentStatus(t *testing.T) {
+	api := tests.NewMockAPI()
+
+	s, _ := tests.NewSSHServer()
+	port, err := s.Start()
+	if err != nil {
+		t.Fatalf("Error starting ssh server: %s", err)
+	}
+
+	d := &tests.MockDriver{
+		Port: port,
+		BaseDriver: drivers.BaseDriver{
+			IPAddress:  "127.0.0.1",
+			SSHKeyPath: "",
+		},
+	}
+	api.Hosts[config.GetMachineName()] = &host.Host{Driver: d}
+
+	s.SetCommandToOutput(map[string]string{
+		localkubeComponentStatusCommand: `[{"name":"apiserver","ready":true,"restarts":2,"uptime":"1m0s"}]`,
+	})
+	components, err := GetLocalkubeComponentStatus(api)
+	if err != nil {
+		t.Fatalf("Error getting localkube component status: %s", err)
+	}
+	if len(components) != 1 || components[0].Name != "apiserver" || components[0].Restarts != 2 {
+		t.Fatalf("Unexpected component status: %+v", components)
+	}
+
+	s.SetCommandToOutput(map[string]string{
+		localkubeComponentStatusCommand: "Bad Output",
+	})
+	if _, err := GetLocalkubeComponentStatus(api); err == nil {
+		t.Fatalf("Expected error in getting component status as ssh returned bad output")
+	}
+}
+
+func TestGetEtcdSnapshot(t *testing.T) {
+	api := tests.NewMockAPI()
+
+	s, _ := tests.NewSSHServer()
+	port, err := s.Start()
+	if err != nil {
+		t.Fatalf("Error starting ssh server: %s", err)
+	}
+
+	d := &tests.MockDriver{
+		Port: port,
+		BaseDriver: drivers.BaseDriver{
+			IPAddress:  "127.0.0.1",
+			SSHKeyPath: "",
+		},
+	}
+	api.Hosts[config.GetMachineName()] = &host.Host{Driver: d}
+
+	snapshot := `{"time":"2017-06-01T10:00:00Z","v2":[{"key":"/registry/namespaces/default","value":"e30="}],"v3":[]}`
+	s.SetCommandToOutput(map[string]string{
+		etcdSnapshotCommand: snapshot,
+	})
+	out, err := GetEtcdSnapshot(api)
+	if err != nil {
+		t.Fatalf("Error getting etcd snapshot: %s", err)
+	}
+	if string(out) != snapshot {
+		t.Fatalf("Expected snapshot %s, got %s", snapshot, out)
+	}
+
+	s.SetCommandToOutput(map[string]string{
+		etcdSnapshotCommand: "Bad Output",
+	})
+	if _, err := GetEtcdSnapshot(api); err == nil {
+		t.Fatalf("Expected error in getting etcd snapshot as ssh returned bad output")
+	}
+}
+
+func TestGetAuditLog(t *testing.T) {
+	api := tests.NewMockAPI()
+	s, _ := tests.NewSSHServer()
+	port, err := s.Start()
+	if err != nil {
+		t.Fatalf("Error starting ssh server: %s", err)
+	}
+
+	d := &tests.MockDriver{
+		Port: port,
+		BaseDriver: drivers.BaseDriver{
+			IPAddress:  "127.0.0.1",
+			SSHKeyPath: "",
+		},
+	}
+	api.Hosts[config.GetMachineName()] = &host.Host{Driver: d}
+
+	auditLog := `2017-06-01T10:00:00Z AUDIT: id="1" stage="ResponseComplete" ip="127.0.0.1" method="list" user="minikube" groups="\"system:masters\"" as="<self>" asgroups="<lookup>" namespace="default" uri="/api/v1/namespaces/default/pods" response="200"`
+	s.SetCommandToOutput(map[string]string{
+		auditLogCommand: auditLog,
+	})
+	out, err := GetAuditLog(api)
+	if err != nil {
+		t.Fatalf("Error getting audit log: %s", err)
+	}
+	if out != auditLog {
+		t.Fatalf("Expected audit log %s, got %s", auditLog, out)
+	}
+}
+
+func TestGetProxyRules(t *testing.T) {
+	api := tests.NewMockAPI()
+	s, _ := tests.NewSSHServer()
+	port, err := s.Start()
+	if err != nil {
+		t.Fatalf("Error starting ssh server: %s", err)
+	}
+
+	d := &tests.MockDriver{
+		Port: port,
+		BaseDriver: drivers.BaseDriver{
+			IPAddress:  "127.0.0.1",
+			SSHKeyPath: "",
+		},
+	}
+	api.Hosts[config.GetMachineName()] = &host.Host{Driver: d}
+
+	rules := "*nat\n:KUBE-SERVICES - [0:0]\nCOMMIT\n"
+	s.SetCommandToOutput(map[string]string{
+		proxyRulesCommand: rules,
+	})
+	out, err := GetProxyRules(api)
+	if err != nil {
+		t.Fatalf("Error getting proxy rules: %s", err)
+	}
+	if out != rules {
+		t.Fatalf("Expected proxy rules %s, got %s", rules, out)
+	}
+}
+
+func TestGetLocalkubeMetrics(t *testing.T) {
+	api := tests.NewMockAPI()
+	s, _ := tests.NewSSHServer()
+	port, err := s.Start()
+	if err != nil {
+		t.Fatalf("Error starting ssh server: %s", err)
+	}
+
+	d := &tests.MockDriver{
+		Port: port,
+		BaseDriver: drivers.BaseDriver{
+			IPAddress:  "127.0.0.1",
+			SSHKeyPath: "",
+		},
+	}
+	api.Hosts[config.GetMachineName()] = &host.Host{Driver: d}
+
+	metrics := "go_goroutines{component=\"localkube\"} 1520\n"
+	s.SetCommandToOutput(map[string]string{
+		localkubeMetricsCommand: metrics,
+	})
+	out, err := GetLocalkubeMetrics(api)
+	if err != nil {
+		t.Fatalf("Error getting localkube metrics: %s", err)
+	}
+	if out != metrics {
+		t.Fatalf("Expected metrics %s, got %s", metrics, out)
+	}
 }
 
 func TestSetupCerts(t *testing.T) {
@@ -436,7 +650,7 @@ func TestSetupCerts(t *testing.T) {
 	tempDir := tests.MakeTempDir()
 	defer os.RemoveAll(tempDir)
 
-	if err := SetupCerts(d, constants.APIServerName, constants.ClusterDNSDomain); err != nil {
+	if err := SetupCerts(d, constants.APIServerName, constants.ClusterDNSDomain, util.DefaultServiceCIDR, nil); err != nil {
 		t.Fatalf("Error starting cluster: %s", err)
 	}
 
@@ -449,6 +663,106 @@ func TestSetupCerts(t *testing.T) {
 	}
 }
 
+func TestGenerateCertsExtraSANs(t *testing.T) {
+	tempDir := tests.MakeTempDir()
+	defer os.RemoveAll(tempDir)
+
+	caCert := filepath.Join(tempDir, "ca.crt")
+	caKey := filepath.Join(tempDir, "ca.key")
+	pub := filepath.Join(tempDir, "apiserver.crt")
+	priv := filepath.Join(tempDir, "apiserver.key")
+	ip := net.ParseIP("192.168.99.100")
+	internalIP := net.ParseIP("10.0.0.1")
+	extraSANs := []string{"localhost", "127.0.0.1", "minikube.example.com"}
+	if err := GenerateCerts(caCert, caKey, pub, priv, ip, internalIP, constants.APIServerName, constants.ClusterDNSDomain, extraSANs); err != nil {
+		t.Fatalf("Error generating certs: %s", err)
+	}
+	cert, err := util.LoadCert(pub)
+	if err != nil {
+		t.Fatalf("Error loading cert: %s", err)
+	}
+	if len(cert.IPAddresses) != 3 || !cert.IPAddresses[2].Equal(net.ParseIP("127.0.0.1")) {
+		t.Errorf("Expected the cert to be valid for the extra IP, got %v", cert.IPAddresses)
+	}
+	for _, name := range []string{"localhost", "minikube.example.com", "kubernetes.default"} {
+		if err := cert.VerifyHostname(name); err != nil {
+			t.Errorf("Expected the cert to be valid for %s: %s", name, err)
+		}
+	}
+
+	if err := GenerateCerts(caCert, caKey, pub, priv, ip, internalIP, constants.APIServerName, constants.ClusterDNSDomain, nil); err != nil {
+		t.Fatalf("Error generating certs: %s", err)
+	}
+	cert, err = util.LoadCert(pub)
+	if err != nil {
+		t.Fatalf("Error loading cert: %s", err)
+	}
+	if err := cert.VerifyHostname("minikube.example.com"); err == nil {
+		t.Errorf("Expected a removed extra SAN to be dropped from the cert")
+	}
+
+	if err := GenerateCerts(caCert, caKey, pub, priv, ip, internalIP, constants.APIServerName, constants.ClusterDNSDomain, []string{"not a name"}); err == nil {
+		t.Errorf("Expected an error for an invalid extra SAN")
+	}
+}
+
+func TestRotateCerts(t *testing.T) {
+	tempDir := tests.MakeTempDir()
+	defer os.RemoveAll(tempDir)
+
+	api := tests.NewMockAPI()
+	s, _ := tests.NewSSHServer()
+	port, err := s.Start()
+	if err != nil {
+		t.Fatalf("Error starting ssh server: %s", err)
+	}
+
+	d := &tests.MockDriver{
+		Port: port,
+		BaseDriver: drivers.BaseDriver{
+			IPAddress:  "127.0.0.1",
+			SSHKeyPath: "",
+		},
+	}
+	api.Hosts[config.GetMachineName()] = &host.Host{Driver: d}
+
+	if err := RotateCerts(api, false); err == nil {
+		t.Fatalf("Expected an error rotating certificates that don't exist")
+	}
+
+	if err := SetupCerts(d, constants.APIServerName, constants.ClusterDNSDomain, util.DefaultServiceCIDR, nil); err != nil {
+		t.Fatalf("Error setting up certs: %s", err)
+	}
+	apiserverCert := filepath.Join(constants.GetMinipath(), "apiserver.crt")
+	caCert := filepath.Join(constants.GetMinipath(), "ca.crt")
+	oldCert, _ := ioutil.ReadFile(apiserverCert)
+	oldCA, _ := ioutil.ReadFile(caCert)
+
+	if err := RotateCerts(api, false); err != nil {
+		t.Fatalf("Error rotating certificates: %s", err)
+	}
+	newCert, _ := ioutil.ReadFile(apiserverCert)
+	if bytes.Equal(oldCert, newCert) {
+		t.Errorf("Expected the apiserver certificate to be replaced")
+	}
+	if newCA, _ := ioutil.ReadFile(caCert); !bytes.Equal(oldCA, newCA) {
+		t.Errorf("Expected the CA certificate to be kept")
+	}
+	if _, ok := s.Commands[localkubeReloadCommand]; !ok {
+		t.Errorf("Expected command to run but did not: %s", localkubeReloadCommand)
+	}
+
+	if err := RotateCerts(api, true); err != nil {
+		t.Fatalf("Error rotating certificates: %s", err)
+	}
+	if newCA, _ := ioutil.ReadFile(caCert); bytes.Equal(oldCA, newCA) {
+		t.Errorf("Expected the CA certificate to be replaced")
+	}
+	if expiring, err := GetExpiringCerts(); err != nil || len(expiring) != 0 {
+		t.Errorf("Expected no expiring certificates, got %v: %v", expiring, err)
+	}
+}
+
 func TestGetHostDockerEnv(t *testing.T) {
 	tempDir := tests.MakeTempDir()
 	defer os.RemoveAll(tempDir)
@@ -530,25 +844,28 @@ func TestHostGetLogs(t *testing.T) {
 
 	tests := []struct {
 		description string
-		follow      bool
+		opts        LogsOptions
 	}{
 		{
 			description: "logs",
-			follow:      false,
 		},
 		{
 			description: "logs -f",
-			follow:      true,
+			opts:        LogsOptions{Follow: true},
+		},
+		{
+			description: "logs --component --since --grep -n",
+			opts:        LogsOptions{Components: []string{"apiserver"}, Since: time.Hour, Grep: "error", Length: 10},
 		},
 	}
 
 	for _, test := range tests {
 		t.Run(test.description, func(t *testing.T) {
-			cmd, err := GetLogsCommand(test.follow)
+			cmd, err := GetLogsCommand(test.opts)
 			if err != nil {
 				t.Errorf("Error getting the logs command: %s", err)
 			}
-			if _, err = GetHostLogs(api, test.follow); err != nil {
+			if _, err = GetHostLogs(api, test.opts); err != nil {
 				t.Errorf("Error getting host logs: %s", err)
 			}
 			if _, ok := s.Commands[cmd]; !ok {
@@ -769,3 +1086,44 @@ func TestUpdateCustomAddons(t *testing.T) {
 		t.Fatalf("Custom addon not copied. Expected transfers to contain custom addon with content: %s. It was: %s", testContent2, transferred)
 	}
 }
+
+func TestUpdateStagedEtcdRestore(t *testing.T) {
+	tempDir := tests.MakeTempDir()
+	defer os.RemoveAll(tempDir)
+
+	s, _ := tests.NewSSHServer()
+	port, err := s.Start()
+	if err != nil {
+		t.Fatalf("Error starting ssh server: %s", err)
+	}
+
+	d := &tests.MockDriver{
+		Port: port,
+		BaseDriver: drivers.BaseDriver{
+			IPAddress:  "127.0.0.1",
+			SSHKeyPath: "",
+		},
+	}
+
+	snapshot := filepath.Join(tempDir, "snapshot.json")
+	contents := []byte(`{"time":"2017-01-01T00:00:00Z"}`)
+	if err := ioutil.WriteFile(snapshot, contents, 0644); err != nil {
+		t.Fatalf("Error writing snapshot: %s", err)
+	}
+	if err := StageEtcdRestore(snapshot); err != nil {
+		t.Fatalf("Error staging etcd restore: %s", err)
+	}
+
+	kubernetesConfig := KubernetesConfig{
+		KubernetesVersion: constants.DefaultKubernetesVersion,
+	}
+	if err := UpdateCluster(d, kubernetesConfig); err != nil {
+		t.Fatalf("Error updating cluster: %s", err)
+	}
+	if transferred := s.Transfers.Bytes(); !bytes.Contains(transferred, contents) {
+		t.Fatalf("Staged snapshot not copied. Expected transfers to contain %s. It was: %s", contents, transferred)
+	}
+	if util.CanReadFile(getStagedEtcdRestorePath()) {
+		t.Fatalf("Staged snapshot was not removed after it was copied")
+	}
+}
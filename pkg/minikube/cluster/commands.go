@@ -21,11 +21,15 @@ import (
 	gflag "flag"
 	"fmt"
 	"net"
+	"path"
 	"strings"
+	"time"
 
 	"text/template"
 
 	"k8s.io/minikube/pkg/minikube/constants"
+	"k8s.io/minikube/pkg/minikube/storageclass"
+	"k8s.io/minikube/pkg/util"
 )
 
 // Kill any running instances.
@@ -54,7 +58,7 @@ ExecReload=/bin/kill -s HUP $MAINPID
 WantedBy=multi-user.target
 `
 
-var startCommandTemplate = "if [[ `systemctl` =~ -\\.mount ]] &>/dev/null;" + `then
+var startCommandTemplate = "{{.UnpauseCommand}}if [[ `systemctl` =~ -\\.mount ]] &>/dev/null;" + `then
   {{.StartCommandSystemd}}
   sudo systemctl daemon-reload
   sudo systemctl enable localkube.service
@@ -80,10 +84,13 @@ func GetStartCommand(kubernetesConfig KubernetesConfig) (string, error) {
 	}
 	t := template.Must(template.New("startCommand").Parse(startCommandTemplate))
 	buf := bytes.Buffer{}
+	// A paused cluster is resumed first, so that restarting localkube doesn't leave its pods frozen
 	data := struct {
+		UnpauseCommand        string
 		StartCommandNoSystemd string
 		StartCommandSystemd   string
 	}{
+		UnpauseCommand:        unpauseCommand,
 		StartCommandNoSystemd: startCommandNoSystemd,
 		StartCommandSystemd:   startCommandSystemd,
 	}
@@ -155,10 +162,69 @@ func GenLocalkubeStartCmd(kubernetesConfig KubernetesConfig) (string, error) {
 		flagVals = append(flagVals, "--apiserver-name="+kubernetesConfig.APIServerName)
 	}
 
+	if len(kubernetesConfig.APIServerExtraSANs) > 0 {
+		flagVals = append(flagVals, "--apiserver-extra-sans="+strings.Join(kubernetesConfig.APIServerExtraSANs, ","))
+	}
+
 	if kubernetesConfig.DNSDomain != "" {
 		flagVals = append(flagVals, "--dns-domain="+kubernetesConfig.DNSDomain)
 	}
 
+	// Older localkube versions don't know the flag, so it is only passed when needed
+	if kubernetesConfig.StorageBackend != "" && kubernetesConfig.StorageBackend != constants.DefaultStorageBackend {
+		flagVals = append(flagVals, "--storage-backend="+kubernetesConfig.StorageBackend)
+	}
+
+	if len(kubernetesConfig.EtcdServers) > 0 {
+		flagVals = append(flagVals, "--etcd-servers="+strings.Join(kubernetesConfig.EtcdServers, ","))
+	}
+	for _, f := range getEtcdTLSFiles(kubernetesConfig) {
+		flagVals = append(flagVals, fmt.Sprintf("--%s=%s", f.flag, path.Join(util.DefaultCertPath, f.name)))
+	}
+
+	if len(kubernetesConfig.AdmissionControl) > 0 {
+		flagVals = append(flagVals, "--admission-control="+strings.Join(kubernetesConfig.AdmissionControl, ","))
+	}
+
+	if kubernetesConfig.AuthorizationMode != "" && kubernetesConfig.AuthorizationMode != constants.DefaultAuthorizationMode {
+		flagVals = append(flagVals, "--authorization-mode="+kubernetesConfig.AuthorizationMode)
+	}
+	if kubernetesConfig.AuthorizationWebhookFile != "" {
+		flagVals = append(flagVals, "--authorization-webhook-config-file="+path.Join(util.DefaultLocalkubeDirectory, constants.AuthorizationWebhookFileName))
+	}
+
+	if kubernetesConfig.AuditLogPath != "" {
+		flagVals = append(flagVals, "--audit-log-path="+kubernetesConfig.AuditLogPath)
+	}
+
+	if dirs := storageclass.GetDirs(kubernetesConfig.StorageClasses); dirs != "" {
+		flagVals = append(flagVals, "--storage-class-dirs="+dirs)
+	}
+
+	if kubernetesConfig.PodCIDR != "" && kubernetesConfig.PodCIDR != util.DefaultPodCIDR {
+		flagVals = append(flagVals, "--pod-cidr="+kubernetesConfig.PodCIDR)
+	}
+	if kubernetesConfig.ServiceCIDR != "" && kubernetesConfig.ServiceCIDR != util.DefaultServiceCIDR {
+		flagVals = append(flagVals, "--service-cluster-ip-range="+kubernetesConfig.ServiceCIDR)
+	}
+	if kubernetesConfig.DNSIP != "" && kubernetesConfig.DNSIP != util.DefaultDNSIP {
+		flagVals = append(flagVals, "--dns-ip="+kubernetesConfig.DNSIP)
+	}
+
+	if kubernetesConfig.ProxyMode != "" && kubernetesConfig.ProxyMode != util.DefaultProxyMode {
+		flagVals = append(flagVals, "--proxy-mode="+kubernetesConfig.ProxyMode)
+	}
+	if kubernetesConfig.MasqueradeAll {
+		flagVals = append(flagVals, "--masquerade-all")
+	}
+	if kubernetesConfig.ClusterCIDR != "" {
+		flagVals = append(flagVals, "--cluster-cidr="+kubernetesConfig.ClusterCIDR)
+	}
+
+	if kubernetesConfig.JoinAPIServer != "" {
+		flagVals = append(flagVals, "--join="+kubernetesConfig.JoinAPIServer)
+	}
+
 	if kubernetesConfig.NodeIP != "127.0.0.1" {
 		flagVals = append(flagVals, "--node-ip="+kubernetesConfig.NodeIP)
 	}
@@ -190,13 +256,17 @@ else
 fi
 `
 
-func GetLogsCommand(follow bool) (string, error) {
+// GetLogsCommand returns a command printing the localkube logs selected by opts.
+func GetLogsCommand(opts LogsOptions) (string, error) {
+	if opts.filtered() {
+		return getFilteredLogsCommand(opts)
+	}
 	t, err := template.New("logsTemplate").Parse(logsTemplate)
 	if err != nil {
 		return "", err
 	}
 	var flags []string
-	if follow {
+	if opts.Follow {
 		flags = append(flags, "-f")
 	}
 
@@ -216,16 +286,94 @@ func GetLogsCommand(follow bool) (string, error) {
 	return buf.String(), nil
 }
 
-var localkubeStatusCommand = fmt.Sprintf("if [[ `systemctl` =~ -\\.mount ]] &>/dev/null; "+`then
-  sudo systemctl is-active localkube &>/dev/null && echo "Running" || echo "Stopped"
+// localkubePIDScript sets pid to the pid of localkube if it is running, and to "" otherwise.
+var localkubePIDScript = fmt.Sprintf("pid=\"\"\nif [[ `systemctl` =~ -\\.mount ]] &>/dev/null; "+`then
+  sudo systemctl is-active localkube &>/dev/null && pid=$(systemctl show -p MainPID localkube | cut -d= -f2)
+else
+  ps $(cat %s) &>/dev/null && pid=$(cat %s)
+fi
+`, constants.LocalkubePIDPath, constants.LocalkubePIDPath)
+
+// localkubeChildrenScript prints the pids of the child processes of localkube, which run its
+// components, given the pid of localkube in pid.
+const localkubeChildrenScript = `$(ps -eo pid,ppid | awk -v ppid=$pid '$2 == ppid {print $1}')`
+
+// localkubeStatusCommand prints Running, Paused or Stopped. A paused localkube is stopped with SIGSTOP.
+var localkubeStatusCommand = localkubePIDScript + `if [ -z "$pid" ]; then
+  echo "Stopped"
+elif grep -q "^State:[[:space:]]*T" /proc/$pid/status; then
+  echo "Paused"
 else
-  if ps $(cat %s) &>/dev/null; then
-    echo "Running"
-  else
-    echo "Stopped"
-  fi
+  echo "Running"
+fi
+`
+
+// pausedContainersPath lists the containers pauseCommand paused, for unpauseCommand to unpause.
+const pausedContainersPath = "/var/run/minikube-paused-containers"
+
+// pauseCommand stops localkube and the processes of its components with SIGSTOP, so that the
+// control plane and the kubelet keep their state, and then pauses the running containers of Kubernetes pods, so that probes don't fail
+// meanwhile. Other containers, such as those of the host with the none driver, are left running.
+// Only the containers it paused are listed, and the list of an earlier pause is dropped unless
+// localkube is still paused.
+var pauseCommand = localkubePIDScript + `if [ -z "$pid" ]; then
+  echo "localkube is not running" >&2
+  exit 1
+fi
+grep -q "^State:[[:space:]]*T" /proc/$pid/status || sudo rm -f ` + pausedContainersPath + `
+sudo kill -STOP $pid ` + localkubeChildrenScript + `
+if command -v docker &>/dev/null; then
+  for id in $(sudo docker ps -q --filter status=running --filter label=io.kubernetes.pod.name); do
+    sudo docker pause $id >/dev/null && echo $id | sudo tee -a ` + pausedContainersPath + ` >/dev/null
+  done
+fi
+`
+
+// unpauseCommand reverses pauseCommand, unpausing the containers it paused before resuming localkube.
+// Containers that were removed meanwhile are skipped.
+var unpauseCommand = `if [ -f ` + pausedContainersPath + ` ]; then
+  for id in $(cat ` + pausedContainersPath + `); do
+    sudo docker unpause $id &>/dev/null || true
+  done
+  sudo rm -f ` + pausedContainersPath + `
+fi
+` + localkubePIDScript + `if [ -n "$pid" ]; then
+  sudo kill -CONT $pid ` + localkubeChildrenScript + `
 fi
-`, constants.LocalkubePIDPath)
+`
+
+// localkubeStatusRequest returns a command requesting urlPath from the status endpoint of localkube,
+// at the address localkube recorded when it started serving, which is either host:port or a unix
+// socket. The default address is used if none was recorded. The request fails after timeout, so
+// that a localkube that doesn't respond, e.g. because it is paused, doesn't hang minikube.
+func localkubeStatusRequest(urlPath string, timeout time.Duration) string {
+	return fmt.Sprintf(`addr=$(cat %s 2>/dev/null || echo %s)
+case "$addr" in
+  %s*) curl -sf --max-time %d --unix-socket "${addr#%s}" http://localhost%s ;;
+  *) curl -sf --max-time %d "http://$addr%s" ;;
+esac`, path.Join(util.DefaultLocalkubeDirectory, util.LocalkubeStatusAddressFile), util.DefaultLocalkubeStatusAddress,
+		unixSocketPrefix, int(timeout/time.Second), unixSocketPrefix, urlPath, int(timeout/time.Second), urlPath)
+}
+
+// unixSocketPrefix prefixes the status addresses of localkube that are unix sockets.
+const unixSocketPrefix = "unix://"
+
+var localkubeComponentStatusCommand = localkubeStatusRequest(util.LocalkubeStatusPath, 10*time.Second)
+
+var localkubeMetricsCommand = localkubeStatusRequest(util.LocalkubeMetricsPath, 30*time.Second)
+
+// etcdSnapshotCommand is given longer, as localkube reads every key of etcd into the snapshot.
+var etcdSnapshotCommand = localkubeStatusRequest(util.LocalkubeEtcdSnapshotPath, 5*time.Minute)
+
+// auditLogCommand prints the apiserver audit log, including the rotated logs localkube keeps,
+// from wherever minikube start --audit-log-path put it.
+var auditLogCommand = localkubeStatusRequest(util.LocalkubeAuditPath, 5*time.Minute)
+
+// proxyRulesCommand prints the iptables rules of the VM, which include the rules of the proxy.
+var proxyRulesCommand = "sudo iptables-save"
+
+// localkubeReloadCommand makes localkube re-read its configuration, restarting if needed.
+var localkubeReloadCommand = "sudo killall -HUP localkube"
 
 func GetMountCleanupCommand(path string) string {
 	return fmt.Sprintf("sudo umount %s;", path)
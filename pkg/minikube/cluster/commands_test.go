@@ -19,9 +19,16 @@ package cluster
 import (
 	gflag "flag"
 	"fmt"
+	"io/ioutil"
+	"os"
+	"os/exec"
+	"path"
+	"path/filepath"
 	"strings"
 	"testing"
 
+	"k8s.io/minikube/pkg/minikube/constants"
+	"k8s.io/minikube/pkg/minikube/storageclass"
 	"k8s.io/minikube/pkg/util"
 )
 
@@ -63,6 +70,109 @@ func TestGetStartCommandExtraOptions(t *testing.T) {
 	}
 }
 
+func TestGetStartCommandStorageBackend(t *testing.T) {
+	tests := []struct {
+		storageBackend string
+		expectFlag     bool
+	}{
+		{"", false},
+		{constants.DefaultStorageBackend, false},
+		{"etcd3", true},
+	}
+	for _, test := range tests {
+		startCommand, err := GetStartCommand(KubernetesConfig{StorageBackend: test.storageBackend})
+		if err != nil {
+			t.Fatalf("Error generating start command: %s", err)
+		}
+		if hasFlag := strings.Contains(startCommand, "--storage-backend="+test.storageBackend); hasFlag != test.expectFlag {
+			t.Errorf("Expected --storage-backend to be passed for %q: %t. Got: %s", test.storageBackend, test.expectFlag, startCommand)
+		}
+	}
+}
+
+func TestGetStartCommandExternalEtcd(t *testing.T) {
+	startCommand, err := GetStartCommand(KubernetesConfig{
+		EtcdServers:  []string{"https://10.0.0.5:2379", "https://10.0.0.6:2379"},
+		EtcdCAFile:   "/home/user/etcd/ca.crt",
+		EtcdCertFile: "/home/user/etcd/client.crt",
+		EtcdKeyFile:  "/home/user/etcd/client.key",
+	})
+	if err != nil {
+		t.Fatalf("Error generating start command: %s", err)
+	}
+	for _, arg := range []string{
+		"--etcd-servers=https://10.0.0.5:2379,https://10.0.0.6:2379",
+		"--etcd-cafile=/var/lib/localkube/certs/etcd-ca.crt",
+		"--etcd-certfile=/var/lib/localkube/certs/etcd-client.crt",
+		"--etcd-keyfile=/var/lib/localkube/certs/etcd-client.key",
+	} {
+		if !strings.Contains(startCommand, arg) {
+			t.Errorf("Error, expected to find argument: %s. Got: %s", arg, startCommand)
+		}
+	}
+
+	startCommand, err = GetStartCommand(KubernetesConfig{})
+	if err != nil {
+		t.Fatalf("Error generating start command: %s", err)
+	}
+	if strings.Contains(startCommand, "--etcd-") {
+		t.Errorf("Expected no external etcd flags. Got: %s", startCommand)
+	}
+}
+
+func TestGetStartCommandAuthorization(t *testing.T) {
+	startCommand, err := GetStartCommand(KubernetesConfig{
+		AdmissionControl:         []string{"NamespaceLifecycle", "ServiceAccount", "NodeRestriction"},
+		AuthorizationMode:        "Node,RBAC,Webhook",
+		AuthorizationWebhookFile: "/home/user/webhook.kubeconfig",
+	})
+	if err != nil {
+		t.Fatalf("Error generating start command: %s", err)
+	}
+	for _, arg := range []string{
+		"--admission-control=NamespaceLifecycle,ServiceAccount,NodeRestriction",
+		"--authorization-mode=Node,RBAC,Webhook",
+		"--authorization-webhook-config-file=/var/lib/localkube/authorization-webhook.kubeconfig",
+	} {
+		if !strings.Contains(startCommand, arg) {
+			t.Errorf("Error, expected to find argument: %s. Got: %s", arg, startCommand)
+		}
+	}
+
+	startCommand, err = GetStartCommand(KubernetesConfig{AuthorizationMode: constants.DefaultAuthorizationMode})
+	if err != nil {
+		t.Fatalf("Error generating start command: %s", err)
+	}
+	if strings.Contains(startCommand, "--admission-control") || strings.Contains(startCommand, "--authorization-") {
+		t.Errorf("Expected no admission or authorization flags. Got: %s", startCommand)
+	}
+}
+
+func TestGetStartCommandStorageClasses(t *testing.T) {
+	startCommand, err := GetStartCommand(KubernetesConfig{
+		StorageClasses: []storageclass.StorageClassConfig{
+			{Name: "slow", Dir: "/data/slow"},
+			{Name: "fast", Dir: "/data/fast"},
+			{Name: "standard-retain"},
+		},
+	})
+	if err != nil {
+		t.Fatalf("Error generating start command: %s", err)
+	}
+	arg := "--storage-class-dirs=fast=/data/fast,slow=/data/slow "
+	if !strings.Contains(startCommand, arg) {
+		t.Errorf("Error, expected to find argument: %s. Got: %s", arg, startCommand)
+	}
+
+	startCommand, err = GetStartCommand(KubernetesConfig{StorageClasses: []storageclass.StorageClassConfig{{Name: "standard-retain"}}})
+	if err != nil {
+		t.Fatalf("Error generating start command: %s", err)
+	}
+	if strings.Contains(startCommand, "--storage-class-dirs") {
+		t.Errorf("Expected no storage class dirs flag. Got: %s", startCommand)
+	}
+}
+
 func flagMapToSetFlags(flagMap map[string]string) {
 	for flag, val := range flagMap {
 		gflag.Set(flag, val)
@@ -71,3 +181,172 @@ func flagMapToSetFlags(flagMap map[string]string) {
 func getSingleFlagValue(flag, val string) string {
 	return fmt.Sprintf("--%s %s", flag, val)
 }
+
+func TestGetStartCommandNetworkRanges(t *testing.T) {
+	startCommand, err := GetStartCommand(KubernetesConfig{
+		PodCIDR:     "172.30.0.0/16",
+		ServiceCIDR: "172.31.0.0/24",
+		DNSIP:       "172.31.0.53",
+	})
+	if err != nil {
+		t.Fatalf("Error generating start command: %s", err)
+	}
+	for _, arg := range []string{"--pod-cidr=172.30.0.0/16", "--service-cluster-ip-range=172.31.0.0/24", "--dns-ip=172.31.0.53"} {
+		if !strings.Contains(startCommand, arg) {
+			t.Errorf("Error, expected to find argument: %s. Got: %s", arg, startCommand)
+		}
+	}
+
+	startCommand, err = GetStartCommand(KubernetesConfig{
+		PodCIDR:     util.DefaultPodCIDR,
+		ServiceCIDR: util.DefaultServiceCIDR,
+		DNSIP:       util.DefaultDNSIP,
+	})
+	if err != nil {
+		t.Fatalf("Error generating start command: %s", err)
+	}
+	for _, flag := range []string{"--pod-cidr", "--service-cluster-ip-range", "--dns-ip"} {
+		if strings.Contains(startCommand, flag) {
+			t.Errorf("Expected no %s flag for the default. Got: %s", flag, startCommand)
+		}
+	}
+}
+
+func TestGetStartCommandAuditLogPath(t *testing.T) {
+	startCommand, err := GetStartCommand(KubernetesConfig{})
+	if err != nil {
+		t.Fatalf("Error generating start command: %s", err)
+	}
+	if strings.Contains(startCommand, "--audit-log-path") {
+		t.Errorf("Expected auditing to be off by default. Got: %s", startCommand)
+	}
+
+	startCommand, err = GetStartCommand(KubernetesConfig{AuditLogPath: "/var/lib/localkube/audit/audit.log"})
+	if err != nil {
+		t.Fatalf("Error generating start command: %s", err)
+	}
+	if arg := "--audit-log-path=/var/lib/localkube/audit/audit.log"; !strings.Contains(startCommand, arg) {
+		t.Errorf("Error, expected to find argument: %s. Got: %s", arg, startCommand)
+	}
+}
+
+func TestGetStartCommandProxy(t *testing.T) {
+	startCommand, err := GetStartCommand(KubernetesConfig{
+		ProxyMode:     "userspace",
+		MasqueradeAll: true,
+		ClusterCIDR:   "10.180.0.0/16",
+	})
+	if err != nil {
+		t.Fatalf("Error generating start command: %s", err)
+	}
+	for _, arg := range []string{"--proxy-mode=userspace", "--masquerade-all", "--cluster-cidr=10.180.0.0/16"} {
+		if !strings.Contains(startCommand, arg) {
+			t.Errorf("Error, expected to find argument: %s. Got: %s", arg, startCommand)
+		}
+	}
+
+	startCommand, err = GetStartCommand(KubernetesConfig{ProxyMode: util.DefaultProxyMode})
+	if err != nil {
+		t.Fatalf("Error generating start command: %s", err)
+	}
+	for _, flag := range []string{"--proxy-mode", "--masquerade-all", "--cluster-cidr"} {
+		if strings.Contains(startCommand, flag) {
+			t.Errorf("Expected no %s flag. Got: %s", flag, startCommand)
+		}
+	}
+}
+
+func TestGetStartCommandExtraSANs(t *testing.T) {
+	startCommand, err := GetStartCommand(KubernetesConfig{
+		APIServerExtraSANs: []string{"localhost", "127.0.0.1"},
+	})
+	if err != nil {
+		t.Fatalf("Error generating start command: %s", err)
+	}
+	if arg := "--apiserver-extra-sans=localhost,127.0.0.1"; !strings.Contains(startCommand, arg) {
+		t.Errorf("Error, expected to find argument: %s. Got: %s", arg, startCommand)
+	}
+}
+
+func TestGetStartCommandWorker(t *testing.T) {
+	startCommand, err := GetStartCommand(KubernetesConfig{
+		NodeIP:        "192.168.99.101",
+		JoinAPIServer: "https://192.168.99.100:8443",
+	})
+	if err != nil {
+		t.Fatalf("Error generating start command: %s", err)
+	}
+	for _, arg := range []string{"--join=https://192.168.99.100:8443", "--node-ip=192.168.99.101"} {
+		if !strings.Contains(startCommand, arg) {
+			t.Errorf("Error, expected to find argument: %s. Got: %s", arg, startCommand)
+		}
+	}
+}
+
+func TestPauseCommands(t *testing.T) {
+	if _, err := exec.LookPath("bash"); err != nil {
+		t.Skip("bash is not available")
+	}
+	startCommand, err := GetStartCommand(KubernetesConfig{})
+	if err != nil {
+		t.Fatalf("Error generating start command: %s", err)
+	}
+	if !strings.HasPrefix(startCommand, unpauseCommand) {
+		t.Errorf("Expected the start command to unpause the cluster first, got: %s", startCommand)
+	}
+	if !strings.Contains(pauseCommand, "--filter label=io.kubernetes.pod.name") {
+		t.Errorf("Expected only the containers of pods to be paused, got: %s", pauseCommand)
+	}
+	for name, command := range map[string]string{
+		"status":  localkubeStatusCommand,
+		"pause":   pauseCommand,
+		"unpause": unpauseCommand,
+		"start":   startCommand,
+	} {
+		if out, err := exec.Command("bash", "-n", "-c", command).CombinedOutput(); err != nil {
+			t.Errorf("Invalid %s command: %s\n%s", name, out, command)
+		}
+	}
+}
+
+func TestLocalkubeStatusRequest(t *testing.T) {
+	if _, err := exec.LookPath("bash"); err != nil {
+		t.Skip("bash is not available")
+	}
+	dir, err := ioutil.TempDir("", "status")
+	if err != nil {
+		t.Fatalf("Error creating temp dir: %s", err)
+	}
+	defer os.RemoveAll(dir)
+	// A fake curl prints the arguments it is called with.
+	if err := ioutil.WriteFile(filepath.Join(dir, "curl"), []byte("#!/bin/sh\necho \"$@\"\n"), 0755); err != nil {
+		t.Fatalf("Error writing fake curl: %s", err)
+	}
+	addressFile := filepath.Join(dir, util.LocalkubeStatusAddressFile)
+
+	for _, test := range []struct {
+		address  string
+		expected string
+	}{
+		{"", "-sf --max-time 10 http://127.0.0.1:10260/status"},
+		{"0.0.0.0:10270", "-sf --max-time 10 http://0.0.0.0:10270/status"},
+		{"unix:///var/run/localkube.sock", "-sf --max-time 10 --unix-socket /var/run/localkube.sock http://localhost/status"},
+	} {
+		os.Remove(addressFile)
+		if test.address != "" {
+			if err := ioutil.WriteFile(addressFile, []byte(test.address), 0644); err != nil {
+				t.Fatalf("Error writing address: %s", err)
+			}
+		}
+		command := strings.Replace(localkubeComponentStatusCommand, path.Join(util.DefaultLocalkubeDirectory, util.LocalkubeStatusAddressFile), addressFile, 1)
+		cmd := exec.Command("bash", "-c", command)
+		cmd.Env = append(os.Environ(), "PATH="+dir+":"+os.Getenv("PATH"))
+		out, err := cmd.CombinedOutput()
+		if err != nil {
+			t.Fatalf("Error running status request: %s\n%s", err, out)
+		}
+		if strings.TrimSpace(string(out)) != test.expected {
+			t.Errorf("Expected curl %s for address %q, got: %s", test.expected, test.address, out)
+		}
+	}
+}
@@ -18,26 +18,53 @@ package cluster
 
 import (
 	"net"
+	"path/filepath"
+	"time"
 
 	"github.com/pkg/errors"
+	"k8s.io/minikube/pkg/minikube/constants"
 	"k8s.io/minikube/pkg/util"
 )
 
-var (
-	// This is the internalIP , the API server and other components communicate on.
-	internalIP = net.ParseIP(util.DefaultServiceClusterIP)
-)
-
-func GenerateCerts(caCert, caKey, pub, priv string, ip net.IP, name string, dnsDomain string) error {
+// GenerateCerts generates the apiserver certificate for ip, internalIP, the IP of the
+// kubernetes service which the API server and other components communicate on, and the
+// hostnames and IPs in extraSANs.
+func GenerateCerts(caCert, caKey, pub, priv string, ip, internalIP net.IP, name string, dnsDomain string, extraSANs []string) error {
+	extraIPs, extraNames, err := util.ParseSANs(extraSANs)
+	if err != nil {
+		return errors.Wrap(err, "Error parsing apiserver extra SANs")
+	}
 	if !(util.CanReadFile(caCert) && util.CanReadFile(caKey)) {
 		if err := util.GenerateCACert(caCert, caKey, name); err != nil {
 			return errors.Wrap(err, "Error generating certificate")
 		}
 	}
 
-	ips := []net.IP{ip, internalIP}
-	if err := util.GenerateSignedCert(pub, priv, ips, util.GetAlternateDNS(dnsDomain), caCert, caKey); err != nil {
+	ips := append([]net.IP{ip, internalIP}, extraIPs...)
+	dnsNames := append(util.GetAlternateDNS(dnsDomain), extraNames...)
+	if err := util.GenerateSignedCert(pub, priv, ips, dnsNames, caCert, caKey); err != nil {
 		return errors.Wrap(err, "Error generating signed cert")
 	}
 	return nil
 }
+
+// GetExpiringCerts returns the expiry of the CA and apiserver certificates in the minikube
+// directory that expire within util.CertExpiryWarningPeriod, keyed by their path. The apiserver
+// certificate is also the client certificate of the kubeconfig. Missing certificates are skipped.
+func GetExpiringCerts() (map[string]time.Time, error) {
+	expiring := map[string]time.Time{}
+	for _, name := range []string{"ca.crt", "apiserver.crt"} {
+		p := filepath.Join(constants.GetMinipath(), name)
+		if !util.CanReadFile(p) {
+			continue
+		}
+		cert, err := util.LoadCert(p)
+		if err != nil {
+			return nil, errors.Wrapf(err, "Error loading certificate %s", p)
+		}
+		if util.CertExpiresSoon(cert) {
+			expiring[p] = cert.NotAfter
+		}
+	}
+	return expiring, nil
+}
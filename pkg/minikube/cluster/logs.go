@@ -0,0 +1,168 @@
+/*
+Copyright 2016 The Kubernetes Authors All rights reserved.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package cluster
+
+import (
+	"bytes"
+	"fmt"
+	"strings"
+	"text/template"
+	"time"
+
+	"k8s.io/minikube/pkg/minikube/constants"
+	"k8s.io/minikube/pkg/util"
+)
+
+// LogsOptions selects the lines of the localkube logs GetLogsCommand prints.
+type LogsOptions struct {
+	Follow bool
+	// Components limits the lines to those logged by these components, see util.LogComponents.
+	Components []string
+	// Since limits the lines to those logged in the last Since.
+	Since time.Duration
+	// Grep limits the lines to those matching this extended regular expression.
+	Grep string
+	// Length limits the lines to the last Length matching ones. Zero prints all of them.
+	Length int
+}
+
+func (o LogsOptions) filtered() bool {
+	return len(o.Components) > 0 || o.Since > 0 || o.Grep != "" || o.Length > 0
+}
+
+// logsFilter is an awk program printing the lines of the localkube logs tagged with a component
+// in LOGS_COMPONENTS, logged between LOGS_SINCE and LOGS_NOW, that match LOGS_GREP.
+//
+// Localkube tags each line it writes with the server that logged it, e.g. "[kubelet] I1016 ...".
+// Lines written by an older localkube have no tag and are taken to be localkube's own. The journald
+// prefix and the tag are skipped over when reading the time of a line and when matching LOGS_GREP.
+//
+// LOGS_SINCE and LOGS_NOW are in the month, day and time format of the glog headers, e.g.
+// "1016 12:34:56", as the logs don't record the year. When LOGS_SINCE is after LOGS_NOW the range
+// wraps around the new year. Lines without a time, like the continuation lines of multi-line
+// messages, are printed along with the line before them.
+const logsFilter = `
+function timestamp(msg) {
+	if (msg ~ /^[IWEF][0-9][0-9][0-9][0-9] [0-9][0-9]:[0-9][0-9]:[0-9][0-9]/) return substr(msg, 2, 13)
+	if (msg ~ /^[0-9][0-9][0-9][0-9]-[0-9][0-9]-[0-9][0-9] [0-9][0-9]:[0-9][0-9]:[0-9][0-9]/) return substr(msg, 6, 2) substr(msg, 9, 2) " " substr(msg, 12, 8)
+	return ""
+}
+BEGIN {
+	n = split(ENVIRON["LOGS_COMPONENTS"], c, ",")
+	for (i = 1; i <= n; i++) want[c[i]] = 1
+	grep = ENVIRON["LOGS_GREP"]
+	since = ENVIRON["LOGS_SINCE"]
+	now = ENVIRON["LOGS_NOW"]
+	recent = since == ""
+}
+{
+	msg = $0
+	sub(/^[A-Z][a-z][a-z] [ 0-9][0-9] [0-9:]+ [^ ]+ localkube\[[0-9]+\]: /, "", msg)
+	comp = "localkube"
+	if (msg ~ /^\[[a-z-]+\] /) {
+		comp = substr(msg, 2, index(msg, "]") - 2)
+		msg = substr(msg, index(msg, "]") + 2)
+	}
+	t = timestamp(msg)
+	if (since != "" && t != "") {
+		if (now == "") recent = t >= since
+		else if (since <= now) recent = t >= since && t <= now
+		else recent = t >= since || t <= now
+	}
+	if ((n == 0 || comp in want) && recent && (grep == "" || msg ~ grep)) {
+		print
+		fflush()
+	}
+}
+`
+
+const filteredLogsTemplate = "if [[ `systemctl` =~ -\\.mount ]] &>/dev/null; " + `then
+  sudo journalctl -u localkube{{if .Since}} --since=-{{.Since}}s{{end}} | {{.Filter}}{{.Tail}}
+{{- if .Follow}}
+  sudo journalctl -u localkube -f -n 0 | {{.Filter}}
+{{- end}}
+else
+{{- if .Since}}
+  now=$(date +%s)
+  since="$(date -d @$(( now - {{.Since}} )) '+%m%d %H:%M:%S')"
+  now="$(date -d @$now '+%m%d %H:%M:%S')"
+{{- end}}
+  tail -q -n +1 {{.RemoteLocalkubeErrPath}} {{.RemoteLocalkubeOutPath}} | LOGS_SINCE="$since" LOGS_NOW="$now" {{.Filter}}{{.Tail}}
+{{- if .Follow}}
+  tail -q -n 0 -f {{.RemoteLocalkubeErrPath}} {{.RemoteLocalkubeOutPath}} | {{.Filter}}
+{{- end}}
+fi
+`
+
+// getFilteredLogsCommand returns a command printing the lines of the localkube logs selected by
+// opts. When following, the selected lines logged so far are printed before the new ones.
+func getFilteredLogsCommand(opts LogsOptions) (string, error) {
+	for _, c := range opts.Components {
+		if !IsLogComponent(c) {
+			return "", fmt.Errorf("unknown component %q, expected one of %s", c, strings.Join(util.LogComponents, ", "))
+		}
+	}
+
+	filter := getLogsFilter(opts)
+	tail := ""
+	if opts.Length > 0 {
+		tail = fmt.Sprintf(" | tail -n %d", opts.Length)
+	}
+
+	buf := bytes.Buffer{}
+	t := template.Must(template.New("filteredLogs").Parse(filteredLogsTemplate))
+	if err := t.Execute(&buf, struct {
+		RemoteLocalkubeErrPath string
+		RemoteLocalkubeOutPath string
+		Follow                 bool
+		Since                  int64
+		Filter                 string
+		Tail                   string
+	}{
+		RemoteLocalkubeErrPath: constants.RemoteLocalKubeErrPath,
+		RemoteLocalkubeOutPath: constants.RemoteLocalKubeOutPath,
+		Follow:                 opts.Follow,
+		Since:                  int64((opts.Since + time.Second - 1) / time.Second),
+		Filter:                 filter,
+		Tail:                   tail,
+	}); err != nil {
+		return "", err
+	}
+	return buf.String(), nil
+}
+
+// getLogsFilter returns a command filtering the localkube logs on its input by the components and
+// regular expression of opts, and by the times in LOGS_SINCE and LOGS_NOW.
+func getLogsFilter(opts LogsOptions) string {
+	return fmt.Sprintf("LOGS_COMPONENTS=%s LOGS_GREP=%s awk '%s'",
+		shellQuote(strings.Join(opts.Components, ",")), shellQuote(opts.Grep), logsFilter)
+}
+
+// IsLogComponent returns whether name is one of util.LogComponents.
+func IsLogComponent(name string) bool {
+	for _, c := range util.LogComponents {
+		if c == name {
+			return true
+		}
+	}
+	return false
+}
+
+// shellQuote quotes s as a single word for the shell.
+func shellQuote(s string) string {
+	return "'" + strings.Replace(s, "'", `'\''`, -1) + "'"
+}
@@ -0,0 +1,184 @@
+/*
+Copyright 2016 The Kubernetes Authors All rights reserved.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package cluster
+
+import (
+	"os"
+	"os/exec"
+	"strings"
+	"testing"
+	"time"
+)
+
+const testLocalkubeLogs = `[localkube] Starting etcd...
+[etcd] 2017-10-16 12:00:01.000000 I | etcdserver: name = kubeetcd
+[localkube] Starting apiserver...
+[apiserver] I1016 12:00:02.000000    1234 serve.go:85] Serving securely on 0.0.0.0:8443
+[apiserver] apiserver: Error starting: bind: address already in use
+[kubelet] I1016 12:00:03.000000    1234 kubelet.go:1797] Starting kubelet main sync loop.
+[proxy] E1016 12:00:04.000000    1234 proxier.go:1032] Failed to execute iptables-restore: exit status 1
+[proxy]   iptables-restore: line 7 failed
+[controller-manager] I1016 12:00:05.000000    1234 replica_set.go:155] Starting ReplicaSet controller
+[localkube] W1016 12:00:06.000000    1234 reflector.go:323] watch of *v1.Pod ended with: too old resource version
+Oct 16 12:00:07 minikube localkube[1234]: [kubelet] I1016 12:00:07.000000    1234 kubelet_node_status.go:77] Attempting to register node minikube
+I1016 12:00:08.000000    1234 kubelet.go:1813] Skipping pod synchronization
+`
+
+// testNewYearLogs are logged around the new year, along with a line logged later in the day a year before.
+const testNewYearLogs = `[kubelet] I1231 23:59:30.000000    1234 kubelet.go:1797] Starting kubelet main sync loop.
+[kubelet] I0101 00:00:30.000000    1234 kubelet_node_status.go:77] Attempting to register node minikube
+[kubelet] I0101 12:00:00.000000    1234 kubelet.go:1813] Skipping pod synchronization
+`
+
+func TestGetLogsFilter(t *testing.T) {
+	if _, err := exec.LookPath("awk"); err != nil {
+		t.Skip("awk is not available")
+	}
+
+	tests := []struct {
+		description string
+		opts        LogsOptions
+		logs        string
+		since       string
+		now         string
+		expected    []string
+	}{
+		{
+			description: "components",
+			opts:        LogsOptions{Components: []string{"apiserver", "etcd"}},
+			expected: []string{
+				"[etcd] 2017-10-16 12:00:01.000000 I | etcdserver: name = kubeetcd",
+				"[apiserver] I1016 12:00:02.000000    1234 serve.go:85] Serving securely on 0.0.0.0:8443",
+				"[apiserver] apiserver: Error starting: bind: address already in use",
+			},
+		},
+		{
+			description: "continuation lines",
+			opts:        LogsOptions{Components: []string{"proxy"}},
+			expected: []string{
+				"[proxy] E1016 12:00:04.000000    1234 proxier.go:1032] Failed to execute iptables-restore: exit status 1",
+				"[proxy]   iptables-restore: line 7 failed",
+			},
+		},
+		{
+			description: "journald",
+			opts:        LogsOptions{Components: []string{"kubelet"}},
+			expected: []string{
+				"[kubelet] I1016 12:00:03.000000    1234 kubelet.go:1797] Starting kubelet main sync loop.",
+				"Oct 16 12:00:07 minikube localkube[1234]: [kubelet] I1016 12:00:07.000000    1234 kubelet_node_status.go:77] Attempting to register node minikube",
+			},
+		},
+		{
+			description: "untagged",
+			opts:        LogsOptions{Components: []string{"localkube"}},
+			expected: []string{
+				"[localkube] Starting etcd...",
+				"[localkube] Starting apiserver...",
+				"[localkube] W1016 12:00:06.000000    1234 reflector.go:323] watch of *v1.Pod ended with: too old resource version",
+				"I1016 12:00:08.000000    1234 kubelet.go:1813] Skipping pod synchronization",
+			},
+		},
+		{
+			description: "grep",
+			opts:        LogsOptions{Grep: `^[EI][0-9]+ .*(iptables|register)`},
+			expected: []string{
+				"[proxy] E1016 12:00:04.000000    1234 proxier.go:1032] Failed to execute iptables-restore: exit status 1",
+				"Oct 16 12:00:07 minikube localkube[1234]: [kubelet] I1016 12:00:07.000000    1234 kubelet_node_status.go:77] Attempting to register node minikube",
+			},
+		},
+		{
+			description: "since",
+			opts:        LogsOptions{Since: time.Minute, Grep: "Starting"},
+			since:       "1016 12:00:03",
+			now:         "1016 12:01:03",
+			expected: []string{
+				"[kubelet] I1016 12:00:03.000000    1234 kubelet.go:1797] Starting kubelet main sync loop.",
+				"[controller-manager] I1016 12:00:05.000000    1234 replica_set.go:155] Starting ReplicaSet controller",
+			},
+		},
+		{
+			description: "since the last year",
+			opts:        LogsOptions{Since: 10 * time.Minute},
+			logs:        testNewYearLogs,
+			since:       "1231 23:55:00",
+			now:         "0101 00:05:00",
+			expected: []string{
+				"[kubelet] I1231 23:59:30.000000    1234 kubelet.go:1797] Starting kubelet main sync loop.",
+				"[kubelet] I0101 00:00:30.000000    1234 kubelet_node_status.go:77] Attempting to register node minikube",
+			},
+		},
+		{
+			description: "since the new year",
+			opts:        LogsOptions{Since: time.Minute},
+			logs:        testNewYearLogs,
+			since:       "0101 00:00:00",
+			now:         "0101 00:01:00",
+			expected: []string{
+				"[kubelet] I0101 00:00:30.000000    1234 kubelet_node_status.go:77] Attempting to register node minikube",
+			},
+		},
+	}
+
+	for _, test := range tests {
+		t.Run(test.description, func(t *testing.T) {
+			logs := test.logs
+			if logs == "" {
+				logs = testLocalkubeLogs
+			}
+			cmd := exec.Command("sh", "-c", getLogsFilter(test.opts))
+			cmd.Env = append(os.Environ(), "LOGS_SINCE="+test.since, "LOGS_NOW="+test.now)
+			cmd.Stdin = strings.NewReader(logs)
+			out, err := cmd.Output()
+			if err != nil {
+				t.Fatalf("Error running the logs filter: %s", err)
+			}
+			lines := strings.Split(strings.TrimSuffix(string(out), "\n"), "\n")
+			if strings.Join(lines, "\n") != strings.Join(test.expected, "\n") {
+				t.Errorf("Expected lines:\n%s\ngot:\n%s", strings.Join(test.expected, "\n"), out)
+			}
+		})
+	}
+}
+
+func TestGetFilteredLogsCommand(t *testing.T) {
+	cmd, err := GetLogsCommand(LogsOptions{Follow: true, Components: []string{"kubelet"}, Since: 90 * time.Second, Length: 20})
+	if err != nil {
+		t.Fatalf("Error getting the logs command: %s", err)
+	}
+	for _, expected := range []string{
+		"sudo journalctl -u localkube --since=-90s | LOGS_COMPONENTS='kubelet' LOGS_GREP='' awk",
+		"| tail -n 20\n",
+		"sudo journalctl -u localkube -f -n 0 | LOGS_COMPONENTS='kubelet'",
+		"$(( now - 90 ))",
+		`LOGS_SINCE="$since" LOGS_NOW="$now"`,
+		"tail -q -n 0 -f",
+	} {
+		if !strings.Contains(cmd, expected) {
+			t.Errorf("Expected the logs command to contain %q, got:\n%s", expected, cmd)
+		}
+	}
+
+	if _, err := GetLogsCommand(LogsOptions{Components: []string{"dns"}}); err == nil {
+		t.Errorf("Expected an error for an unknown component")
+	}
+}
+
+func TestShellQuote(t *testing.T) {
+	if q := shellQuote("it's"); q != `'it'\''s'` {
+		t.Errorf("Unexpected quoting %s", q)
+	}
+}
@@ -0,0 +1,364 @@
+/*
+Copyright 2016 The Kubernetes Authors All rights reserved.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package cluster
+
+import (
+	"encoding/json"
+	"fmt"
+	"io/ioutil"
+	"net"
+	"os"
+	"path/filepath"
+	"regexp"
+	"strconv"
+
+	"github.com/docker/machine/libmachine"
+	"github.com/docker/machine/libmachine/host"
+	"github.com/golang/glog"
+	"github.com/pkg/errors"
+
+	"k8s.io/minikube/pkg/minikube/assets"
+	cfg "k8s.io/minikube/pkg/minikube/config"
+	"k8s.io/minikube/pkg/minikube/constants"
+	"k8s.io/minikube/pkg/util"
+)
+
+// Node is a worker machine added to the cluster of a profile. It runs localkube with only the
+// kubelet and proxy, joining the apiserver of the profile's machine.
+type Node struct {
+	Name string `json:"name"`
+	// PodCIDR is the subnet of the cluster CIDR the node assigns the IPs of its pods from.
+	PodCIDR string `json:"podCIDR,omitempty"`
+}
+
+// nodeNameRegexp matches the names of nodes, which are part of their machine and host names.
+var nodeNameRegexp = regexp.MustCompile(`^[a-z0-9]([-a-z0-9]*[a-z0-9])?$`)
+
+// workerComponents are the localkube components running on a node.
+var workerComponents = []string{"kubelet", "proxy"}
+
+// nodeNetworkPlugin is the network plugin of the cluster's machine and its nodes when no other one
+// is set. It bridges the pods of each machine on the pod CIDR of the machine, which
+// routePodCIDRs routes to from the other machines.
+const nodeNetworkPlugin = "kubenet"
+
+// defaultClusterCIDRPrefix is the prefix length of the cluster CIDR around the pod CIDR of the
+// cluster's machine, when --cluster-cidr isn't set.
+const defaultClusterCIDRPrefix = 16
+
+// GetNodeMachineName returns the name of the machine of a node, which is also its name in
+// Kubernetes. It is prefixed with the profile so that the nodes of different profiles don't clash.
+func GetNodeMachineName(name string) string {
+	return cfg.GetMachineName() + "-" + name
+}
+
+// getNodeDir returns the directory the certificates of a node are kept in.
+func getNodeDir(name string) string {
+	return constants.MakeMiniPath("profiles", cfg.GetMachineName(), "nodes", name)
+}
+
+func getNodesPath() string {
+	return constants.MakeMiniPath("profiles", cfg.GetMachineName(), "nodes.json")
+}
+
+// GetNodes returns the nodes added to the cluster of the current profile.
+func GetNodes() ([]Node, error) {
+	nodes := []Node{}
+	data, err := ioutil.ReadFile(getNodesPath())
+	if os.IsNotExist(err) {
+		return nodes, nil
+	}
+	if err != nil {
+		return nil, errors.Wrap(err, "Error reading nodes")
+	}
+	if err := json.Unmarshal(data, &nodes); err != nil {
+		return nil, errors.Wrapf(err, "Error parsing nodes: %s", getNodesPath())
+	}
+	return nodes, nil
+}
+
+func saveNodes(nodes []Node) error {
+	data, err := json.MarshalIndent(nodes, "", "  ")
+	if err != nil {
+		return errors.Wrap(err, "Error encoding nodes")
+	}
+	if err := os.MkdirAll(filepath.Dir(getNodesPath()), 0700); err != nil {
+		return errors.Wrap(err, "Error creating profile directory")
+	}
+	if err := ioutil.WriteFile(getNodesPath(), data, 0644); err != nil {
+		return errors.Wrap(err, "Error saving nodes")
+	}
+	return nil
+}
+
+func findNode(nodes []Node, name string) int {
+	for i, n := range nodes {
+		if n.Name == name {
+			return i
+		}
+	}
+	return -1
+}
+
+// AddNode records a new node and starts it. The node is recorded first, so that RemoveNode can
+// clean up after a node that failed to start.
+func AddNode(api libmachine.API, config MachineConfig, kubernetesConfig KubernetesConfig, name string) error {
+	if !nodeNameRegexp.MatchString(name) {
+		return errors.Errorf("Invalid node name %q, must consist of lower case alphanumeric characters or '-', and start and end with an alphanumeric character", name)
+	}
+	if config.VMDriver == "none" {
+		return errors.New("The none driver doesn't support nodes, as it runs the cluster on the host")
+	}
+	nodes, err := GetNodes()
+	if err != nil {
+		return err
+	}
+	if findNode(nodes, name) >= 0 {
+		return errors.Errorf("Node %s already exists", name)
+	}
+	podCIDR, err := allocatePodCIDR(kubernetesConfig, nodes)
+	if err != nil {
+		return err
+	}
+	if err := saveNodes(append(nodes, Node{Name: name, PodCIDR: podCIDR})); err != nil {
+		return err
+	}
+	if len(nodes) == 0 {
+		if err := restartClusterForNodes(api, kubernetesConfig); err != nil {
+			return err
+		}
+	}
+	return StartNode(api, config, kubernetesConfig, name)
+}
+
+// restartClusterForNodes restarts localkube on the cluster's machine when its first node is added,
+// to switch it to the pod network of a cluster with nodes, see withNodeNetwork.
+func restartClusterForNodes(api libmachine.API, kubernetesConfig KubernetesConfig) error {
+	master, err := CheckIfApiExistsAndLoad(api)
+	if err != nil {
+		return errors.Wrap(err, "Error loading the cluster's machine, run minikube start first")
+	}
+	kubernetesConfig.NodeIP, err = master.Driver.GetIP()
+	if err != nil {
+		return errors.Wrap(err, "Error getting the cluster's IP")
+	}
+	if err := StartCluster(api, kubernetesConfig); err != nil {
+		return errors.Wrap(err, "Error restarting localkube on the cluster's machine")
+	}
+	return nil
+}
+
+// StartNode starts the machine of a node with the machine config of the cluster, creating it if
+// needed, and runs localkube on it as a worker joining the apiserver of the profile's machine.
+func StartNode(api libmachine.API, config MachineConfig, kubernetesConfig KubernetesConfig, name string) error {
+	master, err := CheckIfApiExistsAndLoad(api)
+	if err != nil {
+		return errors.Wrap(err, "Error loading the cluster's machine, run minikube start first")
+	}
+	masterIP, err := master.Driver.GetIP()
+	if err != nil {
+		return errors.Wrap(err, "Error getting the cluster's IP")
+	}
+	nodes, err := GetNodes()
+	if err != nil {
+		return err
+	}
+	i := findNode(nodes, name)
+	if i < 0 {
+		return errors.Errorf("Node %s doesn't exist", name)
+	}
+	if nodes[i].PodCIDR == "" {
+		// The node was added before nodes had their own pod CIDR
+		nodes[i].PodCIDR, err = allocatePodCIDR(kubernetesConfig, nodes)
+		if err != nil {
+			return err
+		}
+		if err := saveNodes(nodes); err != nil {
+			return err
+		}
+	}
+	networkConfig, err := withNodeNetwork(kubernetesConfig, nodes)
+	if err != nil {
+		return err
+	}
+
+	config.MachineName = GetNodeMachineName(name)
+	h, err := StartHost(api, config)
+	if err != nil {
+		return errors.Wrapf(err, "Error starting node %s", name)
+	}
+	ip, err := h.Driver.GetIP()
+	if err != nil {
+		return errors.Wrap(err, "Error getting the node's IP")
+	}
+
+	localkubeFile, err := getLocalkubeFile(kubernetesConfig)
+	if err != nil {
+		return err
+	}
+	certFiles, err := getNodeCerts(name)
+	if err != nil {
+		return errors.Wrapf(err, "Error generating the certificates of node %s", name)
+	}
+	if err := transferFiles(h.Driver, append([]assets.CopyableFile{localkubeFile}, certFiles...)); err != nil {
+		return errors.Wrapf(err, "Error copying files to node %s", name)
+	}
+
+	joinURL := fmt.Sprintf("https://%s", net.JoinHostPort(masterIP, strconv.Itoa(util.APIServerPort)))
+	if err := startLocalkube(h, getWorkerConfig(networkConfig, joinURL, ip, nodes[i].PodCIDR)); err != nil {
+		return err
+	}
+	return routePodCIDRs(api, kubernetesConfig, nodes)
+}
+
+// getWorkerConfig returns the config of the localkube of a node, which keeps the settings of the
+// kubelet and proxy from the cluster's config, and assigns the IPs of pods from podCIDR.
+func getWorkerConfig(k KubernetesConfig, joinURL, nodeIP, podCIDR string) KubernetesConfig {
+	worker := KubernetesConfig{
+		KubernetesVersion: k.KubernetesVersion,
+		NodeIP:            nodeIP,
+		APIServerName:     constants.APIServerName,
+		DNSDomain:         k.DNSDomain,
+		ContainerRuntime:  k.ContainerRuntime,
+		NetworkPlugin:     k.NetworkPlugin,
+		FeatureGates:      k.FeatureGates,
+		PodCIDR:           podCIDR,
+		ServiceCIDR:       k.ServiceCIDR,
+		DNSIP:             k.DNSIP,
+		ProxyMode:         k.ProxyMode,
+		MasqueradeAll:     k.MasqueradeAll,
+		ClusterCIDR:       k.ClusterCIDR,
+		JoinAPIServer:     joinURL,
+	}
+	for _, e := range k.ExtraOptions {
+		for _, c := range workerComponents {
+			if e.Component == c {
+				worker.ExtraOptions = append(worker.ExtraOptions, e)
+			}
+		}
+	}
+	return worker
+}
+
+// getNodeCerts returns the CA certificate and the client certificate of a node, which is created
+// the first time. The apiserver authenticates the node as system:node:<machine name> in the
+// system:nodes group.
+func getNodeCerts(name string) ([]assets.CopyableFile, error) {
+	caCert := constants.MakeMiniPath("ca.crt")
+	caKey := constants.MakeMiniPath("ca.key")
+	certPath := filepath.Join(getNodeDir(name), "node.crt")
+	keyPath := filepath.Join(getNodeDir(name), "node.key")
+	if !util.CanReadFile(certPath) || !util.CanReadFile(keyPath) {
+		if err := util.GenerateClientCert(certPath, keyPath, "system:node:"+GetNodeMachineName(name), []string{"system:nodes"}, caCert, caKey); err != nil {
+			return nil, err
+		}
+	}
+
+	files := []assets.CopyableFile{}
+	for _, f := range []struct {
+		path, name, permissions string
+	}{
+		{caCert, "ca.crt", "0644"},
+		{certPath, "node.crt", "0644"},
+		{keyPath, "node.key", "0600"},
+	} {
+		file, err := assets.NewFileAsset(f.path, util.DefaultCertPath, f.name, f.permissions)
+		if err != nil {
+			return nil, err
+		}
+		files = append(files, file)
+	}
+	return files, nil
+}
+
+// StopNode stops the machine of a node.
+func StopNode(api libmachine.API, name string) error {
+	return stopHost(api, GetNodeMachineName(name))
+}
+
+// RemoveNode deletes the machine and the certificates of a node, and forgets it.
+func RemoveNode(api libmachine.API, name string) error {
+	nodes, err := GetNodes()
+	if err != nil {
+		return err
+	}
+	i := findNode(nodes, name)
+	if i < 0 {
+		return errors.Errorf("Node %s doesn't exist", name)
+	}
+
+	exists, err := api.Exists(GetNodeMachineName(name))
+	if err != nil {
+		return errors.Wrapf(err, "Error checking that node %s exists", name)
+	}
+	if exists {
+		if err := deleteHost(api, GetNodeMachineName(name)); err != nil {
+			return err
+		}
+	} else {
+		glog.Infof("The machine of node %s doesn't exist", name)
+	}
+	if err := os.RemoveAll(getNodeDir(name)); err != nil {
+		return errors.Wrapf(err, "Error removing the certificates of node %s", name)
+	}
+	podCIDR := nodes[i].PodCIDR
+	nodes = append(nodes[:i], nodes[i+1:]...)
+	if err := saveNodes(nodes); err != nil {
+		return err
+	}
+	if podCIDR != "" {
+		if err := unroutePodCIDR(api, nodes, podCIDR); err != nil {
+			glog.Errorf("Error removing the route to the pods of node %s: %s", name, err)
+		}
+	}
+	return nil
+}
+
+// GetNodeHost loads the machine of a node.
+func GetNodeHost(api libmachine.API, name string) (*host.Host, error) {
+	nodes, err := GetNodes()
+	if err != nil {
+		return nil, err
+	}
+	if findNode(nodes, name) < 0 {
+		return nil, errors.Errorf("Node %s doesn't exist", name)
+	}
+	return loadHost(api, GetNodeMachineName(name))
+}
+
+// GetNodeHostStatus gets the status of the machine of a node.
+func GetNodeHostStatus(api libmachine.API, name string) (string, error) {
+	return getHostStatus(api, GetNodeMachineName(name))
+}
+
+// GetNodeLocalkubeStatus gets the status of localkube on the machine of a node.
+func GetNodeLocalkubeStatus(api libmachine.API, name string) (string, error) {
+	h, err := GetNodeHost(api, name)
+	if err != nil {
+		return "", err
+	}
+	return getLocalkubeStatus(h)
+}
+
+// CreateNodeSSHShell logs into or runs a command on the machine of a node.
+func CreateNodeSSHShell(api libmachine.API, name string, args []string) error {
+	h, err := GetNodeHost(api, name)
+	if err != nil {
+		return err
+	}
+	return createSSHShell(h, args)
+}
@@ -0,0 +1,229 @@
+/*
+Copyright 2016 The Kubernetes Authors All rights reserved.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package cluster
+
+import (
+	"fmt"
+	"net"
+	"strings"
+
+	"github.com/docker/machine/libmachine"
+	"github.com/docker/machine/libmachine/host"
+	"github.com/docker/machine/libmachine/state"
+	"github.com/golang/glog"
+	"github.com/pkg/errors"
+
+	cfg "k8s.io/minikube/pkg/minikube/config"
+	"k8s.io/minikube/pkg/util"
+)
+
+// getPodNetwork returns the pod CIDR of the cluster's machine, and the cluster CIDR that the pod
+// CIDRs of the nodes are carved from: --cluster-cidr if it is set, or else the /16 around the pod
+// CIDR of the cluster's machine.
+func getPodNetwork(k KubernetesConfig) (*net.IPNet, *net.IPNet, error) {
+	podCIDR := k.PodCIDR
+	if podCIDR == "" {
+		podCIDR = util.DefaultPodCIDR
+	}
+	_, pods, err := net.ParseCIDR(podCIDR)
+	if err != nil {
+		return nil, nil, errors.Wrap(err, "Error parsing the pod CIDR")
+	}
+	podPrefix, bits := pods.Mask.Size()
+
+	var clusterCIDR *net.IPNet
+	if k.ClusterCIDR != "" {
+		if _, clusterCIDR, err = net.ParseCIDR(k.ClusterCIDR); err != nil {
+			return nil, nil, errors.Wrap(err, "Error parsing the cluster CIDR")
+		}
+	} else {
+		if podPrefix <= defaultClusterCIDRPrefix {
+			return nil, nil, errors.Errorf("The pod CIDR %s is too large for nodes to get their own pod CIDR around it, set --cluster-cidr to a larger range containing it", pods)
+		}
+		mask := net.CIDRMask(defaultClusterCIDRPrefix, bits)
+		clusterCIDR = &net.IPNet{IP: pods.IP.Mask(mask), Mask: mask}
+	}
+	if clusterPrefix, _ := clusterCIDR.Mask.Size(); !clusterCIDR.Contains(pods.IP) || clusterPrefix >= podPrefix {
+		return nil, nil, errors.Errorf("The cluster CIDR %s must be larger than the pod CIDR %s and contain it, to have room for the pod CIDRs of the nodes", clusterCIDR, pods)
+	}
+
+	serviceCIDR := k.ServiceCIDR
+	if serviceCIDR == "" {
+		serviceCIDR = util.DefaultServiceCIDR
+	}
+	_, services, err := net.ParseCIDR(serviceCIDR)
+	if err != nil {
+		return nil, nil, errors.Wrap(err, "Error parsing the service cluster IP range")
+	}
+	if util.CIDRsOverlap(*clusterCIDR, *services) {
+		return nil, nil, errors.Errorf("The cluster CIDR %s overlaps the service cluster IP range %s", clusterCIDR, services)
+	}
+	return pods, clusterCIDR, nil
+}
+
+// allocatePodCIDR returns the first subnet of the cluster CIDR, of the size of the pod CIDR of the
+// cluster's machine, that isn't the pod CIDR of the cluster's machine or of one of the nodes.
+func allocatePodCIDR(k KubernetesConfig, nodes []Node) (string, error) {
+	pods, clusterCIDR, err := getPodNetwork(k)
+	if err != nil {
+		return "", err
+	}
+	used := []net.IPNet{*pods}
+	for _, n := range nodes {
+		if _, podCIDR, err := net.ParseCIDR(n.PodCIDR); err == nil {
+			used = append(used, *podCIDR)
+		}
+	}
+
+	subnet := net.IPNet{IP: clusterCIDR.IP.Mask(clusterCIDR.Mask), Mask: pods.Mask}
+	for clusterCIDR.Contains(subnet.IP) {
+		free := true
+		for _, u := range used {
+			free = free && !util.CIDRsOverlap(subnet, u)
+		}
+		if free {
+			return subnet.String(), nil
+		}
+		subnet.IP = nextSubnet(subnet)
+	}
+	return "", errors.Errorf("The cluster CIDR %s has no pod CIDR left for another node", clusterCIDR)
+}
+
+// nextSubnet returns the IP of the subnet of the same size right after n.
+func nextSubnet(n net.IPNet) net.IP {
+	ip := make(net.IP, len(n.IP))
+	copy(ip, n.IP)
+	prefix, _ := n.Mask.Size()
+	carry := 1 << uint(7-(prefix-1)%8)
+	for i := (prefix - 1) / 8; i >= 0 && carry > 0; i-- {
+		sum := int(ip[i]) + carry
+		ip[i] = byte(sum)
+		carry = sum >> 8
+	}
+	return ip
+}
+
+// withNodeNetwork returns the config of the cluster's machine once it has nodes. Its proxy treats
+// the whole cluster CIDR as the traffic of pods, and its kubelet runs kubenet unless another
+// network plugin is set, so that its pods get IPs from its pod CIDR rather than from Docker's bridge,
+// which is the same on every machine.
+func withNodeNetwork(k KubernetesConfig, nodes []Node) (KubernetesConfig, error) {
+	if len(nodes) == 0 {
+		return k, nil
+	}
+	_, clusterCIDR, err := getPodNetwork(k)
+	if err != nil {
+		return k, err
+	}
+	k.ClusterCIDR = clusterCIDR.String()
+	if k.NetworkPlugin == "" {
+		k.NetworkPlugin = nodeNetworkPlugin
+	}
+	return k, nil
+}
+
+// podRoute routes the pod CIDR of a machine of the cluster through its IP.
+type podRoute struct {
+	podCIDR string
+	ip      string
+}
+
+// routePodCIDRs routes the pod CIDR of every running machine of the cluster, the cluster's own and
+// its nodes', through the IP of the machine on each of the other ones.
+func routePodCIDRs(api libmachine.API, k KubernetesConfig, nodes []Node) error {
+	pods, clusterCIDR, err := getPodNetwork(k)
+	if err != nil {
+		return err
+	}
+	hosts, routes := []*host.Host{}, []podRoute{}
+	addHost := func(h *host.Host, podCIDR string) error {
+		ip, err := h.Driver.GetIP()
+		if err != nil {
+			return errors.Wrapf(err, "Error getting the IP of %s", h.Name)
+		}
+		hosts = append(hosts, h)
+		routes = append(routes, podRoute{podCIDR: podCIDR, ip: ip})
+		return nil
+	}
+
+	master, err := CheckIfApiExistsAndLoad(api)
+	if err != nil {
+		return errors.Wrap(err, "Error loading the cluster's machine")
+	}
+	if err := addHost(master, pods.String()); err != nil {
+		return err
+	}
+	for _, n := range nodes {
+		if s, err := GetNodeHostStatus(api, n.Name); err != nil || s != state.Running.String() || n.PodCIDR == "" {
+			continue
+		}
+		h, err := loadHost(api, GetNodeMachineName(n.Name))
+		if err != nil {
+			return err
+		}
+		if err := addHost(h, n.PodCIDR); err != nil {
+			return err
+		}
+	}
+
+	for i, h := range hosts {
+		others := append(append([]podRoute{}, routes[:i]...), routes[i+1:]...)
+		cmd := getPodRoutesCommand(clusterCIDR.String(), others)
+		glog.Infoln(cmd)
+		if output, err := RunCommand(h, cmd, true); err != nil {
+			return errors.Wrapf(err, "Error routing the pod CIDRs of the cluster on %s: %s", h.Name, output)
+		}
+	}
+	return nil
+}
+
+// getPodRoutesCommand returns the command adding routes to the pods of the other machines of the
+// cluster, and letting the machine forward the traffic of pods, which Docker drops by default.
+func getPodRoutesCommand(clusterCIDR string, routes []podRoute) string {
+	cmds := []string{}
+	for _, dir := range []string{"-s", "-d"} {
+		rule := fmt.Sprintf("FORWARD %s %s -j ACCEPT", dir, clusterCIDR)
+		cmds = append(cmds, fmt.Sprintf("(sudo iptables -C %s 2>/dev/null || sudo iptables -I %s)", rule, rule))
+	}
+	for _, r := range routes {
+		cmds = append(cmds, fmt.Sprintf("sudo ip route replace %s via %s", r.podCIDR, r.ip))
+	}
+	return strings.Join(cmds, " && ")
+}
+
+// unroutePodCIDR removes the route to the pod CIDR of a removed node from the running machines of
+// the cluster.
+func unroutePodCIDR(api libmachine.API, nodes []Node, podCIDR string) error {
+	names := []string{cfg.GetMachineName()}
+	for _, n := range nodes {
+		names = append(names, GetNodeMachineName(n.Name))
+	}
+	for _, name := range names {
+		if s, err := getHostStatus(api, name); err != nil || s != state.Running.String() {
+			continue
+		}
+		h, err := loadHost(api, name)
+		if err != nil {
+			return err
+		}
+		cmd := fmt.Sprintf("sudo ip route del %s 2>/dev/null; true", podCIDR)
+		if output, err := RunCommand(h, cmd, true); err != nil {
+			return errors.Wrapf(err, "Error removing the route to %s on %s: %s", podCIDR, name, output)
+		}
+	}
+	return nil
+}
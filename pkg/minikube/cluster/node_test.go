@@ -0,0 +1,261 @@
+/*
+Copyright 2016 The Kubernetes Authors All rights reserved.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package cluster
+
+import (
+	"bytes"
+	"io/ioutil"
+	"net"
+	"os"
+	"path/filepath"
+	"reflect"
+	"strings"
+	"testing"
+
+	"github.com/docker/machine/libmachine/auth"
+	"github.com/docker/machine/libmachine/drivers"
+	"github.com/docker/machine/libmachine/engine"
+	"github.com/docker/machine/libmachine/host"
+	"github.com/docker/machine/libmachine/provision"
+	"github.com/docker/machine/libmachine/state"
+	"k8s.io/minikube/pkg/minikube/config"
+	"k8s.io/minikube/pkg/minikube/constants"
+	"k8s.io/minikube/pkg/minikube/tests"
+	"k8s.io/minikube/pkg/util"
+)
+
+func TestCreateNodeHost(t *testing.T) {
+	api := tests.NewMockAPI()
+	machineConfig := defaultMachineConfig
+	machineConfig.MachineName = GetNodeMachineName("node1")
+
+	h, err := createHost(api, machineConfig)
+	if err != nil {
+		t.Fatalf("Error creating host: %v", err)
+	}
+	if h.Name != config.GetMachineName()+"-node1" {
+		t.Fatalf("Machine created with incorrect name: %s", h.Name)
+	}
+	if exists, _ := api.Exists(config.GetMachineName()); exists {
+		t.Fatal("The cluster's machine should not be created")
+	}
+}
+
+func TestAddRemoveNode(t *testing.T) {
+	tempDir := tests.MakeTempDir()
+	defer os.RemoveAll(tempDir)
+	if err := util.GenerateCACert(constants.MakeMiniPath("ca.crt"), constants.MakeMiniPath("ca.key"), constants.APIServerName); err != nil {
+		t.Fatalf("Error generating CA: %s", err)
+	}
+
+	api := tests.NewMockAPI()
+	s, _ := tests.NewSSHServer()
+	port, err := s.Start()
+	if err != nil {
+		t.Fatalf("Error starting ssh server: %s", err)
+	}
+	api.Hosts[config.GetMachineName()] = &host.Host{
+		Name:        config.GetMachineName(),
+		HostOptions: &host.Options{AuthOptions: &auth.Options{}, EngineOptions: &engine.Options{}},
+		Driver:      &tests.MockDriver{Port: port, BaseDriver: drivers.BaseDriver{IPAddress: "192.168.99.100"}, CurrentState: state.Running},
+	}
+	machineName := GetNodeMachineName("node1")
+	api.Hosts[machineName] = &host.Host{
+		Name:        machineName,
+		HostOptions: &host.Options{AuthOptions: &auth.Options{}, EngineOptions: &engine.Options{}},
+		Driver: &tests.MockDriver{
+			Port:         port,
+			CurrentState: state.Running,
+			BaseDriver: drivers.BaseDriver{
+				IPAddress:  "127.0.0.1",
+				SSHKeyPath: "",
+			},
+		},
+	}
+	provision.SetDetector(&tests.MockDetector{Provisioner: &tests.MockProvisioner{}})
+
+	kubernetesConfig := KubernetesConfig{
+		KubernetesVersion: constants.DefaultKubernetesVersion,
+		ExtraOptions: util.ExtraOptionSlice{
+			{Component: "apiserver", Key: "EnableSwaggerUI", Value: "true"},
+			{Component: "kubelet", Key: "MaxPods", Value: "50"},
+		},
+	}
+	if err := AddNode(api, defaultMachineConfig, kubernetesConfig, "node1"); err != nil {
+		t.Fatalf("Error adding node: %s", err)
+	}
+
+	if nodes, err := GetNodes(); err != nil || !reflect.DeepEqual(nodes, []Node{{Name: "node1", PodCIDR: "10.180.0.0/24"}}) {
+		t.Fatalf("Expected node1 to be recorded, got %v, %v", nodes, err)
+	}
+	cert, err := util.LoadCert(filepath.Join(getNodeDir("node1"), "node.crt"))
+	if err != nil {
+		t.Fatalf("Error loading node certificate: %s", err)
+	}
+	if cert.Subject.CommonName != "system:node:"+machineName || !reflect.DeepEqual(cert.Subject.Organization, []string{"system:nodes"}) {
+		t.Errorf("Unexpected node certificate subject %+v", cert.Subject)
+	}
+	ca, _ := ioutil.ReadFile(constants.MakeMiniPath("ca.crt"))
+	if !bytes.Contains(s.Transfers.Bytes(), ca) {
+		t.Errorf("Expected the CA certificate to be copied to the node")
+	}
+	started, restarted := false, false
+	for cmd := range s.Commands {
+		if strings.Contains(cmd, "--join=https://192.168.99.100:8443") {
+			started = true
+			if !strings.Contains(cmd, "--extra-config=kubelet.MaxPods=50") || strings.Contains(cmd, "apiserver") {
+				t.Errorf("Expected only the kubelet extra config to be passed to the worker: %s", cmd)
+			}
+			for _, flag := range []string{"--pod-cidr=10.180.0.0/24", "--cluster-cidr=10.180.0.0/16", "--network-plugin=kubenet"} {
+				if !strings.Contains(cmd, flag) {
+					t.Errorf("Expected the worker to be started with %s: %s", flag, cmd)
+				}
+			}
+		} else if strings.Contains(cmd, "--cluster-cidr=10.180.0.0/16") {
+			restarted = true
+			if strings.Contains(cmd, "--pod-cidr") || !strings.Contains(cmd, "--network-plugin=kubenet") {
+				t.Errorf("Expected the cluster to keep its pod CIDR and run kubenet: %s", cmd)
+			}
+		}
+	}
+	if !started {
+		t.Fatalf("Expected localkube to join the cluster, got %v", s.Commands)
+	}
+	if !restarted {
+		t.Errorf("Expected localkube to be restarted on the cluster's machine with the cluster CIDR, got %v", s.Commands)
+	}
+	for _, route := range []string{"sudo ip route replace 10.180.0.0/24 via 127.0.0.1", "sudo ip route replace 10.180.1.0/24 via 192.168.99.100"} {
+		routed := false
+		for cmd := range s.Commands {
+			routed = routed || strings.Contains(cmd, route)
+		}
+		if !routed {
+			t.Errorf("Expected the route %q to be added, got %v", route, s.Commands)
+		}
+	}
+
+	if err := AddNode(api, defaultMachineConfig, kubernetesConfig, "node1"); err == nil {
+		t.Errorf("Expected an error adding an existing node")
+	}
+	if err := AddNode(api, defaultMachineConfig, kubernetesConfig, "Node_2"); err == nil {
+		t.Errorf("Expected an error adding a node with an invalid name")
+	}
+
+	if err := RemoveNode(api, "node1"); err != nil {
+		t.Fatalf("Error removing node: %s", err)
+	}
+	if exists, _ := api.Exists(machineName); exists {
+		t.Errorf("Expected the machine of the node to be deleted")
+	}
+	if _, err := os.Stat(getNodeDir("node1")); !os.IsNotExist(err) {
+		t.Errorf("Expected the certificates of the node to be deleted, got %v", err)
+	}
+	if nodes, err := GetNodes(); err != nil || len(nodes) != 0 {
+		t.Errorf("Expected no nodes, got %v, %v", nodes, err)
+	}
+	if _, ok := s.Commands["sudo ip route del 10.180.0.0/24 2>/dev/null; true"]; !ok {
+		t.Errorf("Expected the route to the pods of the node to be removed, got %v", s.Commands)
+	}
+	if err := RemoveNode(api, "node1"); err == nil {
+		t.Errorf("Expected an error removing a node that doesn't exist")
+	}
+}
+
+func TestAllocatePodCIDR(t *testing.T) {
+	k := KubernetesConfig{PodCIDR: util.DefaultPodCIDR, ServiceCIDR: util.DefaultServiceCIDR}
+	nodes := []Node{}
+	for _, name := range []string{"node1", "node2"} {
+		podCIDR, err := allocatePodCIDR(k, nodes)
+		if err != nil {
+			t.Fatalf("Error allocating the pod CIDR of %s: %s", name, err)
+		}
+		nodes = append(nodes, Node{Name: name, PodCIDR: podCIDR})
+	}
+	if expected := []Node{{"node1", "10.180.0.0/24"}, {"node2", "10.180.2.0/24"}}; !reflect.DeepEqual(nodes, expected) {
+		t.Fatalf("Expected the nodes to get %v, got %v", expected, nodes)
+	}
+
+	_, clusterCIDR, _ := net.ParseCIDR("10.180.0.0/16")
+	_, master, _ := net.ParseCIDR(util.DefaultPodCIDR)
+	subnets := []net.IPNet{*master}
+	for _, n := range nodes {
+		_, podCIDR, _ := net.ParseCIDR(n.PodCIDR)
+		if !clusterCIDR.Contains(podCIDR.IP) {
+			t.Errorf("Expected the pod CIDR %s of %s to be in the cluster CIDR %s", podCIDR, n.Name, clusterCIDR)
+		}
+		for _, s := range subnets {
+			if util.CIDRsOverlap(s, *podCIDR) {
+				t.Errorf("Expected the pod CIDR %s of %s to be disjoint from %s", podCIDR, n.Name, s.String())
+			}
+		}
+		subnets = append(subnets, *podCIDR)
+	}
+}
+
+func TestAllocatePodCIDRRanges(t *testing.T) {
+	var tests = []struct {
+		description string
+		config      KubernetesConfig
+		nodes       []Node
+		expected    string
+		shouldErr   bool
+	}{
+		{description: "default", expected: "10.180.0.0/24"},
+		{description: "reuses removed", nodes: []Node{{"node2", "10.180.2.0/24"}}, expected: "10.180.0.0/24"},
+		{description: "skips taken", nodes: []Node{{"node1", "10.180.0.0/24"}, {"node2", "10.180.2.0/24"}}, expected: "10.180.3.0/24"},
+		{description: "cluster cidr", config: KubernetesConfig{PodCIDR: "172.30.0.0/24", ClusterCIDR: "172.30.0.0/20"}, expected: "172.30.1.0/24"},
+		{description: "crosses bytes", config: KubernetesConfig{PodCIDR: "10.180.0.0/25", ClusterCIDR: "10.180.0.0/23"}, nodes: []Node{{"node1", "10.180.0.128/25"}}, expected: "10.180.1.0/25"},
+		{description: "exhausted", config: KubernetesConfig{ClusterCIDR: "10.180.0.0/23"}, nodes: []Node{{"node1", "10.180.0.0/24"}}, shouldErr: true},
+		{description: "pod cidr outside cluster cidr", config: KubernetesConfig{ClusterCIDR: "10.244.0.0/16"}, shouldErr: true},
+		{description: "cluster cidr as large as pod cidr", config: KubernetesConfig{ClusterCIDR: "10.180.1.0/24"}, shouldErr: true},
+		{description: "large pod cidr", config: KubernetesConfig{PodCIDR: "172.30.0.0/16"}, shouldErr: true},
+		{description: "overlaps services", config: KubernetesConfig{PodCIDR: "10.0.1.0/24", ClusterCIDR: "10.0.0.0/16"}, shouldErr: true},
+	}
+
+	for _, test := range tests {
+		t.Run(test.description, func(t *testing.T) {
+			podCIDR, err := allocatePodCIDR(test.config, test.nodes)
+			if err != nil && !test.shouldErr {
+				t.Errorf("Unexpected error: %s", err)
+			}
+			if err == nil && test.shouldErr {
+				t.Errorf("Expected an error, got %s", podCIDR)
+			}
+			if podCIDR != test.expected {
+				t.Errorf("Expected pod CIDR %s, got %s", test.expected, podCIDR)
+			}
+		})
+	}
+}
+
+func TestWithNodeNetwork(t *testing.T) {
+	k := KubernetesConfig{PodCIDR: util.DefaultPodCIDR}
+	if c, err := withNodeNetwork(k, nil); err != nil || !reflect.DeepEqual(c, k) {
+		t.Errorf("Expected the config of a cluster without nodes to be unchanged, got %+v, %v", c, err)
+	}
+	c, err := withNodeNetwork(k, []Node{{"node1", "10.180.0.0/24"}})
+	if err != nil {
+		t.Fatalf("Unexpected error: %s", err)
+	}
+	if c.ClusterCIDR != "10.180.0.0/16" || c.NetworkPlugin != nodeNetworkPlugin || c.PodCIDR != util.DefaultPodCIDR {
+		t.Errorf("Unexpected config of a cluster with nodes %+v", c)
+	}
+	k.NetworkPlugin = "cni"
+	if c, _ := withNodeNetwork(k, []Node{{"node1", "10.180.0.0/24"}}); c.NetworkPlugin != "cni" {
+		t.Errorf("Expected the network plugin to be kept, got %s", c.NetworkPlugin)
+	}
+}
@@ -16,7 +16,11 @@ limitations under the License.
 
 package cluster
 
-import "k8s.io/minikube/pkg/util"
+import (
+	cfg "k8s.io/minikube/pkg/minikube/config"
+	"k8s.io/minikube/pkg/minikube/storageclass"
+	"k8s.io/minikube/pkg/util"
+)
 
 // MachineConfig contains the parameters used to start a cluster.
 type MachineConfig struct {
@@ -35,16 +39,43 @@ type MachineConfig struct {
 	Downloader          util.ISODownloader
 	DockerOpt           []string // Each entry is formatted as KEY=VALUE.
 	DisableDriverMounts bool     // Only used by virtualbox and xhyve
+	MachineName         string   // The machine of the profile if empty, set for the machines of nodes
+}
+
+// GetMachineName returns the name of the machine the config creates.
+func (c MachineConfig) GetMachineName() string {
+	if c.MachineName != "" {
+		return c.MachineName
+	}
+	return cfg.GetMachineName()
 }
 
 // KubernetesConfig contains the parameters used to configure the VM Kubernetes.
 type KubernetesConfig struct {
-	KubernetesVersion string
-	NodeIP            string
-	APIServerName     string
-	DNSDomain         string
-	ContainerRuntime  string
-	NetworkPlugin     string
-	FeatureGates      string
-	ExtraOptions      util.ExtraOptionSlice
+	KubernetesVersion        string
+	NodeIP                   string
+	APIServerName            string
+	APIServerExtraSANs       []string
+	DNSDomain                string
+	ContainerRuntime         string
+	NetworkPlugin            string
+	FeatureGates             string
+	StorageBackend           string
+	EtcdServers              []string
+	EtcdCAFile               string
+	EtcdCertFile             string
+	EtcdKeyFile              string
+	AdmissionControl         []string
+	AuthorizationMode        string
+	AuthorizationWebhookFile string
+	AuditLogPath             string
+	StorageClasses           []storageclass.StorageClassConfig
+	PodCIDR                  string
+	ServiceCIDR              string
+	DNSIP                    string
+	ProxyMode                string
+	MasqueradeAll            bool
+	ClusterCIDR              string
+	JoinAPIServer            string // Set for the workers of nodes, which join the apiserver at this URL
+	ExtraOptions             util.ExtraOptionSlice
 }
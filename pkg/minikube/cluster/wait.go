@@ -0,0 +1,240 @@
+/*
+Copyright 2016 The Kubernetes Authors All rights reserved.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package cluster
+
+import (
+	"bytes"
+	"fmt"
+	"io"
+	"sort"
+	"strings"
+	"time"
+
+	"github.com/pkg/errors"
+	meta_v1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/labels"
+	"k8s.io/client-go/kubernetes"
+	"k8s.io/client-go/kubernetes/scheme"
+	"k8s.io/client-go/pkg/api/v1"
+	"k8s.io/client-go/pkg/apis/extensions/v1beta1"
+
+	"k8s.io/minikube/pkg/minikube/assets"
+)
+
+// waitInterval is how often WaitForCluster checks the cluster.
+var waitInterval = 2 * time.Second
+
+// waitGate is a condition the cluster has to meet to be usable. check returns nil once it is
+// met, or an error describing what it is waiting for.
+type waitGate struct {
+	description string
+	check       func() error
+}
+
+// WaitForCluster waits until the apiserver is healthy, the node is Ready, the default service
+// account exists and the pods of the enabled addons in kube-system are Ready, in this order. The
+// gate being waited on is printed to out. If the cluster isn't usable within timeout, the
+// returned error describes the gate that wasn't met.
+func WaitForCluster(client kubernetes.Interface, timeout time.Duration, out io.Writer) error {
+	selectors, err := getAddonPodSelectors()
+	if err != nil {
+		return errors.Wrap(err, "Error getting the pods of the enabled addons")
+	}
+	gates := []waitGate{
+		{"the apiserver to be healthy", func() error { return checkAPIServerHealthy(client) }},
+		{"the node to be Ready", func() error { return checkNodeReady(client) }},
+		{"the default service account", func() error { return checkDefaultServiceAccount(client) }},
+		{"the addon pods to be Ready", func() error { return checkAddonPodsReady(client, selectors) }},
+	}
+
+	return waitForGates(gates, timeout, out)
+}
+
+// waitForGates waits for each gate in turn, until they are all met or timeout passes.
+func waitForGates(gates []waitGate, timeout time.Duration, out io.Writer) error {
+	deadline := time.Now().Add(timeout)
+	for _, g := range gates {
+		fmt.Fprintf(out, "Waiting for %s...\n", g.description)
+		for {
+			err := g.check()
+			if err == nil {
+				break
+			}
+			if time.Now().After(deadline) {
+				return errors.Wrapf(err, "Timed out after %s waiting for %s", timeout, g.description)
+			}
+			time.Sleep(waitInterval)
+		}
+	}
+	return nil
+}
+
+func checkAPIServerHealthy(client kubernetes.Interface) error {
+	body, err := client.Core().RESTClient().Get().AbsPath("/healthz").Do().Raw()
+	if err != nil {
+		return errors.Wrap(err, "apiserver is not healthy")
+	}
+	if string(body) != "ok" {
+		return fmt.Errorf("apiserver is not healthy: %s", body)
+	}
+	return nil
+}
+
+func checkNodeReady(client kubernetes.Interface) error {
+	nodes, err := client.Core().Nodes().List(meta_v1.ListOptions{})
+	if err != nil {
+		return errors.Wrap(err, "Error listing nodes")
+	}
+	if len(nodes.Items) == 0 {
+		return errors.New("the node has not registered yet")
+	}
+	for _, n := range nodes.Items {
+		if !isNodeReady(n) {
+			return fmt.Errorf("node %s is not Ready", n.Name)
+		}
+	}
+	return nil
+}
+
+func isNodeReady(n v1.Node) bool {
+	for _, c := range n.Status.Conditions {
+		if c.Type == v1.NodeReady {
+			return c.Status == v1.ConditionTrue
+		}
+	}
+	return false
+}
+
+func checkDefaultServiceAccount(client kubernetes.Interface) error {
+	if _, err := client.Core().ServiceAccounts(meta_v1.NamespaceDefault).Get("default", meta_v1.GetOptions{}); err != nil {
+		return errors.Wrap(err, "Error getting the default service account")
+	}
+	return nil
+}
+
+// checkAddonPodsReady returns an error listing the addons whose pods don't exist yet, and the
+// pods that aren't Ready with the reason their containers are waiting or terminated.
+func checkAddonPodsReady(client kubernetes.Interface, selectors map[string][]labels.Selector) error {
+	pods, err := client.Core().Pods(meta_v1.NamespaceSystem).List(meta_v1.ListOptions{})
+	if err != nil {
+		return errors.Wrap(err, "Error listing kube-system pods")
+	}
+
+	addons := []string{}
+	for addon := range selectors {
+		addons = append(addons, addon)
+	}
+	sort.Strings(addons)
+	var problems []string
+	for _, addon := range addons {
+		for _, s := range selectors[addon] {
+			matched := false
+			for _, p := range pods.Items {
+				if !s.Matches(labels.Set(p.Labels)) {
+					continue
+				}
+				matched = true
+				if !isPodReady(p) {
+					problems = append(problems, fmt.Sprintf("%s pod %s is not Ready: %s", addon, p.Name, describePodStatus(p)))
+				}
+			}
+			if !matched {
+				problems = append(problems, fmt.Sprintf("%s has no pods matching %s yet", addon, s))
+			}
+		}
+	}
+	if len(problems) > 0 {
+		return errors.New(strings.Join(problems, "\n"))
+	}
+	return nil
+}
+
+func isPodReady(p v1.Pod) bool {
+	for _, c := range p.Status.Conditions {
+		if c.Type == v1.PodReady {
+			return c.Status == v1.ConditionTrue
+		}
+	}
+	return false
+}
+
+// describePodStatus returns the phase of the pod, and why its containers aren't running.
+func describePodStatus(p v1.Pod) string {
+	status := []string{string(p.Status.Phase)}
+	for _, c := range p.Status.ContainerStatuses {
+		switch {
+		case c.State.Waiting != nil:
+			status = append(status, fmt.Sprintf("%s is waiting: %s %s", c.Name, c.State.Waiting.Reason, c.State.Waiting.Message))
+		case c.State.Terminated != nil:
+			status = append(status, fmt.Sprintf("%s terminated: %s %s", c.Name, c.State.Terminated.Reason, c.State.Terminated.Message))
+		}
+		if c.RestartCount > 0 {
+			status = append(status, fmt.Sprintf("%s restarted %d times", c.Name, c.RestartCount))
+		}
+	}
+	return strings.TrimSpace(strings.Join(status, ", "))
+}
+
+// getAddonPodSelectors returns, for each enabled addon, the selectors of the pods its manifests
+// run: one for each replication controller, deployment, daemon set and static pod.
+func getAddonPodSelectors() (map[string][]labels.Selector, error) {
+	selectors := map[string][]labels.Selector{}
+	for name, addon := range assets.Addons {
+		enabled, err := addon.IsEnabled()
+		if err != nil {
+			return nil, errors.Wrapf(err, "Error checking if %s is enabled", name)
+		}
+		if !enabled {
+			continue
+		}
+		for _, a := range addon.Assets {
+			selectors[name] = append(selectors[name], getPodSelectors(a.GetData())...)
+		}
+	}
+	return selectors, nil
+}
+
+// getPodSelectors returns the selectors of the pods run by the objects of a manifest.
+func getPodSelectors(manifest []byte) []labels.Selector {
+	var selectors []labels.Selector
+	for _, doc := range bytes.Split(manifest, []byte("\n---")) {
+		if len(bytes.TrimSpace(doc)) == 0 {
+			continue
+		}
+		obj, _, err := scheme.Codecs.UniversalDeserializer().Decode(doc, nil, nil)
+		if err != nil {
+			// Only the kinds the client knows can run pods
+			continue
+		}
+		var podLabels map[string]string
+		switch o := obj.(type) {
+		case *v1.ReplicationController:
+			podLabels = o.Spec.Template.Labels
+		case *v1beta1.Deployment:
+			podLabels = o.Spec.Template.Labels
+		case *v1beta1.DaemonSet:
+			podLabels = o.Spec.Template.Labels
+		case *v1.Pod:
+			podLabels = o.Labels
+		}
+		if len(podLabels) == 0 {
+			continue
+		}
+		selectors = append(selectors, labels.SelectorFromSet(podLabels))
+	}
+	return selectors
+}
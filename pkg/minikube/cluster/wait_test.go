@@ -0,0 +1,166 @@
+/*
+Copyright 2016 The Kubernetes Authors All rights reserved.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package cluster
+
+import (
+	"bytes"
+	"errors"
+	"io/ioutil"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+	"time"
+
+	"k8s.io/apimachinery/pkg/labels"
+	"k8s.io/client-go/kubernetes"
+	"k8s.io/client-go/rest"
+)
+
+// testAPIServer serves the responses to the paths WaitForCluster requests.
+func testAPIServer(t *testing.T, responses map[string]string) (kubernetes.Interface, func()) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		body, ok := responses[r.URL.Path]
+		if !ok {
+			http.NotFound(w, r)
+			return
+		}
+		w.Header().Set("Content-Type", "application/json")
+		w.Write([]byte(body))
+	}))
+	client, err := kubernetes.NewForConfig(&rest.Config{Host: server.URL})
+	if err != nil {
+		t.Fatalf("Error creating client: %s", err)
+	}
+	return client, server.Close
+}
+
+const testReadyNodes = `{"kind": "NodeList", "apiVersion": "v1", "items": [
+	{"metadata": {"name": "minikube"}, "status": {"conditions": [{"type": "Ready", "status": "True"}]}}
+]}`
+
+const testKubeSystemPods = `{"kind": "PodList", "apiVersion": "v1", "items": [
+	{"metadata": {"name": "kube-dns-1326421443-hj4ks", "labels": {"k8s-app": "kube-dns"}},
+	 "status": {"phase": "Running", "conditions": [{"type": "Ready", "status": "True"}]}},
+	{"metadata": {"name": "kubernetes-dashboard-x7sc2", "labels": {"app": "kubernetes-dashboard"}},
+	 "status": {"phase": "Running", "conditions": [{"type": "Ready", "status": "False"}],
+	  "containerStatuses": [{"name": "kubernetes-dashboard", "restartCount": 3,
+	   "state": {"waiting": {"reason": "CrashLoopBackOff", "message": "Back-off 40s restarting failed container"}}}]}}
+]}`
+
+func TestWaitChecks(t *testing.T) {
+	client, stop := testAPIServer(t, map[string]string{
+		"/healthz":      "ok",
+		"/api/v1/nodes": testReadyNodes,
+		"/api/v1/namespaces/default/serviceaccounts/default": `{"kind": "ServiceAccount", "apiVersion": "v1", "metadata": {"name": "default"}}`,
+		"/api/v1/namespaces/kube-system/pods":                testKubeSystemPods,
+	})
+	defer stop()
+
+	if err := checkAPIServerHealthy(client); err != nil {
+		t.Errorf("Unexpected error checking the apiserver: %s", err)
+	}
+	if err := checkNodeReady(client); err != nil {
+		t.Errorf("Unexpected error checking the node: %s", err)
+	}
+	if err := checkDefaultServiceAccount(client); err != nil {
+		t.Errorf("Unexpected error checking the default service account: %s", err)
+	}
+
+	dns := map[string][]labels.Selector{"kube-dns": {labels.SelectorFromSet(labels.Set{"k8s-app": "kube-dns"})}}
+	if err := checkAddonPodsReady(client, dns); err != nil {
+		t.Errorf("Unexpected error checking the kube-dns pods: %s", err)
+	}
+	notReady := map[string][]labels.Selector{
+		"dashboard": {labels.SelectorFromSet(labels.Set{"app": "kubernetes-dashboard"})},
+		"registry":  {labels.SelectorFromSet(labels.Set{"kubernetes.io/minikube-addons": "registry"})},
+	}
+	err := checkAddonPodsReady(client, notReady)
+	if err == nil {
+		t.Fatalf("Expected an error checking pods that are not ready")
+	}
+	for _, expected := range []string{
+		"dashboard pod kubernetes-dashboard-x7sc2 is not Ready: Running, kubernetes-dashboard is waiting: CrashLoopBackOff",
+		"kubernetes-dashboard restarted 3 times",
+		"registry has no pods matching kubernetes.io/minikube-addons=registry yet",
+	} {
+		if !strings.Contains(err.Error(), expected) {
+			t.Errorf("Expected the error to contain %q, got: %s", expected, err)
+		}
+	}
+}
+
+func TestWaitChecksNotReady(t *testing.T) {
+	client, stop := testAPIServer(t, map[string]string{
+		"/api/v1/nodes": `{"kind": "NodeList", "apiVersion": "v1", "items": [
+			{"metadata": {"name": "minikube"}, "status": {"conditions": [{"type": "Ready", "status": "False"}]}}]}`,
+	})
+	defer stop()
+
+	if err := checkAPIServerHealthy(client); err == nil {
+		t.Errorf("Expected an error checking an unhealthy apiserver")
+	}
+	if err := checkNodeReady(client); err == nil || !strings.Contains(err.Error(), "node minikube is not Ready") {
+		t.Errorf("Expected an error checking a node that is not ready, got %v", err)
+	}
+	if err := checkDefaultServiceAccount(client); err == nil {
+		t.Errorf("Expected an error checking a missing service account")
+	}
+}
+
+func TestWaitForGates(t *testing.T) {
+	defer func(interval time.Duration) { waitInterval = interval }(waitInterval)
+	waitInterval = time.Millisecond
+
+	checks := 0
+	gates := []waitGate{
+		{"the first gate", func() error {
+			if checks++; checks < 3 {
+				return errors.New("not yet")
+			}
+			return nil
+		}},
+		{"the second gate", func() error { return errors.New("never") }},
+	}
+	var out bytes.Buffer
+	err := waitForGates(gates, 50*time.Millisecond, &out)
+	if err == nil || !strings.Contains(err.Error(), "waiting for the second gate: never") {
+		t.Errorf("Expected the second gate to time out, got %v", err)
+	}
+	if expected := "Waiting for the first gate...\nWaiting for the second gate...\n"; out.String() != expected {
+		t.Errorf("Expected output %q, got %q", expected, out.String())
+	}
+	if checks != 3 {
+		t.Errorf("Expected the first gate to be checked 3 times, got %d", checks)
+	}
+}
+
+func TestGetPodSelectors(t *testing.T) {
+	manifest, err := ioutil.ReadFile("../../../deploy/addons/ingress/ingress-rc.yaml")
+	if err != nil {
+		t.Fatalf("Error reading manifest: %s", err)
+	}
+	selectors := getPodSelectors(manifest)
+	if len(selectors) != 2 {
+		t.Fatalf("Expected a selector for each replication controller, got %v", selectors)
+	}
+
+	configMap := []byte("apiVersion: v1\nkind: ConfigMap\nmetadata:\n  name: test\n")
+	if selectors := getPodSelectors(configMap); len(selectors) != 0 {
+		t.Errorf("Expected no selectors for a config map, got %v", selectors)
+	}
+}
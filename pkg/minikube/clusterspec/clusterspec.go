@@ -0,0 +1,517 @@
+/*
+Copyright 2016 The Kubernetes Authors All rights reserved.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+// Package clusterspec reads and writes cluster specs, the file form of the configuration
+// minikube start creates a cluster with.
+package clusterspec
+
+import (
+	"encoding/json"
+	"fmt"
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"reflect"
+	"sort"
+	"strings"
+
+	units "github.com/docker/go-units"
+	"github.com/ghodss/yaml"
+	"github.com/pkg/errors"
+	"k8s.io/minikube/pkg/minikube/assets"
+	"k8s.io/minikube/pkg/minikube/cluster"
+	"k8s.io/minikube/pkg/minikube/constants"
+	"k8s.io/minikube/pkg/minikube/storageclass"
+	"k8s.io/minikube/pkg/util"
+)
+
+const (
+	// APIVersion is the version of the cluster spec schema.
+	APIVersion = "minikube.k8s.io/v1alpha1"
+	Kind       = "Cluster"
+
+	// fileName is the name of the spec minikube start saves in the profile directory.
+	fileName = "cluster.yaml"
+)
+
+// Cluster is the spec of a cluster. Fields that are left out keep the default of the matching
+// minikube start flag.
+type Cluster struct {
+	APIVersion string     `json:"apiVersion"`
+	Kind       string     `json:"kind"`
+	Machine    Machine    `json:"machine"`
+	Kubernetes Kubernetes `json:"kubernetes"`
+	// Addons maps the names of addons to whether they are enabled. Addons that aren't listed keep
+	// their state in the minikube config.
+	Addons map[string]bool `json:"addons,omitempty"`
+	// Mount is the host:vm directory pair minikube mount serves, as in --mount-string.
+	Mount string `json:"mount,omitempty"`
+}
+
+// Machine is the spec of the VM, see cluster.MachineConfig.
+type Machine struct {
+	ISOURL              string   `json:"isoURL,omitempty"`
+	Memory              int      `json:"memory,omitempty"`
+	CPUs                int      `json:"cpus,omitempty"`
+	DiskSize            string   `json:"diskSize,omitempty"`
+	VMDriver            string   `json:"vmDriver,omitempty"`
+	XhyveDiskDriver     string   `json:"xhyveDiskDriver,omitempty"`
+	DockerEnv           []string `json:"dockerEnv,omitempty"`
+	DockerOpt           []string `json:"dockerOpt,omitempty"`
+	InsecureRegistry    []string `json:"insecureRegistry,omitempty"`
+	RegistryMirror      []string `json:"registryMirror,omitempty"`
+	HostOnlyCIDR        string   `json:"hostOnlyCIDR,omitempty"`
+	HypervVirtualSwitch string   `json:"hypervVirtualSwitch,omitempty"`
+	KvmNetwork          string   `json:"kvmNetwork,omitempty"`
+	DisableDriverMounts bool     `json:"disableDriverMounts,omitempty"`
+}
+
+// Kubernetes is the spec of the Kubernetes cluster, see cluster.KubernetesConfig.
+type Kubernetes struct {
+	Version                  string         `json:"version,omitempty"`
+	APIServerName            string         `json:"apiServerName,omitempty"`
+	APIServerExtraSANs       []string       `json:"apiServerExtraSANs,omitempty"`
+	DNSDomain                string         `json:"dnsDomain,omitempty"`
+	ContainerRuntime         string         `json:"containerRuntime,omitempty"`
+	NetworkPlugin            string         `json:"networkPlugin,omitempty"`
+	FeatureGates             string         `json:"featureGates,omitempty"`
+	StorageBackend           string         `json:"storageBackend,omitempty"`
+	EtcdServers              []string       `json:"etcdServers,omitempty"`
+	EtcdCAFile               string         `json:"etcdCAFile,omitempty"`
+	EtcdCertFile             string         `json:"etcdCertFile,omitempty"`
+	EtcdKeyFile              string         `json:"etcdKeyFile,omitempty"`
+	AdmissionControl         []string       `json:"admissionControl,omitempty"`
+	AuthorizationMode        string         `json:"authorizationMode,omitempty"`
+	AuthorizationWebhookFile string         `json:"authorizationWebhookFile,omitempty"`
+	AuditLogPath             string         `json:"auditLogPath,omitempty"`
+	StorageClasses           []StorageClass `json:"storageClasses,omitempty"`
+	PodCIDR                  string         `json:"podCIDR,omitempty"`
+	ServiceCIDR              string         `json:"serviceCIDR,omitempty"`
+	DNSIP                    string         `json:"dnsIP,omitempty"`
+	ProxyMode                string         `json:"proxyMode,omitempty"`
+	MasqueradeAll            bool           `json:"masqueradeAll,omitempty"`
+	ClusterCIDR              string         `json:"clusterCIDR,omitempty"`
+	// ExtraConfig are component.key=value options, as in --extra-config.
+	ExtraConfig []string `json:"extraConfig,omitempty"`
+}
+
+// StorageClass is the spec of a storage class, see storageclass.StorageClassConfig.
+type StorageClass struct {
+	Name              string `json:"name"`
+	Dir               string `json:"dir,omitempty"`
+	ReclaimPolicy     string `json:"reclaimPolicy,omitempty"`
+	VolumeBindingMode string `json:"volumeBindingMode,omitempty"`
+	Default           bool   `json:"default,omitempty"`
+}
+
+// New returns the spec of a cluster created with the machine and Kubernetes configs, the addon
+// states and the mount. The node IP isn't part of the spec, as the driver assigns it.
+func New(m cluster.MachineConfig, k cluster.KubernetesConfig, addons map[string]bool, mount string) *Cluster {
+	c := &Cluster{
+		APIVersion: APIVersion,
+		Kind:       Kind,
+		Machine: Machine{
+			ISOURL:              m.MinikubeISO,
+			Memory:              m.Memory,
+			CPUs:                m.CPUs,
+			DiskSize:            formatDiskSize(m.DiskSize),
+			VMDriver:            m.VMDriver,
+			XhyveDiskDriver:     m.XhyveDiskDriver,
+			DockerEnv:           m.DockerEnv,
+			DockerOpt:           m.DockerOpt,
+			InsecureRegistry:    m.InsecureRegistry,
+			RegistryMirror:      m.RegistryMirror,
+			HostOnlyCIDR:        m.HostOnlyCIDR,
+			HypervVirtualSwitch: m.HypervVirtualSwitch,
+			KvmNetwork:          m.KvmNetwork,
+			DisableDriverMounts: m.DisableDriverMounts,
+		},
+		Kubernetes: Kubernetes{
+			Version:                  k.KubernetesVersion,
+			APIServerName:            k.APIServerName,
+			APIServerExtraSANs:       k.APIServerExtraSANs,
+			DNSDomain:                k.DNSDomain,
+			ContainerRuntime:         k.ContainerRuntime,
+			NetworkPlugin:            k.NetworkPlugin,
+			FeatureGates:             k.FeatureGates,
+			StorageBackend:           k.StorageBackend,
+			EtcdServers:              k.EtcdServers,
+			EtcdCAFile:               k.EtcdCAFile,
+			EtcdCertFile:             k.EtcdCertFile,
+			EtcdKeyFile:              k.EtcdKeyFile,
+			AdmissionControl:         k.AdmissionControl,
+			AuthorizationMode:        k.AuthorizationMode,
+			AuthorizationWebhookFile: k.AuthorizationWebhookFile,
+			AuditLogPath:             k.AuditLogPath,
+			PodCIDR:                  k.PodCIDR,
+			ServiceCIDR:              k.ServiceCIDR,
+			DNSIP:                    k.DNSIP,
+			ProxyMode:                k.ProxyMode,
+			MasqueradeAll:            k.MasqueradeAll,
+			ClusterCIDR:              k.ClusterCIDR,
+		},
+		Addons: addons,
+		Mount:  mount,
+	}
+	for _, s := range k.StorageClasses {
+		c.Kubernetes.StorageClasses = append(c.Kubernetes.StorageClasses, StorageClass{
+			Name:              s.Name,
+			Dir:               s.Dir,
+			ReclaimPolicy:     s.ReclaimPolicy,
+			VolumeBindingMode: s.VolumeBindingMode,
+			Default:           s.Default,
+		})
+	}
+	for _, o := range k.ExtraOptions {
+		c.Kubernetes.ExtraConfig = append(c.Kubernetes.ExtraConfig, o.String())
+	}
+	return c
+}
+
+// GetMachineConfig returns the machine config of the spec, which New created the spec from.
+func (c *Cluster) GetMachineConfig() (cluster.MachineConfig, error) {
+	m := c.Machine
+	diskSize := 0
+	if m.DiskSize != "" {
+		size, err := units.FromHumanSize(m.DiskSize)
+		if err != nil {
+			return cluster.MachineConfig{}, errors.Wrap(err, "Invalid disk size")
+		}
+		diskSize = int(size / units.MB)
+	}
+	return cluster.MachineConfig{
+		MinikubeISO:         m.ISOURL,
+		Memory:              m.Memory,
+		CPUs:                m.CPUs,
+		DiskSize:            diskSize,
+		VMDriver:            m.VMDriver,
+		XhyveDiskDriver:     m.XhyveDiskDriver,
+		DockerEnv:           m.DockerEnv,
+		DockerOpt:           m.DockerOpt,
+		InsecureRegistry:    m.InsecureRegistry,
+		RegistryMirror:      m.RegistryMirror,
+		HostOnlyCIDR:        m.HostOnlyCIDR,
+		HypervVirtualSwitch: m.HypervVirtualSwitch,
+		KvmNetwork:          m.KvmNetwork,
+		Downloader:          util.DefaultDownloader{},
+		DisableDriverMounts: m.DisableDriverMounts,
+	}, nil
+}
+
+// GetKubernetesConfig returns the Kubernetes config of the spec, which New created the spec from.
+func (c *Cluster) GetKubernetesConfig() (cluster.KubernetesConfig, error) {
+	k := c.Kubernetes
+	storageClasses, err := storageclass.ParseStorageClasses(c.GetStorageClasses())
+	if err != nil {
+		return cluster.KubernetesConfig{}, err
+	}
+	options := util.ExtraOptionSlice{}
+	for _, o := range k.ExtraConfig {
+		if err := options.Set(o); err != nil {
+			return cluster.KubernetesConfig{}, err
+		}
+	}
+	return cluster.KubernetesConfig{
+		KubernetesVersion:        k.Version,
+		APIServerName:            k.APIServerName,
+		APIServerExtraSANs:       k.APIServerExtraSANs,
+		DNSDomain:                k.DNSDomain,
+		ContainerRuntime:         k.ContainerRuntime,
+		NetworkPlugin:            k.NetworkPlugin,
+		FeatureGates:             k.FeatureGates,
+		StorageBackend:           k.StorageBackend,
+		EtcdServers:              k.EtcdServers,
+		EtcdCAFile:               k.EtcdCAFile,
+		EtcdCertFile:             k.EtcdCertFile,
+		EtcdKeyFile:              k.EtcdKeyFile,
+		AdmissionControl:         k.AdmissionControl,
+		AuthorizationMode:        k.AuthorizationMode,
+		AuthorizationWebhookFile: k.AuthorizationWebhookFile,
+		AuditLogPath:             k.AuditLogPath,
+		StorageClasses:           storageClasses,
+		PodCIDR:                  k.PodCIDR,
+		ServiceCIDR:              k.ServiceCIDR,
+		DNSIP:                    k.DNSIP,
+		ProxyMode:                k.ProxyMode,
+		MasqueradeAll:            k.MasqueradeAll,
+		ClusterCIDR:              k.ClusterCIDR,
+		ExtraOptions:             options,
+	}, nil
+}
+
+// formatDiskSize returns a disk size in MB in the format of --disk-size, which uses decimal units.
+func formatDiskSize(mb int) string {
+	if mb == 0 {
+		return ""
+	}
+	if mb%1000 == 0 {
+		return fmt.Sprintf("%dg", mb/1000)
+	}
+	return fmt.Sprintf("%dm", mb)
+}
+
+// GetStorageClasses returns the storage classes of the spec in the format of the storage-classes
+// setting of the minikube config.
+func (c *Cluster) GetStorageClasses() string {
+	classes := []storageclass.StorageClassConfig{}
+	for _, s := range c.Kubernetes.StorageClasses {
+		classes = append(classes, storageclass.StorageClassConfig{
+			Name:              s.Name,
+			Dir:               s.Dir,
+			ReclaimPolicy:     s.ReclaimPolicy,
+			VolumeBindingMode: s.VolumeBindingMode,
+			Default:           s.Default,
+		})
+	}
+	return storageclass.FormatStorageClasses(classes)
+}
+
+// Parse parses a YAML or JSON spec. It returns an error listing every problem found, including
+// fields the schema doesn't have, so that typos aren't silently ignored.
+func Parse(data []byte) (*Cluster, error) {
+	j, err := yaml.YAMLToJSON(data)
+	if err != nil {
+		return nil, errors.Wrap(err, "Error parsing cluster spec")
+	}
+	c := &Cluster{}
+	if err := json.Unmarshal(j, c); err != nil {
+		return nil, errors.Wrap(err, "Error parsing cluster spec")
+	}
+	var raw interface{}
+	if err := json.Unmarshal(j, &raw); err != nil {
+		return nil, errors.Wrap(err, "Error parsing cluster spec")
+	}
+
+	m := util.MultiError{}
+	for _, f := range unknownFields(raw, reflect.TypeOf(*c), "") {
+		m.Collect(fmt.Errorf("unknown field %s", f))
+	}
+	m.Collect(c.validate())
+	if err := m.ToError(); err != nil {
+		return nil, errors.Wrap(err, "Invalid cluster spec")
+	}
+	return c, nil
+}
+
+// validate checks the version of the spec and the fields that minikube start can't check once
+// they are set on its flags.
+func (c *Cluster) validate() error {
+	m := util.MultiError{}
+	if c.APIVersion != APIVersion {
+		m.Collect(fmt.Errorf("unsupported apiVersion %q, expected %s", c.APIVersion, APIVersion))
+	}
+	if c.Kind != Kind {
+		m.Collect(fmt.Errorf("unsupported kind %q, expected %s", c.Kind, Kind))
+	}
+	addons := []string{}
+	for name := range c.Addons {
+		addons = append(addons, name)
+	}
+	sort.Strings(addons)
+	for _, name := range addons {
+		if _, ok := assets.Addons[name]; !ok {
+			m.Collect(fmt.Errorf("unknown addon %s", name))
+		}
+	}
+	if c.Mount != "" && !strings.Contains(c.Mount, ":") {
+		m.Collect(fmt.Errorf("invalid mount %q, must be host-dir:vm-dir", c.Mount))
+	}
+	options := util.ExtraOptionSlice{}
+	for _, o := range c.Kubernetes.ExtraConfig {
+		m.Collect(options.Set(o))
+	}
+	if _, err := storageclass.ParseStorageClasses(c.GetStorageClasses()); err != nil {
+		m.Collect(err)
+	}
+	return m.ToError()
+}
+
+// unknownFields returns the paths of the keys of the decoded JSON value that don't match a field
+// of t, which encoding/json silently ignores.
+func unknownFields(value interface{}, t reflect.Type, path string) []string {
+	switch t.Kind() {
+	case reflect.Ptr:
+		return unknownFields(value, t.Elem(), path)
+	case reflect.Slice:
+		items, ok := value.([]interface{})
+		if !ok {
+			return nil
+		}
+		unknown := []string{}
+		for i, item := range items {
+			unknown = append(unknown, unknownFields(item, t.Elem(), fmt.Sprintf("%s[%d]", path, i))...)
+		}
+		return unknown
+	case reflect.Struct:
+		object, ok := value.(map[string]interface{})
+		if !ok {
+			return nil
+		}
+		fields := map[string]reflect.Type{}
+		for i := 0; i < t.NumField(); i++ {
+			name := strings.Split(t.Field(i).Tag.Get("json"), ",")[0]
+			fields[name] = t.Field(i).Type
+		}
+		keys := []string{}
+		for key := range object {
+			keys = append(keys, key)
+		}
+		sort.Strings(keys)
+		unknown := []string{}
+		for _, key := range keys {
+			fieldPath := key
+			if path != "" {
+				fieldPath = path + "." + key
+			}
+			ft, ok := fields[key]
+			if !ok {
+				unknown = append(unknown, fieldPath)
+				continue
+			}
+			unknown = append(unknown, unknownFields(object[key], ft, fieldPath)...)
+		}
+		return unknown
+	}
+	return nil
+}
+
+// Encode returns the spec as YAML, or as JSON if format is "json".
+func (c *Cluster) Encode(format string) ([]byte, error) {
+	switch format {
+	case "yaml":
+		return yaml.Marshal(c)
+	case "json":
+		data, err := json.MarshalIndent(c, "", "  ")
+		if err != nil {
+			return nil, err
+		}
+		return append(data, '\n'), nil
+	}
+	return nil, fmt.Errorf("unsupported format %q, must be yaml or json", format)
+}
+
+// ReadFile reads and parses the spec in the file.
+func ReadFile(path string) (*Cluster, error) {
+	data, err := ioutil.ReadFile(path)
+	if err != nil {
+		return nil, errors.Wrap(err, "Error reading cluster spec")
+	}
+	return Parse(data)
+}
+
+// getProfileDir returns the directory of the files minikube keeps for a profile, besides its machine.
+func getProfileDir(machineName string) string {
+	return constants.MakeMiniPath("profiles", machineName)
+}
+
+// Save saves the spec a machine was started with in its profile directory, for Load.
+func Save(machineName string, c *Cluster) error {
+	data, err := c.Encode("yaml")
+	if err != nil {
+		return errors.Wrap(err, "Error encoding cluster spec")
+	}
+	path := filepath.Join(getProfileDir(machineName), fileName)
+	if err := os.MkdirAll(filepath.Dir(path), 0700); err != nil {
+		return errors.Wrap(err, "Error creating profile directory")
+	}
+	if err := ioutil.WriteFile(path, data, 0644); err != nil {
+		return errors.Wrap(err, "Error saving cluster spec")
+	}
+	return nil
+}
+
+// Load returns the spec the machine was last started with, or nil if none is saved.
+func Load(machineName string) (*Cluster, error) {
+	c, err := ReadFile(filepath.Join(getProfileDir(machineName), fileName))
+	if os.IsNotExist(errors.Cause(err)) {
+		return nil, nil
+	}
+	return c, err
+}
+
+// Delete deletes the profile directory of the machine.
+func Delete(machineName string) error {
+	return os.RemoveAll(getProfileDir(machineName))
+}
+
+// Change is a setting that differs between two specs.
+type Change struct {
+	Field    string
+	Old, New string
+	// RequiresDelete is set for the settings that are only applied when the cluster is created.
+	RequiresDelete bool
+	// Warning explains what else changes along with the setting, if anything.
+	Warning string
+}
+
+// recreatedKubernetesFields are the Kubernetes settings that existing clusters keep using, like the
+// settings of the VM: the services keep the cluster IPs they were given from the service range,
+// and kube-dns its IP.
+var recreatedKubernetesFields = map[string]bool{
+	"kubernetes.serviceCIDR": true,
+	"kubernetes.dnsIP":       true,
+}
+
+// changeWarnings explain the Kubernetes settings that a cluster can change to, but that leave
+// the cluster in a different state than the one it was running with.
+var changeWarnings = map[string]string{
+	"kubernetes.storageBackend": "the apiserver only sees the state stored with the new backend. Switching to etcd3 copies the etcd2 state the first time only, and what changes with etcd3 isn't copied back to etcd2.",
+}
+
+// Diff returns the settings of the machine, Kubernetes and mount that differ between the specs.
+// Addons aren't compared, as they are enabled and disabled on running clusters.
+func Diff(old, new *Cluster) []Change {
+	changes := diffFields("machine", reflect.ValueOf(old.Machine), reflect.ValueOf(new.Machine), true)
+	changes = append(changes, diffFields("kubernetes", reflect.ValueOf(old.Kubernetes), reflect.ValueOf(new.Kubernetes), false)...)
+	if old.Mount != new.Mount {
+		changes = append(changes, Change{Field: "mount", Old: formatValue(old.Mount), New: formatValue(new.Mount)})
+	}
+	return changes
+}
+
+func diffFields(prefix string, old, new reflect.Value, requiresDelete bool) []Change {
+	changes := []Change{}
+	for i := 0; i < old.NumField(); i++ {
+		o, n := old.Field(i).Interface(), new.Field(i).Interface()
+		if old.Field(i).Kind() == reflect.Slice && old.Field(i).Len() == 0 && new.Field(i).Len() == 0 {
+			continue
+		}
+		if reflect.DeepEqual(o, n) {
+			continue
+		}
+		field := prefix + "." + strings.Split(old.Type().Field(i).Tag.Get("json"), ",")[0]
+		changes = append(changes, Change{
+			Field:          field,
+			Old:            formatValue(o),
+			New:            formatValue(n),
+			RequiresDelete: requiresDelete || recreatedKubernetesFields[field],
+			Warning:        changeWarnings[field],
+		})
+	}
+	return changes
+}
+
+// formatValue returns the value as it is written in a JSON spec.
+func formatValue(v interface{}) string {
+	if reflect.ValueOf(v).Kind() == reflect.Slice && reflect.ValueOf(v).Len() == 0 {
+		return "[]"
+	}
+	data, err := json.Marshal(v)
+	if err != nil {
+		return fmt.Sprintf("%v", v)
+	}
+	return string(data)
+}
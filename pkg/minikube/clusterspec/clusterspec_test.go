@@ -0,0 +1,238 @@
+/*
+Copyright 2016 The Kubernetes Authors All rights reserved.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package clusterspec
+
+import (
+	"os"
+	"reflect"
+	"strings"
+	"testing"
+
+	"k8s.io/minikube/pkg/minikube/cluster"
+	"k8s.io/minikube/pkg/minikube/storageclass"
+	"k8s.io/minikube/pkg/minikube/tests"
+	"k8s.io/minikube/pkg/util"
+)
+
+func testCluster() *Cluster {
+	m := cluster.MachineConfig{
+		Memory:           4096,
+		CPUs:             4,
+		DiskSize:         20000,
+		VMDriver:         "kvm",
+		DockerEnv:        []string{"HTTP_PROXY=http://proxy:3128"},
+		InsecureRegistry: []string{"10.0.0.0/24"},
+	}
+	k := cluster.KubernetesConfig{
+		KubernetesVersion: "v1.7.5",
+		NodeIP:            "192.168.99.100",
+		FeatureGates:      "PersistentLocalVolumes=true",
+		StorageClasses:    []storageclass.StorageClassConfig{{Name: "fast", Dir: "/data/fast", ReclaimPolicy: "Retain", VolumeBindingMode: "Immediate", Default: true}},
+		ExtraOptions:      util.ExtraOptionSlice{{Component: "kubelet", Key: "MaxPods", Value: "50"}},
+	}
+	return New(m, k, map[string]bool{"dashboard": false, "ingress": true}, "/home/user:/data")
+}
+
+func TestEncodeParse(t *testing.T) {
+	c := testCluster()
+	if c.Machine.DiskSize != "20g" {
+		t.Errorf("Expected disk size 20g, got %s", c.Machine.DiskSize)
+	}
+	if c.Kubernetes.ExtraConfig[0] != "kubelet.MaxPods=50" {
+		t.Errorf("Expected extra config kubelet.MaxPods=50, got %s", c.Kubernetes.ExtraConfig)
+	}
+	if classes := c.GetStorageClasses(); classes != "fast:dir=/data/fast,reclaimPolicy=Retain,default=true" {
+		t.Errorf("Unexpected storage classes %q", classes)
+	}
+
+	for _, format := range []string{"yaml", "json"} {
+		t.Run(format, func(t *testing.T) {
+			data, err := c.Encode(format)
+			if err != nil {
+				t.Fatalf("Error encoding spec: %s", err)
+			}
+			parsed, err := Parse(data)
+			if err != nil {
+				t.Fatalf("Error parsing spec: %s\n%s", err, data)
+			}
+			if !reflect.DeepEqual(parsed, c) {
+				t.Errorf("Expected %+v, got %+v", c, parsed)
+			}
+		})
+	}
+
+	if _, err := c.Encode("toml"); err == nil {
+		t.Errorf("Expected an error encoding an unsupported format")
+	}
+}
+
+func TestParse(t *testing.T) {
+	var tests = []struct {
+		description string
+		spec        string
+		errors      []string
+	}{
+		{
+			description: "minimal",
+			spec:        "apiVersion: minikube.k8s.io/v1alpha1\nkind: Cluster\n",
+		},
+		{
+			description: "json",
+			spec:        `{"apiVersion": "minikube.k8s.io/v1alpha1", "kind": "Cluster", "machine": {"memory": 4096}}`,
+		},
+		{
+			description: "unsupported version",
+			spec:        "apiVersion: minikube.k8s.io/v2\nkind: Cluster\n",
+			errors:      []string{`unsupported apiVersion "minikube.k8s.io/v2"`},
+		},
+		{
+			description: "unknown fields",
+			spec: `apiVersion: minikube.k8s.io/v1alpha1
+kind: Cluster
+machine:
+  memroy: 4096
+kubernetes:
+  storageClasses:
+  - name: fast
+    size: 1Gi
+`,
+			errors: []string{"unknown field kubernetes.storageClasses[0].size", "unknown field machine.memroy"},
+		},
+		{
+			description: "invalid values",
+			spec: `apiVersion: minikube.k8s.io/v1alpha1
+kind: Cluster
+kubernetes:
+  extraConfig: [kubelet]
+  storageClasses:
+  - name: standard
+addons:
+  dashbaord: true
+mount: /home/user
+`,
+			errors: []string{
+				"unknown addon dashbaord",
+				`invalid mount "/home/user"`,
+				"Value must contain at least one period: kubelet",
+				"Storage class standard is installed by the default-storageclass addon",
+			},
+		},
+		{
+			description: "wrong type",
+			spec:        "apiVersion: minikube.k8s.io/v1alpha1\nkind: Cluster\nmachine:\n  memory: lots\n",
+			errors:      []string{"Error parsing cluster spec"},
+		},
+	}
+
+	for _, test := range tests {
+		t.Run(test.description, func(t *testing.T) {
+			_, err := Parse([]byte(test.spec))
+			if err != nil && len(test.errors) == 0 {
+				t.Fatalf("Unexpected error: %s", err)
+			}
+			if err == nil && len(test.errors) > 0 {
+				t.Fatalf("Expected errors %v", test.errors)
+			}
+			for _, expected := range test.errors {
+				if !strings.Contains(err.Error(), expected) {
+					t.Errorf("Expected the error to contain %q, got: %s", expected, err)
+				}
+			}
+		})
+	}
+}
+
+func TestGetConfigs(t *testing.T) {
+	c := testCluster()
+	m, err := c.GetMachineConfig()
+	if err != nil {
+		t.Fatalf("Error getting machine config: %s", err)
+	}
+	if m.DiskSize != 20000 || m.Memory != 4096 || m.VMDriver != "kvm" || !reflect.DeepEqual(m.DockerEnv, []string{"HTTP_PROXY=http://proxy:3128"}) {
+		t.Errorf("Unexpected machine config %+v", m)
+	}
+
+	k, err := c.GetKubernetesConfig()
+	if err != nil {
+		t.Fatalf("Error getting Kubernetes config: %s", err)
+	}
+	expected := testCluster()
+	if !reflect.DeepEqual(New(m, k, expected.Addons, expected.Mount), expected) {
+		t.Errorf("Expected the configs to create the same spec, got %+v, %+v", m, k)
+	}
+}
+
+func TestSaveLoad(t *testing.T) {
+	tempDir := tests.MakeTempDir()
+	defer os.RemoveAll(tempDir)
+
+	if c, err := Load("minikube"); c != nil || err != nil {
+		t.Errorf("Expected no spec to be saved, got %+v, %v", c, err)
+	}
+
+	c := testCluster()
+	if err := Save("minikube", c); err != nil {
+		t.Fatalf("Error saving spec: %s", err)
+	}
+	loaded, err := Load("minikube")
+	if err != nil {
+		t.Fatalf("Error loading spec: %s", err)
+	}
+	if !reflect.DeepEqual(loaded, c) {
+		t.Errorf("Expected %+v, got %+v", c, loaded)
+	}
+
+	if err := Delete("minikube"); err != nil {
+		t.Fatalf("Error deleting profile: %s", err)
+	}
+	if c, err := Load("minikube"); c != nil || err != nil {
+		t.Errorf("Expected the spec to be deleted, got %+v, %v", c, err)
+	}
+}
+
+func TestDiff(t *testing.T) {
+	old := testCluster()
+	if changes := Diff(old, testCluster()); len(changes) != 0 {
+		t.Errorf("Expected no changes, got %+v", changes)
+	}
+
+	new := testCluster()
+	new.Machine.Memory = 8192
+	new.Machine.DockerEnv = nil
+	new.Kubernetes.Version = "v1.8.0"
+	new.Kubernetes.ExtraConfig = append(new.Kubernetes.ExtraConfig, "apiserver.EnableSwaggerUI=true")
+	new.Kubernetes.NetworkPlugin = "cni"
+	new.Kubernetes.StorageBackend = "etcd3"
+	new.Kubernetes.ServiceCIDR = "172.30.0.0/24"
+	new.Kubernetes.DNSIP = "172.30.0.10"
+	new.Addons = nil
+	new.Mount = ""
+	expected := []Change{
+		{Field: "machine.memory", Old: "4096", New: "8192", RequiresDelete: true},
+		{Field: "machine.dockerEnv", Old: `["HTTP_PROXY=http://proxy:3128"]`, New: "[]", RequiresDelete: true},
+		{Field: "kubernetes.version", Old: `"v1.7.5"`, New: `"v1.8.0"`},
+		{Field: "kubernetes.networkPlugin", Old: `""`, New: `"cni"`},
+		{Field: "kubernetes.storageBackend", Old: `""`, New: `"etcd3"`, Warning: changeWarnings["kubernetes.storageBackend"]},
+		{Field: "kubernetes.serviceCIDR", Old: `""`, New: `"172.30.0.0/24"`, RequiresDelete: true},
+		{Field: "kubernetes.dnsIP", Old: `""`, New: `"172.30.0.10"`, RequiresDelete: true},
+		{Field: "kubernetes.extraConfig", Old: `["kubelet.MaxPods=50"]`, New: `["kubelet.MaxPods=50","apiserver.EnableSwaggerUI=true"]`},
+		{Field: "mount", Old: `"/home/user:/data"`, New: `""`},
+	}
+	if changes := Diff(old, new); !reflect.DeepEqual(changes, expected) {
+		t.Errorf("Expected changes %+v, got %+v", expected, changes)
+	}
+}
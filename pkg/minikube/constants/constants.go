@@ -66,6 +66,13 @@ const DefaultMachineName = "minikube"
 // The name of the default storage class provisioner
 const DefaultStorageClassProvisioner = "standard"
 
+// CustomStorageClassesAddon is the addon that installs the storage classes declared in the
+// StorageClassesConfig setting of the minikube config.
+const (
+	CustomStorageClassesAddon = "custom-storageclasses"
+	StorageClassesConfig      = "storage-classes"
+)
+
 // MakeMiniPath is a utility to calculate a relative path to our directory.
 func MakeMiniPath(fileName ...string) string {
 	args := []string{GetMinipath()}
@@ -75,6 +82,27 @@ func MakeMiniPath(fileName ...string) string {
 
 var MountProcessFileName = ".mount-process"
 
+// StorageBackends are the etcd storage backends the apiserver can use.
+var StorageBackends = []string{DefaultStorageBackend, "etcd3"}
+
+const DefaultStorageBackend = "etcd2"
+
+// The TLS files for an external etcd are copied into the VM's certificate directory with these names.
+const (
+	EtcdCAFileName   = "etcd-ca.crt"
+	EtcdCertFileName = "etcd-client.crt"
+	EtcdKeyFileName  = "etcd-client.key"
+)
+
+// AuthorizationModes are the apiserver authorization modes minikube start accepts.
+var AuthorizationModes = []string{DefaultAuthorizationMode, "RBAC", "Node", "Webhook"}
+
+// DefaultAuthorizationMode is the apiserver authorization mode when --authorization-mode isn't set.
+const DefaultAuthorizationMode = "AlwaysAllow"
+
+// The kubeconfig file of the authorization webhook is copied into the VM's localkube directory with this name.
+const AuthorizationWebhookFileName = "authorization-webhook.kubeconfig"
+
 // Only pass along these flags to localkube.
 var LogFlags = [...]string{
 	"v",
@@ -90,13 +118,20 @@ const (
 	MinimumDiskSizeMB   = 2000
 	DefaultVMDriver     = "virtualbox"
 	DefaultStatusFormat = "minikube: {{.MinikubeStatus}}\n" +
-		"localkube: {{.LocalkubeStatus}}\n" + "kubectl: {{.KubeconfigStatus}}\n"
-	DefaultAddonListFormat    = "- {{.AddonName}}: {{.AddonStatus}}\n"
-	DefaultConfigViewFormat   = "- {{.ConfigKey}}: {{.ConfigValue}}\n"
-	GithubMinikubeReleasesURL = "https://storage.googleapis.com/minikube/releases.json"
-	KubernetesVersionGCSURL   = "https://storage.googleapis.com/minikube/k8s_releases.json"
-	DefaultWait               = 20
-	DefaultInterval           = 6
+		"localkube: {{.LocalkubeStatus}}\n" +
+		"{{range .LocalkubeComponents}}  {{.Name}}: {{.Summary}}\n{{end}}" +
+		"{{range .Nodes}}node {{.Name}}: {{.MinikubeStatus}}, localkube: {{.LocalkubeStatus}}\n{{end}}" +
+		"kubectl: {{.KubeconfigStatus}}\n"
+	DefaultAddonListFormat          = "- {{.AddonName}}: {{.AddonStatus}}\n"
+	DefaultConfigViewFormat         = "- {{.ConfigKey}}: {{.ConfigValue}}\n"
+	DefaultSnapshotListFormat       = "- {{.Name}}: {{.Time}} ({{.Size}})\n"
+	DefaultAuditFormat              = "{{.Time}} {{.User}} {{.Verb}} {{.Resource}} {{.Namespace}} {{.URI}} {{.Response}}\n"
+	DefaultNodeListFormat           = "- {{.Name}}: {{.IP}} (minikube: {{.MinikubeStatus}}, localkube: {{.LocalkubeStatus}})\n"
+	DefaultVolumeSnapshotListFormat = "- {{.Name}}: {{.Namespace}}/{{.Claim}} {{.Time}} ({{.Capacity}})\n"
+	GithubMinikubeReleasesURL       = "https://storage.googleapis.com/minikube/releases.json"
+	KubernetesVersionGCSURL         = "https://storage.googleapis.com/minikube/k8s_releases.json"
+	DefaultWait                     = 20
+	DefaultInterval                 = 6
 )
 
 var DefaultIsoUrl = fmt.Sprintf("https://storage.googleapis.com/%s/minikube-%s.iso", minikubeVersion.GetIsoPath(), minikubeVersion.GetIsoVersion())
@@ -127,6 +162,8 @@ const (
 	LocalkubeServicePath = "/usr/lib/systemd/system/localkube.service"
 	LocalkubeRunning     = "active"
 	LocalkubeStopped     = "inactive"
+	LocalkubeUnhealthy   = "Unhealthy"
+	LocalkubePaused      = "Paused"
 )
 
 const (
@@ -0,0 +1,40 @@
+/*
+Copyright 2016 The Kubernetes Authors All rights reserved.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+// Package configtypes names the configuration types of the localkube components that extra config
+// is set on. It links the Kubernetes servers in, so only the generator of the extra config fields
+// and the tests import it, never the minikube binary.
+package configtypes
+
+import (
+	"github.com/coreos/etcd/etcdserver"
+	apiserveroptions "k8s.io/kubernetes/cmd/kube-apiserver/app/options"
+	cmoptions "k8s.io/kubernetes/cmd/kube-controller-manager/app/options"
+	kubeletoptions "k8s.io/kubernetes/cmd/kubelet/app/options"
+	"k8s.io/kubernetes/pkg/apis/componentconfig"
+	scheduleroptions "k8s.io/kubernetes/plugin/cmd/kube-scheduler/app/options"
+)
+
+// Components maps the localkube components that accept extra config to the type of the
+// configuration localkube sets it on. Only the types are used, so the pointers are nil.
+var Components = map[string]interface{}{
+	"apiserver":          (*apiserveroptions.ServerRunOptions)(nil),
+	"controller-manager": (*cmoptions.CMServer)(nil),
+	"kubelet":            (*kubeletoptions.KubeletServer)(nil),
+	"scheduler":          (*scheduleroptions.SchedulerServer)(nil),
+	"proxy":              (*componentconfig.KubeProxyConfiguration)(nil),
+	"etcd":               (*etcdserver.ServerConfig)(nil),
+}
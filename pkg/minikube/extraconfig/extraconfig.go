@@ -0,0 +1,78 @@
+/*
+Copyright 2016 The Kubernetes Authors All rights reserved.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package extraconfig
+
+import (
+	"fmt"
+	"sort"
+	"strings"
+
+	"k8s.io/minikube/pkg/util"
+)
+
+//go:generate go run ../../../hack/gen_extra_config_fields.go
+
+// Components returns the sorted names of the components that accept extra config.
+func Components() []string {
+	names := []string{}
+	for name := range componentFields {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+	return names
+}
+
+// ListFields returns the paths and types of every field the extra config of the component can set.
+func ListFields(component string) ([]util.FieldPath, error) {
+	fields, ok := componentFields[component]
+	if !ok {
+		return nil, unknownComponentError(component)
+	}
+	return fields, nil
+}
+
+// Validate returns an error listing every option that localkube would fail to set, either
+// because its component or key doesn't exist or because its value is of the wrong type. Keys
+// that don't exist come with suggestions of the keys that may have been meant.
+func Validate(options util.ExtraOptionSlice) error {
+	m := util.MultiError{}
+	for _, o := range options {
+		fields, ok := componentFields[o.Component]
+		if !ok {
+			m.Collect(fmt.Errorf("%s: %s", o.String(), unknownComponentError(o.Component)))
+			continue
+		}
+		err := util.ValidateField(o.Key, fields, o.Value)
+		if err == nil {
+			continue
+		}
+		suggestions := util.SuggestFieldPaths(o.Key, fields)
+		if len(suggestions) == 0 {
+			m.Collect(fmt.Errorf("%s: %s", o.String(), err))
+			continue
+		}
+		for i, s := range suggestions {
+			suggestions[i] = o.Component + "." + s
+		}
+		m.Collect(fmt.Errorf("%s: %s Did you mean %s?", o.String(), err, strings.Join(suggestions, " or ")))
+	}
+	return m.ToError()
+}
+
+func unknownComponentError(component string) error {
+	return fmt.Errorf("unknown component %q, expected one of %s", component, strings.Join(Components(), ", "))
+}
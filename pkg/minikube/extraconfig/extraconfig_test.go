@@ -0,0 +1,85 @@
+/*
+Copyright 2016 The Kubernetes Authors All rights reserved.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package extraconfig
+
+import (
+	"reflect"
+	"strings"
+	"testing"
+
+	"k8s.io/minikube/pkg/minikube/extraconfig/configtypes"
+	"k8s.io/minikube/pkg/util"
+)
+
+func TestValidate(t *testing.T) {
+	var tests = []struct {
+		option string
+		errMsg string
+	}{
+		{option: "kubelet.MaxPods=10"},
+		{option: "kubelet.ImageGCHighThresholdPercent=90"},
+		{option: "apiserver.Authentication.PasswordFile.BasicAuthFile=/etc/users.csv"},
+		{option: "controller-manager.NodeMonitorGracePeriod.Duration=1m"},
+		{option: "proxy.IPTables.SyncPeriod.Duration=10s"},
+		{option: "etcd.QuotaBackendBytes=4294967296"},
+		{option: "etcd.SnapCount=1000", errMsg: "Unable to find field by name: SnapCount"},
+		{option: "kubelet.MaxPod=10", errMsg: "Did you mean kubelet.MaxPods?"},
+		{option: "kubelet.maxpods=10", errMsg: "Did you mean kubelet.MaxPods?"},
+		{option: "kubelet.MaxPods=ten", errMsg: "Error converting input ten to an integer"},
+		{option: "scheduler.PolicyConfigFile=/etc/policy.json"},
+		{option: "dns.Domain=cluster.local", errMsg: `unknown component "dns"`},
+	}
+	for _, test := range tests {
+		options := util.ExtraOptionSlice{}
+		if err := options.Set(test.option); err != nil {
+			t.Fatalf("Error parsing %s: %s", test.option, err)
+		}
+		err := Validate(options)
+		if err != nil && (test.errMsg == "" || !strings.Contains(err.Error(), test.errMsg)) {
+			t.Errorf("Unexpected error validating %s: %s", test.option, err)
+		}
+		if err == nil && test.errMsg != "" {
+			t.Errorf("Expected an error validating %s", test.option)
+		}
+	}
+}
+
+func TestListFields(t *testing.T) {
+	for _, c := range Components() {
+		fields, err := ListFields(c)
+		if err != nil {
+			t.Fatalf("Error listing the fields of %s: %s", c, err)
+		}
+		if len(fields) == 0 {
+			t.Errorf("Expected %s to have settable fields", c)
+		}
+	}
+	if _, err := ListFields("dns"); err == nil {
+		t.Errorf("Expected an error listing the fields of an unknown component")
+	}
+}
+
+func TestComponentFieldsUpToDate(t *testing.T) {
+	if len(componentFields) != len(configtypes.Components) {
+		t.Errorf("Expected the fields of %d components, got %d. Run go generate ./pkg/minikube/extraconfig", len(configtypes.Components), len(componentFields))
+	}
+	for c, config := range configtypes.Components {
+		if !reflect.DeepEqual(componentFields[c], util.ListFields(config)) {
+			t.Errorf("The fields of %s are out of date. Run go generate ./pkg/minikube/extraconfig", c)
+		}
+	}
+}
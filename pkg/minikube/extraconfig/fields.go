@@ -0,0 +1,411 @@
+// Code generated by hack/gen_extra_config_fields.go. DO NOT EDIT.
+
+package extraconfig
+
+import (
+	"reflect"
+
+	"k8s.io/minikube/pkg/util"
+)
+
+// componentFields are the fields the extra config of each localkube component can set.
+var componentFields = map[string][]util.FieldPath{
+	"apiserver": {
+		{Path: "Admission.ConfigFile", Type: "string", Kind: reflect.String},
+		{Path: "Admission.PluginNames", Type: "[]string", Kind: reflect.Slice},
+		{Path: "AllowPrivileged", Type: "bool", Kind: reflect.Bool},
+		{Path: "Audit.LogOptions.MaxAge", Type: "int", Kind: reflect.Int},
+		{Path: "Audit.LogOptions.MaxBackups", Type: "int", Kind: reflect.Int},
+		{Path: "Audit.LogOptions.MaxSize", Type: "int", Kind: reflect.Int},
+		{Path: "Audit.LogOptions.Path", Type: "string", Kind: reflect.String},
+		{Path: "Audit.PolicyFile", Type: "string", Kind: reflect.String},
+		{Path: "Audit.WebhookOptions.ConfigFile", Type: "string", Kind: reflect.String},
+		{Path: "Audit.WebhookOptions.Mode", Type: "string", Kind: reflect.String},
+		{Path: "Authentication.Anonymous.Allow", Type: "bool", Kind: reflect.Bool},
+		{Path: "Authentication.AnyToken.Allow", Type: "bool", Kind: reflect.Bool},
+		{Path: "Authentication.BootstrapToken.Allow", Type: "bool", Kind: reflect.Bool},
+		{Path: "Authentication.ClientCert.ClientCA", Type: "string", Kind: reflect.String},
+		{Path: "Authentication.Keystone.CAFile", Type: "string", Kind: reflect.String},
+		{Path: "Authentication.Keystone.URL", Type: "string", Kind: reflect.String},
+		{Path: "Authentication.OIDC.CAFile", Type: "string", Kind: reflect.String},
+		{Path: "Authentication.OIDC.ClientID", Type: "string", Kind: reflect.String},
+		{Path: "Authentication.OIDC.GroupsClaim", Type: "string", Kind: reflect.String},
+		{Path: "Authentication.OIDC.IssuerURL", Type: "string", Kind: reflect.String},
+		{Path: "Authentication.OIDC.UsernameClaim", Type: "string", Kind: reflect.String},
+		{Path: "Authentication.PasswordFile.BasicAuthFile", Type: "string", Kind: reflect.String},
+		{Path: "Authentication.RequestHeader.AllowedNames", Type: "[]string", Kind: reflect.Slice},
+		{Path: "Authentication.RequestHeader.ClientCAFile", Type: "string", Kind: reflect.String},
+		{Path: "Authentication.RequestHeader.ExtraHeaderPrefixes", Type: "[]string", Kind: reflect.Slice},
+		{Path: "Authentication.RequestHeader.GroupHeaders", Type: "[]string", Kind: reflect.Slice},
+		{Path: "Authentication.RequestHeader.UsernameHeaders", Type: "[]string", Kind: reflect.Slice},
+		{Path: "Authentication.ServiceAccounts.KeyFiles", Type: "[]string", Kind: reflect.Slice},
+		{Path: "Authentication.ServiceAccounts.Lookup", Type: "bool", Kind: reflect.Bool},
+		{Path: "Authentication.TokenFile.TokenFile", Type: "string", Kind: reflect.String},
+		{Path: "Authentication.WebHook.CacheTTL", Type: "time.Duration", Kind: reflect.Int64},
+		{Path: "Authentication.WebHook.ConfigFile", Type: "string", Kind: reflect.String},
+		{Path: "Authorization.Mode", Type: "string", Kind: reflect.String},
+		{Path: "Authorization.PolicyFile", Type: "string", Kind: reflect.String},
+		{Path: "Authorization.WebhookCacheAuthorizedTTL", Type: "time.Duration", Kind: reflect.Int64},
+		{Path: "Authorization.WebhookCacheUnauthorizedTTL", Type: "time.Duration", Kind: reflect.Int64},
+		{Path: "Authorization.WebhookConfigFile", Type: "string", Kind: reflect.String},
+		{Path: "CloudProvider.CloudConfigFile", Type: "string", Kind: reflect.String},
+		{Path: "CloudProvider.CloudProvider", Type: "string", Kind: reflect.String},
+		{Path: "EnableAggregatorRouting", Type: "bool", Kind: reflect.Bool},
+		{Path: "EnableLogsHandler", Type: "bool", Kind: reflect.Bool},
+		{Path: "Etcd.DefaultStorageMediaType", Type: "string", Kind: reflect.String},
+		{Path: "Etcd.DefaultWatchCacheSize", Type: "int", Kind: reflect.Int},
+		{Path: "Etcd.DeleteCollectionWorkers", Type: "int", Kind: reflect.Int},
+		{Path: "Etcd.EnableGarbageCollection", Type: "bool", Kind: reflect.Bool},
+		{Path: "Etcd.EnableWatchCache", Type: "bool", Kind: reflect.Bool},
+		{Path: "Etcd.EncryptionProviderConfigFilepath", Type: "string", Kind: reflect.String},
+		{Path: "Etcd.EtcdServersOverrides", Type: "[]string", Kind: reflect.Slice},
+		{Path: "Etcd.StorageConfig.CAFile", Type: "string", Kind: reflect.String},
+		{Path: "Etcd.StorageConfig.CertFile", Type: "string", Kind: reflect.String},
+		{Path: "Etcd.StorageConfig.DeserializationCacheSize", Type: "int", Kind: reflect.Int},
+		{Path: "Etcd.StorageConfig.KeyFile", Type: "string", Kind: reflect.String},
+		{Path: "Etcd.StorageConfig.Prefix", Type: "string", Kind: reflect.String},
+		{Path: "Etcd.StorageConfig.Quorum", Type: "bool", Kind: reflect.Bool},
+		{Path: "Etcd.StorageConfig.ServerList", Type: "[]string", Kind: reflect.Slice},
+		{Path: "Etcd.StorageConfig.Type", Type: "string", Kind: reflect.String},
+		{Path: "EventTTL", Type: "time.Duration", Kind: reflect.Int64},
+		{Path: "Features.EnableContentionProfiling", Type: "bool", Kind: reflect.Bool},
+		{Path: "Features.EnableProfiling", Type: "bool", Kind: reflect.Bool},
+		{Path: "Features.EnableSwaggerUI", Type: "bool", Kind: reflect.Bool},
+		{Path: "GenericServerRunOptions.AdvertiseAddress", Type: "net.IP", Kind: reflect.Slice},
+		{Path: "GenericServerRunOptions.CorsAllowedOriginList", Type: "[]string", Kind: reflect.Slice},
+		{Path: "GenericServerRunOptions.ExternalHost", Type: "string", Kind: reflect.String},
+		{Path: "GenericServerRunOptions.MaxMutatingRequestsInFlight", Type: "int", Kind: reflect.Int},
+		{Path: "GenericServerRunOptions.MaxRequestsInFlight", Type: "int", Kind: reflect.Int},
+		{Path: "GenericServerRunOptions.MinRequestTimeout", Type: "int", Kind: reflect.Int},
+		{Path: "GenericServerRunOptions.TargetRAMMB", Type: "int", Kind: reflect.Int},
+		{Path: "GenericServerRunOptions.WatchCacheSizes", Type: "[]string", Kind: reflect.Slice},
+		{Path: "InsecureServing.BindAddress", Type: "net.IP", Kind: reflect.Slice},
+		{Path: "InsecureServing.BindPort", Type: "int", Kind: reflect.Int},
+		{Path: "KubeletConfig.BearerToken", Type: "string", Kind: reflect.String},
+		{Path: "KubeletConfig.CAFile", Type: "string", Kind: reflect.String},
+		{Path: "KubeletConfig.CertFile", Type: "string", Kind: reflect.String},
+		{Path: "KubeletConfig.EnableHttps", Type: "bool", Kind: reflect.Bool},
+		{Path: "KubeletConfig.HTTPTimeout", Type: "time.Duration", Kind: reflect.Int64},
+		{Path: "KubeletConfig.Insecure", Type: "bool", Kind: reflect.Bool},
+		{Path: "KubeletConfig.KeyFile", Type: "string", Kind: reflect.String},
+		{Path: "KubeletConfig.PreferredAddressTypes", Type: "[]string", Kind: reflect.Slice},
+		{Path: "KubeletConfig.ServerName", Type: "string", Kind: reflect.String},
+		{Path: "KubernetesServiceNodePort", Type: "int", Kind: reflect.Int},
+		{Path: "MasterCount", Type: "int", Kind: reflect.Int},
+		{Path: "MaxConnectionBytesPerSec", Type: "int64", Kind: reflect.Int64},
+		{Path: "ProxyClientCertFile", Type: "string", Kind: reflect.String},
+		{Path: "ProxyClientKeyFile", Type: "string", Kind: reflect.String},
+		{Path: "SSHKeyfile", Type: "string", Kind: reflect.String},
+		{Path: "SSHUser", Type: "string", Kind: reflect.String},
+		{Path: "SecureServing.BindAddress", Type: "net.IP", Kind: reflect.Slice},
+		{Path: "SecureServing.BindPort", Type: "int", Kind: reflect.Int},
+		{Path: "SecureServing.ServerCert.CACertFile", Type: "string", Kind: reflect.String},
+		{Path: "SecureServing.ServerCert.CertDirectory", Type: "string", Kind: reflect.String},
+		{Path: "SecureServing.ServerCert.CertKey.CertFile", Type: "string", Kind: reflect.String},
+		{Path: "SecureServing.ServerCert.CertKey.KeyFile", Type: "string", Kind: reflect.String},
+		{Path: "SecureServing.ServerCert.PairName", Type: "string", Kind: reflect.String},
+		{Path: "ServiceClusterIPRange", Type: "net.IPNet", Kind: reflect.Struct},
+		{Path: "ServiceNodePortRange", Type: "net.PortRange", Kind: reflect.Struct},
+		{Path: "StorageSerialization.DefaultStorageVersions", Type: "string", Kind: reflect.String},
+		{Path: "StorageSerialization.StorageVersions", Type: "string", Kind: reflect.String},
+	},
+	"controller-manager": {
+		{Path: "APIVersion", Type: "string", Kind: reflect.String},
+		{Path: "Address", Type: "string", Kind: reflect.String},
+		{Path: "AllocateNodeCIDRs", Type: "bool", Kind: reflect.Bool},
+		{Path: "CIDRAllocatorType", Type: "string", Kind: reflect.String},
+		{Path: "CloudConfigFile", Type: "string", Kind: reflect.String},
+		{Path: "CloudProvider", Type: "string", Kind: reflect.String},
+		{Path: "ClusterCIDR", Type: "string", Kind: reflect.String},
+		{Path: "ClusterName", Type: "string", Kind: reflect.String},
+		{Path: "ClusterSigningCertFile", Type: "string", Kind: reflect.String},
+		{Path: "ClusterSigningDuration.Duration", Type: "time.Duration", Kind: reflect.Int64},
+		{Path: "ClusterSigningKeyFile", Type: "string", Kind: reflect.String},
+		{Path: "ConcurrentDaemonSetSyncs", Type: "int32", Kind: reflect.Int32},
+		{Path: "ConcurrentDeploymentSyncs", Type: "int32", Kind: reflect.Int32},
+		{Path: "ConcurrentEndpointSyncs", Type: "int32", Kind: reflect.Int32},
+		{Path: "ConcurrentGCSyncs", Type: "int32", Kind: reflect.Int32},
+		{Path: "ConcurrentJobSyncs", Type: "int32", Kind: reflect.Int32},
+		{Path: "ConcurrentNamespaceSyncs", Type: "int32", Kind: reflect.Int32},
+		{Path: "ConcurrentRCSyncs", Type: "int32", Kind: reflect.Int32},
+		{Path: "ConcurrentRSSyncs", Type: "int32", Kind: reflect.Int32},
+		{Path: "ConcurrentResourceQuotaSyncs", Type: "int32", Kind: reflect.Int32},
+		{Path: "ConcurrentSATokenSyncs", Type: "int32", Kind: reflect.Int32},
+		{Path: "ConcurrentServiceSyncs", Type: "int32", Kind: reflect.Int32},
+		{Path: "ConfigureCloudRoutes", Type: "bool", Kind: reflect.Bool},
+		{Path: "ContentType", Type: "string", Kind: reflect.String},
+		{Path: "ControllerStartInterval.Duration", Type: "time.Duration", Kind: reflect.Int64},
+		{Path: "Controllers", Type: "[]string", Kind: reflect.Slice},
+		{Path: "DeletingPodsBurst", Type: "int32", Kind: reflect.Int32},
+		{Path: "DeletingPodsQps", Type: "float32", Kind: reflect.Float32},
+		{Path: "DeploymentControllerSyncPeriod.Duration", Type: "time.Duration", Kind: reflect.Int64},
+		{Path: "DisableAttachDetachReconcilerSync", Type: "bool", Kind: reflect.Bool},
+		{Path: "EnableContentionProfiling", Type: "bool", Kind: reflect.Bool},
+		{Path: "EnableGarbageCollector", Type: "bool", Kind: reflect.Bool},
+		{Path: "EnableProfiling", Type: "bool", Kind: reflect.Bool},
+		{Path: "EnableTaintManager", Type: "bool", Kind: reflect.Bool},
+		{Path: "HorizontalPodAutoscalerDownscaleForbiddenWindow.Duration", Type: "time.Duration", Kind: reflect.Int64},
+		{Path: "HorizontalPodAutoscalerSyncPeriod.Duration", Type: "time.Duration", Kind: reflect.Int64},
+		{Path: "HorizontalPodAutoscalerUpscaleForbiddenWindow.Duration", Type: "time.Duration", Kind: reflect.Int64},
+		{Path: "HorizontalPodAutoscalerUseRESTClients", Type: "bool", Kind: reflect.Bool},
+		{Path: "Kind", Type: "string", Kind: reflect.String},
+		{Path: "KubeAPIBurst", Type: "int32", Kind: reflect.Int32},
+		{Path: "KubeAPIQPS", Type: "float32", Kind: reflect.Float32},
+		{Path: "Kubeconfig", Type: "string", Kind: reflect.String},
+		{Path: "LargeClusterSizeThreshold", Type: "int32", Kind: reflect.Int32},
+		{Path: "LeaderElection.LeaderElect", Type: "bool", Kind: reflect.Bool},
+		{Path: "LeaderElection.LeaseDuration.Duration", Type: "time.Duration", Kind: reflect.Int64},
+		{Path: "LeaderElection.RenewDeadline.Duration", Type: "time.Duration", Kind: reflect.Int64},
+		{Path: "LeaderElection.ResourceLock", Type: "string", Kind: reflect.String},
+		{Path: "LeaderElection.RetryPeriod.Duration", Type: "time.Duration", Kind: reflect.Int64},
+		{Path: "LookupCacheSizeForDaemonSet", Type: "int32", Kind: reflect.Int32},
+		{Path: "LookupCacheSizeForRC", Type: "int32", Kind: reflect.Int32},
+		{Path: "LookupCacheSizeForRS", Type: "int32", Kind: reflect.Int32},
+		{Path: "Master", Type: "string", Kind: reflect.String},
+		{Path: "MinResyncPeriod.Duration", Type: "time.Duration", Kind: reflect.Int64},
+		{Path: "NamespaceSyncPeriod.Duration", Type: "time.Duration", Kind: reflect.Int64},
+		{Path: "NodeCIDRMaskSize", Type: "int32", Kind: reflect.Int32},
+		{Path: "NodeEvictionRate", Type: "float32", Kind: reflect.Float32},
+		{Path: "NodeMonitorGracePeriod.Duration", Type: "time.Duration", Kind: reflect.Int64},
+		{Path: "NodeMonitorPeriod.Duration", Type: "time.Duration", Kind: reflect.Int64},
+		{Path: "NodeStartupGracePeriod.Duration", Type: "time.Duration", Kind: reflect.Int64},
+		{Path: "NodeSyncPeriod.Duration", Type: "time.Duration", Kind: reflect.Int64},
+		{Path: "PVClaimBinderSyncPeriod.Duration", Type: "time.Duration", Kind: reflect.Int64},
+		{Path: "PodEvictionTimeout.Duration", Type: "time.Duration", Kind: reflect.Int64},
+		{Path: "Port", Type: "int32", Kind: reflect.Int32},
+		{Path: "ReconcilerSyncLoopPeriod.Duration", Type: "time.Duration", Kind: reflect.Int64},
+		{Path: "RegisterRetryCount", Type: "int32", Kind: reflect.Int32},
+		{Path: "ResourceQuotaSyncPeriod.Duration", Type: "time.Duration", Kind: reflect.Int64},
+		{Path: "RootCAFile", Type: "string", Kind: reflect.String},
+		{Path: "RouteReconciliationPeriod.Duration", Type: "time.Duration", Kind: reflect.Int64},
+		{Path: "SecondaryNodeEvictionRate", Type: "float32", Kind: reflect.Float32},
+		{Path: "ServiceAccountKeyFile", Type: "string", Kind: reflect.String},
+		{Path: "ServiceCIDR", Type: "string", Kind: reflect.String},
+		{Path: "ServiceSyncPeriod.Duration", Type: "time.Duration", Kind: reflect.Int64},
+		{Path: "TerminatedPodGCThreshold", Type: "int32", Kind: reflect.Int32},
+		{Path: "UnhealthyZoneThreshold", Type: "float32", Kind: reflect.Float32},
+		{Path: "UseServiceAccountCredentials", Type: "bool", Kind: reflect.Bool},
+		{Path: "VolumeConfiguration.EnableDynamicProvisioning", Type: "bool", Kind: reflect.Bool},
+		{Path: "VolumeConfiguration.EnableHostPathProvisioning", Type: "bool", Kind: reflect.Bool},
+		{Path: "VolumeConfiguration.FlexVolumePluginDir", Type: "string", Kind: reflect.String},
+		{Path: "VolumeConfiguration.PersistentVolumeRecyclerConfiguration.IncrementTimeoutHostPath", Type: "int32", Kind: reflect.Int32},
+		{Path: "VolumeConfiguration.PersistentVolumeRecyclerConfiguration.IncrementTimeoutNFS", Type: "int32", Kind: reflect.Int32},
+		{Path: "VolumeConfiguration.PersistentVolumeRecyclerConfiguration.MaximumRetry", Type: "int32", Kind: reflect.Int32},
+		{Path: "VolumeConfiguration.PersistentVolumeRecyclerConfiguration.MinimumTimeoutHostPath", Type: "int32", Kind: reflect.Int32},
+		{Path: "VolumeConfiguration.PersistentVolumeRecyclerConfiguration.MinimumTimeoutNFS", Type: "int32", Kind: reflect.Int32},
+		{Path: "VolumeConfiguration.PersistentVolumeRecyclerConfiguration.PodTemplateFilePathHostPath", Type: "string", Kind: reflect.String},
+		{Path: "VolumeConfiguration.PersistentVolumeRecyclerConfiguration.PodTemplateFilePathNFS", Type: "string", Kind: reflect.String},
+	},
+	"etcd": {
+		{Path: "AutoCompactionRetention", Type: "int", Kind: reflect.Int},
+		{Path: "BootstrapTimeout", Type: "time.Duration", Kind: reflect.Int64},
+		{Path: "ClientCertAuthEnabled", Type: "bool", Kind: reflect.Bool},
+		{Path: "DataDir", Type: "string", Kind: reflect.String},
+		{Path: "DedicatedWALDir", Type: "string", Kind: reflect.String},
+		{Path: "DiscoveryProxy", Type: "string", Kind: reflect.String},
+		{Path: "DiscoveryURL", Type: "string", Kind: reflect.String},
+		{Path: "ElectionTicks", Type: "int", Kind: reflect.Int},
+		{Path: "ForceNewCluster", Type: "bool", Kind: reflect.Bool},
+		{Path: "InitialClusterToken", Type: "string", Kind: reflect.String},
+		{Path: "Name", Type: "string", Kind: reflect.String},
+		{Path: "NewCluster", Type: "bool", Kind: reflect.Bool},
+		{Path: "PeerTLSInfo.CAFile", Type: "string", Kind: reflect.String},
+		{Path: "PeerTLSInfo.CertFile", Type: "string", Kind: reflect.String},
+		{Path: "PeerTLSInfo.ClientCertAuth", Type: "bool", Kind: reflect.Bool},
+		{Path: "PeerTLSInfo.KeyFile", Type: "string", Kind: reflect.String},
+		{Path: "PeerTLSInfo.ServerName", Type: "string", Kind: reflect.String},
+		{Path: "PeerTLSInfo.TrustedCAFile", Type: "string", Kind: reflect.String},
+		{Path: "QuotaBackendBytes", Type: "int64", Kind: reflect.Int64},
+		{Path: "StrictReconfigCheck", Type: "bool", Kind: reflect.Bool},
+	},
+	"kubelet": {
+		{Path: "APIServerList", Type: "[]string", Kind: reflect.Slice},
+		{Path: "APIVersion", Type: "string", Kind: reflect.String},
+		{Path: "Address", Type: "string", Kind: reflect.String},
+		{Path: "AllowPrivileged", Type: "bool", Kind: reflect.Bool},
+		{Path: "AllowedUnsafeSysctls", Type: "[]string", Kind: reflect.Slice},
+		{Path: "Authentication.Anonymous.Enabled", Type: "bool", Kind: reflect.Bool},
+		{Path: "Authentication.Webhook.CacheTTL.Duration", Type: "time.Duration", Kind: reflect.Int64},
+		{Path: "Authentication.Webhook.Enabled", Type: "bool", Kind: reflect.Bool},
+		{Path: "Authentication.X509.ClientCAFile", Type: "string", Kind: reflect.String},
+		{Path: "Authorization.Mode", Type: "componentconfig.KubeletAuthorizationMode", Kind: reflect.String},
+		{Path: "Authorization.Webhook.CacheAuthorizedTTL.Duration", Type: "time.Duration", Kind: reflect.Int64},
+		{Path: "Authorization.Webhook.CacheUnauthorizedTTL.Duration", Type: "time.Duration", Kind: reflect.Int64},
+		{Path: "BootstrapKubeconfig", Type: "string", Kind: reflect.String},
+		{Path: "CAdvisorPort", Type: "int32", Kind: reflect.Int32},
+		{Path: "CNIBinDir", Type: "string", Kind: reflect.String},
+		{Path: "CNIConfDir", Type: "string", Kind: reflect.String},
+		{Path: "CPUCFSQuota", Type: "bool", Kind: reflect.Bool},
+		{Path: "CertDirectory", Type: "string", Kind: reflect.String},
+		{Path: "CgroupDriver", Type: "string", Kind: reflect.String},
+		{Path: "CgroupRoot", Type: "string", Kind: reflect.String},
+		{Path: "CgroupsPerQOS", Type: "bool", Kind: reflect.Bool},
+		{Path: "ChaosChance", Type: "float64", Kind: reflect.Float64},
+		{Path: "CloudConfigFile", Type: "string", Kind: reflect.String},
+		{Path: "CloudProvider", Type: "string", Kind: reflect.String},
+		{Path: "ClusterDNS", Type: "[]string", Kind: reflect.Slice},
+		{Path: "ClusterDomain", Type: "string", Kind: reflect.String},
+		{Path: "ContainerRuntime", Type: "string", Kind: reflect.String},
+		{Path: "Containerized", Type: "bool", Kind: reflect.Bool},
+		{Path: "ContentType", Type: "string", Kind: reflect.String},
+		{Path: "DockerDisableSharedPID", Type: "bool", Kind: reflect.Bool},
+		{Path: "DockerEndpoint", Type: "string", Kind: reflect.String},
+		{Path: "DockerExecHandlerName", Type: "string", Kind: reflect.String},
+		{Path: "DockershimRootDirectory", Type: "string", Kind: reflect.String},
+		{Path: "EnableContentionProfiling", Type: "bool", Kind: reflect.Bool},
+		{Path: "EnableControllerAttachDetach", Type: "bool", Kind: reflect.Bool},
+		{Path: "EnableCustomMetrics", Type: "bool", Kind: reflect.Bool},
+		{Path: "EnableDebuggingHandlers", Type: "bool", Kind: reflect.Bool},
+		{Path: "EnableServer", Type: "bool", Kind: reflect.Bool},
+		{Path: "EnforceNodeAllocatable", Type: "[]string", Kind: reflect.Slice},
+		{Path: "EventBurst", Type: "int32", Kind: reflect.Int32},
+		{Path: "EventRecordQPS", Type: "int32", Kind: reflect.Int32},
+		{Path: "EvictionHard", Type: "string", Kind: reflect.String},
+		{Path: "EvictionMaxPodGracePeriod", Type: "int32", Kind: reflect.Int32},
+		{Path: "EvictionMinimumReclaim", Type: "string", Kind: reflect.String},
+		{Path: "EvictionPressureTransitionPeriod.Duration", Type: "time.Duration", Kind: reflect.Int64},
+		{Path: "EvictionSoft", Type: "string", Kind: reflect.String},
+		{Path: "EvictionSoftGracePeriod", Type: "string", Kind: reflect.String},
+		{Path: "ExitOnLockContention", Type: "bool", Kind: reflect.Bool},
+		{Path: "ExperimentalCheckNodeCapabilitiesBeforeMount", Type: "bool", Kind: reflect.Bool},
+		{Path: "ExperimentalDockershim", Type: "bool", Kind: reflect.Bool},
+		{Path: "ExperimentalFailSwapOn", Type: "bool", Kind: reflect.Bool},
+		{Path: "ExperimentalKernelMemcgNotification", Type: "bool", Kind: reflect.Bool},
+		{Path: "ExperimentalMounterPath", Type: "string", Kind: reflect.String},
+		{Path: "ExperimentalNodeAllocatableIgnoreEvictionThreshold", Type: "bool", Kind: reflect.Bool},
+		{Path: "FeatureGates", Type: "string", Kind: reflect.String},
+		{Path: "FileCheckFrequency.Duration", Type: "time.Duration", Kind: reflect.Int64},
+		{Path: "HTTPCheckFrequency.Duration", Type: "time.Duration", Kind: reflect.Int64},
+		{Path: "HairpinMode", Type: "string", Kind: reflect.String},
+		{Path: "HealthzBindAddress", Type: "string", Kind: reflect.String},
+		{Path: "HealthzPort", Type: "int32", Kind: reflect.Int32},
+		{Path: "HostIPCSources", Type: "[]string", Kind: reflect.Slice},
+		{Path: "HostNetworkSources", Type: "[]string", Kind: reflect.Slice},
+		{Path: "HostPIDSources", Type: "[]string", Kind: reflect.Slice},
+		{Path: "HostnameOverride", Type: "string", Kind: reflect.String},
+		{Path: "IPTablesDropBit", Type: "int32", Kind: reflect.Int32},
+		{Path: "IPTablesMasqueradeBit", Type: "int32", Kind: reflect.Int32},
+		{Path: "ImageGCHighThresholdPercent", Type: "int32", Kind: reflect.Int32},
+		{Path: "ImageGCLowThresholdPercent", Type: "int32", Kind: reflect.Int32},
+		{Path: "ImageMinimumGCAge.Duration", Type: "time.Duration", Kind: reflect.Int64},
+		{Path: "ImagePullProgressDeadline.Duration", Type: "time.Duration", Kind: reflect.Int64},
+		{Path: "KeepTerminatedPodVolumes", Type: "bool", Kind: reflect.Bool},
+		{Path: "Kind", Type: "string", Kind: reflect.String},
+		{Path: "KubeAPIBurst", Type: "int32", Kind: reflect.Int32},
+		{Path: "KubeAPIQPS", Type: "int32", Kind: reflect.Int32},
+		{Path: "KubeReservedCgroup", Type: "string", Kind: reflect.String},
+		{Path: "KubeletCgroups", Type: "string", Kind: reflect.String},
+		{Path: "LockFilePath", Type: "string", Kind: reflect.String},
+		{Path: "LowDiskSpaceThresholdMB", Type: "int32", Kind: reflect.Int32},
+		{Path: "MakeIPTablesUtilChains", Type: "bool", Kind: reflect.Bool},
+		{Path: "ManifestURL", Type: "string", Kind: reflect.String},
+		{Path: "ManifestURLHeader", Type: "string", Kind: reflect.String},
+		{Path: "MasterServiceNamespace", Type: "string", Kind: reflect.String},
+		{Path: "MaxContainerCount", Type: "int32", Kind: reflect.Int32},
+		{Path: "MaxOpenFiles", Type: "int64", Kind: reflect.Int64},
+		{Path: "MaxPerPodContainerCount", Type: "int32", Kind: reflect.Int32},
+		{Path: "MaxPods", Type: "int32", Kind: reflect.Int32},
+		{Path: "MinimumGCAge.Duration", Type: "time.Duration", Kind: reflect.Int64},
+		{Path: "NetworkPluginDir", Type: "string", Kind: reflect.String},
+		{Path: "NetworkPluginMTU", Type: "int32", Kind: reflect.Int32},
+		{Path: "NetworkPluginName", Type: "string", Kind: reflect.String},
+		{Path: "NodeIP", Type: "string", Kind: reflect.String},
+		{Path: "NodeStatusUpdateFrequency.Duration", Type: "time.Duration", Kind: reflect.Int64},
+		{Path: "NonMasqueradeCIDR", Type: "string", Kind: reflect.String},
+		{Path: "OOMScoreAdj", Type: "int32", Kind: reflect.Int32},
+		{Path: "OutOfDiskTransitionFrequency.Duration", Type: "time.Duration", Kind: reflect.Int64},
+		{Path: "PodCIDR", Type: "string", Kind: reflect.String},
+		{Path: "PodManifestPath", Type: "string", Kind: reflect.String},
+		{Path: "PodSandboxImage", Type: "string", Kind: reflect.String},
+		{Path: "PodsPerCore", Type: "int32", Kind: reflect.Int32},
+		{Path: "Port", Type: "int32", Kind: reflect.Int32},
+		{Path: "ProtectKernelDefaults", Type: "bool", Kind: reflect.Bool},
+		{Path: "ProviderID", Type: "string", Kind: reflect.String},
+		{Path: "ReadOnlyPort", Type: "int32", Kind: reflect.Int32},
+		{Path: "ReallyCrashForTesting", Type: "bool", Kind: reflect.Bool},
+		{Path: "RegisterNode", Type: "bool", Kind: reflect.Bool},
+		{Path: "RegisterSchedulable", Type: "bool", Kind: reflect.Bool},
+		{Path: "RegistryBurst", Type: "int32", Kind: reflect.Int32},
+		{Path: "RegistryPullQPS", Type: "int32", Kind: reflect.Int32},
+		{Path: "RemoteImageEndpoint", Type: "string", Kind: reflect.String},
+		{Path: "RemoteRuntimeEndpoint", Type: "string", Kind: reflect.String},
+		{Path: "RequireKubeConfig", Type: "bool", Kind: reflect.Bool},
+		{Path: "ResolverConfig", Type: "string", Kind: reflect.String},
+		{Path: "RktAPIEndpoint", Type: "string", Kind: reflect.String},
+		{Path: "RktPath", Type: "string", Kind: reflect.String},
+		{Path: "RktStage1Image", Type: "string", Kind: reflect.String},
+		{Path: "RootDirectory", Type: "string", Kind: reflect.String},
+		{Path: "RunOnce", Type: "bool", Kind: reflect.Bool},
+		{Path: "RuntimeCgroups", Type: "string", Kind: reflect.String},
+		{Path: "RuntimeRequestTimeout.Duration", Type: "time.Duration", Kind: reflect.Int64},
+		{Path: "SeccompProfileRoot", Type: "string", Kind: reflect.String},
+		{Path: "SerializeImagePulls", Type: "bool", Kind: reflect.Bool},
+		{Path: "StreamingConnectionIdleTimeout.Duration", Type: "time.Duration", Kind: reflect.Int64},
+		{Path: "SyncFrequency.Duration", Type: "time.Duration", Kind: reflect.Int64},
+		{Path: "SystemCgroups", Type: "string", Kind: reflect.String},
+		{Path: "SystemReservedCgroup", Type: "string", Kind: reflect.String},
+		{Path: "TLSCertFile", Type: "string", Kind: reflect.String},
+		{Path: "TLSPrivateKeyFile", Type: "string", Kind: reflect.String},
+		{Path: "VolumePluginDir", Type: "string", Kind: reflect.String},
+		{Path: "VolumeStatsAggPeriod.Duration", Type: "time.Duration", Kind: reflect.Int64},
+	},
+	"proxy": {
+		{Path: "APIVersion", Type: "string", Kind: reflect.String},
+		{Path: "BindAddress", Type: "string", Kind: reflect.String},
+		{Path: "ClientConnection.AcceptContentTypes", Type: "string", Kind: reflect.String},
+		{Path: "ClientConnection.Burst", Type: "int", Kind: reflect.Int},
+		{Path: "ClientConnection.ContentType", Type: "string", Kind: reflect.String},
+		{Path: "ClientConnection.KubeConfigFile", Type: "string", Kind: reflect.String},
+		{Path: "ClientConnection.QPS", Type: "float32", Kind: reflect.Float32},
+		{Path: "ClusterCIDR", Type: "string", Kind: reflect.String},
+		{Path: "ConfigSyncPeriod.Duration", Type: "time.Duration", Kind: reflect.Int64},
+		{Path: "Conntrack.Max", Type: "int32", Kind: reflect.Int32},
+		{Path: "Conntrack.MaxPerCore", Type: "int32", Kind: reflect.Int32},
+		{Path: "Conntrack.Min", Type: "int32", Kind: reflect.Int32},
+		{Path: "Conntrack.TCPCloseWaitTimeout.Duration", Type: "time.Duration", Kind: reflect.Int64},
+		{Path: "Conntrack.TCPEstablishedTimeout.Duration", Type: "time.Duration", Kind: reflect.Int64},
+		{Path: "EnableProfiling", Type: "bool", Kind: reflect.Bool},
+		{Path: "FeatureGates", Type: "string", Kind: reflect.String},
+		{Path: "HealthzBindAddress", Type: "string", Kind: reflect.String},
+		{Path: "HostnameOverride", Type: "string", Kind: reflect.String},
+		{Path: "IPTables.MasqueradeAll", Type: "bool", Kind: reflect.Bool},
+		{Path: "IPTables.MasqueradeBit", Type: "int32", Kind: reflect.Int32},
+		{Path: "IPTables.MinSyncPeriod.Duration", Type: "time.Duration", Kind: reflect.Int64},
+		{Path: "IPTables.SyncPeriod.Duration", Type: "time.Duration", Kind: reflect.Int64},
+		{Path: "Kind", Type: "string", Kind: reflect.String},
+		{Path: "MetricsBindAddress", Type: "string", Kind: reflect.String},
+		{Path: "Mode", Type: "componentconfig.ProxyMode", Kind: reflect.String},
+		{Path: "OOMScoreAdj", Type: "int32", Kind: reflect.Int32},
+		{Path: "PortRange", Type: "string", Kind: reflect.String},
+		{Path: "ResourceContainer", Type: "string", Kind: reflect.String},
+		{Path: "UDPIdleTimeout.Duration", Type: "time.Duration", Kind: reflect.Int64},
+	},
+	"scheduler": {
+		{Path: "APIVersion", Type: "string", Kind: reflect.String},
+		{Path: "Address", Type: "string", Kind: reflect.String},
+		{Path: "AlgorithmProvider", Type: "string", Kind: reflect.String},
+		{Path: "ContentType", Type: "string", Kind: reflect.String},
+		{Path: "EnableContentionProfiling", Type: "bool", Kind: reflect.Bool},
+		{Path: "EnableProfiling", Type: "bool", Kind: reflect.Bool},
+		{Path: "FailureDomains", Type: "string", Kind: reflect.String},
+		{Path: "HardPodAffinitySymmetricWeight", Type: "int", Kind: reflect.Int},
+		{Path: "Kind", Type: "string", Kind: reflect.String},
+		{Path: "KubeAPIBurst", Type: "int32", Kind: reflect.Int32},
+		{Path: "KubeAPIQPS", Type: "float32", Kind: reflect.Float32},
+		{Path: "Kubeconfig", Type: "string", Kind: reflect.String},
+		{Path: "LeaderElection.LeaderElect", Type: "bool", Kind: reflect.Bool},
+		{Path: "LeaderElection.LeaseDuration.Duration", Type: "time.Duration", Kind: reflect.Int64},
+		{Path: "LeaderElection.RenewDeadline.Duration", Type: "time.Duration", Kind: reflect.Int64},
+		{Path: "LeaderElection.ResourceLock", Type: "string", Kind: reflect.String},
+		{Path: "LeaderElection.RetryPeriod.Duration", Type: "time.Duration", Kind: reflect.Int64},
+		{Path: "LockObjectName", Type: "string", Kind: reflect.String},
+		{Path: "LockObjectNamespace", Type: "string", Kind: reflect.String},
+		{Path: "Master", Type: "string", Kind: reflect.String},
+		{Path: "PolicyConfigFile", Type: "string", Kind: reflect.String},
+		{Path: "PolicyConfigMapName", Type: "string", Kind: reflect.String},
+		{Path: "PolicyConfigMapNamespace", Type: "string", Kind: reflect.String},
+		{Path: "Port", Type: "int32", Kind: reflect.Int32},
+		{Path: "SchedulerName", Type: "string", Kind: reflect.String},
+		{Path: "UseLegacyPolicyConfig", Type: "bool", Kind: reflect.Bool},
+	},
+}
@@ -0,0 +1,182 @@
+/*
+Copyright 2016 The Kubernetes Authors All rights reserved.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package metrics
+
+import (
+	"io"
+	"sort"
+	"time"
+
+	"github.com/pkg/errors"
+	dto "github.com/prometheus/client_model/go"
+	"github.com/prometheus/common/expfmt"
+	"k8s.io/minikube/pkg/util"
+)
+
+// ComponentSummary describes the metrics of a single localkube component.
+type ComponentSummary struct {
+	Name string
+	// Supervised is set for the components localkube starts and restarts, which Ready and
+	// Restarts are reported for.
+	Supervised bool
+	Ready      bool
+	Restarts   int
+	// Series is the number of time series of the component.
+	Series int
+}
+
+// Latency is the mean of one of the latency metrics of the cluster.
+type Latency struct {
+	Description string
+	Count       uint64
+	Mean        time.Duration
+}
+
+// Summary is an overview of the metrics localkube serves on util.LocalkubeMetricsPath.
+type Summary struct {
+	Components          []ComponentSummary
+	Latencies           []Latency
+	CPUSeconds          float64
+	ResidentMemoryBytes float64
+	Goroutines          float64
+}
+
+// latencyMetrics are the summaries and histograms that are averaged into Summary.Latencies, and
+// the unit of their values.
+var latencyMetrics = []struct {
+	description string
+	name        string
+	unit        time.Duration
+}{
+	{"apiserver requests", "apiserver_request_latencies_summary", time.Microsecond},
+	{"apiserver etcd requests", "etcd_request_latencies_summary", time.Microsecond},
+	{"etcd WAL fsyncs", "etcd_disk_wal_fsync_duration_seconds", time.Second},
+	{"etcd backend commits", "etcd_disk_backend_commit_duration_seconds", time.Second},
+	{"pod scheduling", "scheduler_e2e_scheduling_latency_microseconds", time.Microsecond},
+	{"pod starts", "kubelet_pod_start_latency_microseconds", time.Microsecond},
+	{"proxy rule syncs", "kubeproxy_sync_proxy_rules_latency_microseconds", time.Microsecond},
+}
+
+// Summarize reads metrics in the Prometheus text format and summarizes them. Latencies are only
+// included for the metrics that were observed at least once.
+func Summarize(r io.Reader) (*Summary, error) {
+	var parser expfmt.TextParser
+	families, err := parser.TextToMetricFamilies(r)
+	if err != nil {
+		return nil, errors.Wrap(err, "Error parsing metrics")
+	}
+
+	components := map[string]*ComponentSummary{}
+	component := func(name string) *ComponentSummary {
+		if _, ok := components[name]; !ok {
+			components[name] = &ComponentSummary{Name: name}
+		}
+		return components[name]
+	}
+	for _, f := range families {
+		for _, m := range f.GetMetric() {
+			c := component(getLabel(m, util.MetricsComponentLabel))
+			c.Series++
+			switch f.GetName() {
+			case "localkube_component_ready":
+				c.Supervised = true
+				c.Ready = getValue(m) == 1
+			case "localkube_component_restarts_total":
+				c.Supervised = true
+				c.Restarts = int(getValue(m))
+			}
+		}
+	}
+
+	summary := &Summary{
+		CPUSeconds:          sumValues(families["process_cpu_seconds_total"]),
+		ResidentMemoryBytes: sumValues(families["process_resident_memory_bytes"]),
+		Goroutines:          sumValues(families["go_goroutines"]),
+	}
+	for _, c := range components {
+		summary.Components = append(summary.Components, *c)
+	}
+	sort.Slice(summary.Components, func(i, j int) bool {
+		return summary.Components[i].Name < summary.Components[j].Name
+	})
+	for _, l := range latencyMetrics {
+		count, sum := sumObservations(families[l.name])
+		if count == 0 {
+			continue
+		}
+		summary.Latencies = append(summary.Latencies, Latency{
+			Description: l.description,
+			Count:       count,
+			Mean:        time.Duration(sum/float64(count)*float64(l.unit) + 0.5),
+		})
+	}
+	return summary, nil
+}
+
+func getLabel(m *dto.Metric, name string) string {
+	for _, l := range m.GetLabel() {
+		if l.GetName() == name {
+			return l.GetValue()
+		}
+	}
+	return ""
+}
+
+func getValue(m *dto.Metric) float64 {
+	switch {
+	case m.Gauge != nil:
+		return m.GetGauge().GetValue()
+	case m.Counter != nil:
+		return m.GetCounter().GetValue()
+	case m.Untyped != nil:
+		return m.GetUntyped().GetValue()
+	}
+	return 0
+}
+
+// sumValues returns the sum of the values of the gauges or counters of the family, which may be nil.
+func sumValues(f *dto.MetricFamily) float64 {
+	total := 0.0
+	if f == nil {
+		return total
+	}
+	for _, m := range f.GetMetric() {
+		total += getValue(m)
+	}
+	return total
+}
+
+// sumObservations returns the number and sum of the observations of the summaries or histograms
+// of the family, which may be nil.
+func sumObservations(f *dto.MetricFamily) (uint64, float64) {
+	var count uint64
+	sum := 0.0
+	if f == nil {
+		return count, sum
+	}
+	for _, m := range f.GetMetric() {
+		switch {
+		case m.Summary != nil:
+			count += m.GetSummary().GetSampleCount()
+			sum += m.GetSummary().GetSampleSum()
+		case m.Histogram != nil:
+			count += m.GetHistogram().GetSampleCount()
+			sum += m.GetHistogram().GetSampleSum()
+		}
+	}
+	return count, sum
+}
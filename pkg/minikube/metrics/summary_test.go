@@ -0,0 +1,85 @@
+/*
+Copyright 2016 The Kubernetes Authors All rights reserved.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package metrics
+
+import (
+	"reflect"
+	"strings"
+	"testing"
+	"time"
+)
+
+const testMetrics = `# TYPE apiserver_request_latencies_summary summary
+apiserver_request_latencies_summary{resource="pods",verb="GET",quantile="0.5",component="apiserver"} 1500
+apiserver_request_latencies_summary_sum{resource="pods",verb="GET",component="apiserver"} 3000
+apiserver_request_latencies_summary_count{resource="pods",verb="GET",component="apiserver"} 2
+apiserver_request_latencies_summary_sum{resource="nodes",verb="LIST",component="apiserver"} 9000
+apiserver_request_latencies_summary_count{resource="nodes",verb="LIST",component="apiserver"} 2
+# TYPE etcd_disk_wal_fsync_duration_seconds histogram
+etcd_disk_wal_fsync_duration_seconds_bucket{component="etcd",le="0.01"} 4
+etcd_disk_wal_fsync_duration_seconds_bucket{component="etcd",le="+Inf"} 4
+etcd_disk_wal_fsync_duration_seconds_sum{component="etcd"} 0.02
+etcd_disk_wal_fsync_duration_seconds_count{component="etcd"} 4
+# TYPE scheduler_e2e_scheduling_latency_microseconds summary
+scheduler_e2e_scheduling_latency_microseconds_sum{component="scheduler"} 0
+scheduler_e2e_scheduling_latency_microseconds_count{component="scheduler"} 0
+# TYPE go_goroutines gauge
+go_goroutines{component="localkube"} 1520
+# TYPE process_cpu_seconds_total counter
+process_cpu_seconds_total{component="localkube"} 12.5
+# TYPE process_resident_memory_bytes gauge
+process_resident_memory_bytes{component="localkube"} 3.145728e+08
+# TYPE localkube_component_ready gauge
+localkube_component_ready{component="apiserver"} 1
+localkube_component_ready{component="etcd"} 0
+# TYPE localkube_component_restarts_total counter
+localkube_component_restarts_total{component="apiserver"} 0
+localkube_component_restarts_total{component="etcd"} 3
+`
+
+func TestSummarize(t *testing.T) {
+	summary, err := Summarize(strings.NewReader(testMetrics))
+	if err != nil {
+		t.Fatalf("Error summarizing metrics: %s", err)
+	}
+
+	expectedComponents := []ComponentSummary{
+		{Name: "apiserver", Supervised: true, Ready: true, Restarts: 0, Series: 4},
+		{Name: "etcd", Supervised: true, Ready: false, Restarts: 3, Series: 3},
+		{Name: "localkube", Series: 3},
+		{Name: "scheduler", Series: 1},
+	}
+	if !reflect.DeepEqual(summary.Components, expectedComponents) {
+		t.Errorf("Expected components %+v, got %+v", expectedComponents, summary.Components)
+	}
+
+	expectedLatencies := []Latency{
+		{Description: "apiserver requests", Count: 4, Mean: 3 * time.Millisecond},
+		{Description: "etcd WAL fsyncs", Count: 4, Mean: 5 * time.Millisecond},
+	}
+	if !reflect.DeepEqual(summary.Latencies, expectedLatencies) {
+		t.Errorf("Expected latencies %+v, got %+v", expectedLatencies, summary.Latencies)
+	}
+
+	if summary.Goroutines != 1520 || summary.CPUSeconds != 12.5 || summary.ResidentMemoryBytes != 300*1024*1024 {
+		t.Errorf("Unexpected process metrics %+v", summary)
+	}
+
+	if _, err := Summarize(strings.NewReader("not metrics {")); err == nil {
+		t.Errorf("Expected an error summarizing invalid metrics")
+	}
+}
@@ -0,0 +1,220 @@
+/*
+Copyright 2016 The Kubernetes Authors All rights reserved.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package storageclass
+
+import (
+	"bytes"
+	"path"
+	"sort"
+	"strconv"
+	"strings"
+	"text/template"
+
+	"github.com/pkg/errors"
+	"k8s.io/apimachinery/pkg/util/validation"
+	"k8s.io/minikube/pkg/minikube/assets"
+	"k8s.io/minikube/pkg/minikube/constants"
+)
+
+// StorageClassConfig is a storage class declared in the minikube config, which the storage
+// provisioner in localkube serves.
+type StorageClassConfig struct {
+	Name string
+	// Dir is the directory in the VM the volumes of the class are created in. The provisioner's
+	// directory is used if it is empty.
+	Dir               string
+	ReclaimPolicy     string
+	VolumeBindingMode string
+	Default           bool
+}
+
+var reclaimPolicies = []string{"Delete", "Retain", "Recycle"}
+
+// ParseStorageClasses parses storage classes declared as a ';' separated list of
+// NAME[:key=value,...] entries, where the keys are dir, reclaimPolicy, volumeBindingMode and
+// default, e.g. "fast:dir=/data/fast,default=true;slow:reclaimPolicy=Retain".
+func ParseStorageClasses(spec string) ([]StorageClassConfig, error) {
+	classes := []StorageClassConfig{}
+	names := map[string]bool{}
+	hasDefault := false
+	for _, entry := range strings.Split(spec, ";") {
+		entry = strings.TrimSpace(entry)
+		if entry == "" {
+			continue
+		}
+		parts := strings.SplitN(entry, ":", 2)
+		class := StorageClassConfig{
+			Name:              parts[0],
+			ReclaimPolicy:     "Delete",
+			VolumeBindingMode: "Immediate",
+		}
+		if len(parts) == 2 {
+			for _, option := range strings.Split(parts[1], ",") {
+				if err := class.set(option); err != nil {
+					return nil, errors.Wrapf(err, "Error parsing storage class %s", class.Name)
+				}
+			}
+		}
+		if err := class.validate(); err != nil {
+			return nil, err
+		}
+		if names[class.Name] {
+			return nil, errors.Errorf("Storage class %s is declared more than once", class.Name)
+		}
+		names[class.Name] = true
+		if class.Default && hasDefault {
+			return nil, errors.New("Only one storage class can be the default")
+		}
+		hasDefault = hasDefault || class.Default
+		classes = append(classes, class)
+	}
+	return classes, nil
+}
+
+// FormatStorageClasses returns classes in the format ParseStorageClasses parses, leaving out
+// the options that have their default values.
+func FormatStorageClasses(classes []StorageClassConfig) string {
+	entries := []string{}
+	for _, c := range classes {
+		options := []string{}
+		if c.Dir != "" {
+			options = append(options, "dir="+c.Dir)
+		}
+		if c.ReclaimPolicy != "" && c.ReclaimPolicy != "Delete" {
+			options = append(options, "reclaimPolicy="+c.ReclaimPolicy)
+		}
+		if c.VolumeBindingMode != "" && c.VolumeBindingMode != "Immediate" {
+			options = append(options, "volumeBindingMode="+c.VolumeBindingMode)
+		}
+		if c.Default {
+			options = append(options, "default=true")
+		}
+		entry := c.Name
+		if len(options) > 0 {
+			entry += ":" + strings.Join(options, ",")
+		}
+		entries = append(entries, entry)
+	}
+	return strings.Join(entries, ";")
+}
+
+func (c *StorageClassConfig) set(option string) error {
+	kv := strings.SplitN(option, "=", 2)
+	if len(kv) != 2 {
+		return errors.Errorf("Invalid option %q, must be key=value", option)
+	}
+	switch kv[0] {
+	case "dir":
+		c.Dir = kv[1]
+	case "reclaimPolicy":
+		c.ReclaimPolicy = kv[1]
+	case "volumeBindingMode":
+		c.VolumeBindingMode = kv[1]
+	case "default":
+		b, err := strconv.ParseBool(kv[1])
+		if err != nil {
+			return errors.Wrap(err, "Error parsing default")
+		}
+		c.Default = b
+	default:
+		return errors.Errorf("Unknown option %s, must be one of dir, reclaimPolicy, volumeBindingMode, default", kv[0])
+	}
+	return nil
+}
+
+func (c StorageClassConfig) validate() error {
+	if errs := validation.IsDNS1123Subdomain(c.Name); len(errs) > 0 {
+		return errors.Errorf("Invalid storage class name %q: %s", c.Name, strings.Join(errs, ", "))
+	}
+	if c.Name == constants.DefaultStorageClassProvisioner {
+		return errors.Errorf("Storage class %s is installed by the default-storageclass addon", c.Name)
+	}
+	if c.Dir != "" && !path.IsAbs(c.Dir) {
+		return errors.Errorf("The directory of storage class %s must be an absolute path in the VM", c.Name)
+	}
+	valid := false
+	for _, p := range reclaimPolicies {
+		valid = valid || c.ReclaimPolicy == p
+	}
+	if !valid {
+		return errors.Errorf("Invalid reclaim policy %q of storage class %s, must be one of %v", c.ReclaimPolicy, c.Name, reclaimPolicies)
+	}
+	// The volumeBindingMode field was added to storage classes in Kubernetes 1.9
+	if c.VolumeBindingMode != "Immediate" {
+		return errors.Errorf("Invalid volume binding mode %q of storage class %s, only Immediate is supported by this Kubernetes version", c.VolumeBindingMode, c.Name)
+	}
+	return nil
+}
+
+// GetDefault returns the name of the default storage class, or "" if none of classes is the default.
+func GetDefault(classes []StorageClassConfig) string {
+	for _, c := range classes {
+		if c.Default {
+			return c.Name
+		}
+	}
+	return ""
+}
+
+// GetDirs returns the directories of classes as class=dir pairs for localkube's
+// --storage-class-dirs flag, sorted by class.
+func GetDirs(classes []StorageClassConfig) string {
+	dirs := []string{}
+	for _, c := range classes {
+		if c.Dir != "" {
+			dirs = append(dirs, c.Name+"="+c.Dir)
+		}
+	}
+	sort.Strings(dirs)
+	return strings.Join(dirs, ",")
+}
+
+var manifestTemplate = template.Must(template.New("storageClasses").Parse(`{{range .}}---
+kind: StorageClass
+apiVersion: storage.k8s.io/v1
+metadata:
+  name: {{.Name}}
+  annotations:
+    storageclass.beta.kubernetes.io/is-default-class: "{{.Default}}"
+  labels:
+    addonmanager.kubernetes.io/mode: Reconcile
+provisioner: k8s.io/minikube-hostpath
+parameters:
+  reclaimPolicy: {{.ReclaimPolicy}}
+{{end}}`))
+
+// GenerateManifest returns the StorageClass objects of classes, as installed by the
+// custom-storageclasses addon.
+func GenerateManifest(classes []StorageClassConfig) ([]byte, error) {
+	buf := bytes.Buffer{}
+	if err := manifestTemplate.Execute(&buf, classes); err != nil {
+		return nil, errors.Wrap(err, "Error generating storage classes")
+	}
+	return buf.Bytes(), nil
+}
+
+// LoadAddon sets the assets of the custom-storageclasses addon to the manifest of classes.
+func LoadAddon(classes []StorageClassConfig) error {
+	manifest, err := GenerateManifest(classes)
+	if err != nil {
+		return err
+	}
+	assets.Addons[constants.CustomStorageClassesAddon].Assets = []*assets.MemoryAsset{
+		assets.NewMemoryAssetFromBytes(manifest, constants.AddonsPath, "custom-storageclasses.yaml", "0640"),
+	}
+	return nil
+}
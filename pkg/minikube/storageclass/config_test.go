@@ -0,0 +1,117 @@
+/*
+Copyright 2016 The Kubernetes Authors All rights reserved.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package storageclass
+
+import (
+	"reflect"
+	"strings"
+	"testing"
+)
+
+func TestParseStorageClasses(t *testing.T) {
+	var tests = []struct {
+		description string
+		spec        string
+		expected    []StorageClassConfig
+		shouldErr   bool
+	}{
+		{
+			description: "empty",
+			spec:        "",
+			expected:    []StorageClassConfig{},
+		},
+		{
+			description: "defaults",
+			spec:        "slow",
+			expected:    []StorageClassConfig{{Name: "slow", ReclaimPolicy: "Delete", VolumeBindingMode: "Immediate"}},
+		},
+		{
+			description: "options",
+			spec:        "fast:dir=/data/fast,reclaimPolicy=Retain,default=true; slow",
+			expected: []StorageClassConfig{
+				{Name: "fast", Dir: "/data/fast", ReclaimPolicy: "Retain", VolumeBindingMode: "Immediate", Default: true},
+				{Name: "slow", ReclaimPolicy: "Delete", VolumeBindingMode: "Immediate"},
+			},
+		},
+		{description: "invalid name", spec: "Fast_Disk", shouldErr: true},
+		{description: "standard", spec: "standard", shouldErr: true},
+		{description: "relative dir", spec: "fast:dir=data", shouldErr: true},
+		{description: "unknown option", spec: "fast:size=1Gi", shouldErr: true},
+		{description: "missing value", spec: "fast:default", shouldErr: true},
+		{description: "invalid reclaim policy", spec: "fast:reclaimPolicy=Keep", shouldErr: true},
+		{description: "unsupported binding mode", spec: "fast:volumeBindingMode=WaitForFirstConsumer", shouldErr: true},
+		{description: "duplicate", spec: "fast;fast", shouldErr: true},
+		{description: "two defaults", spec: "fast:default=true;slow:default=true", shouldErr: true},
+	}
+
+	for _, test := range tests {
+		t.Run(test.description, func(t *testing.T) {
+			classes, err := ParseStorageClasses(test.spec)
+			if err != nil {
+				if !test.shouldErr {
+					t.Fatalf("Unexpected error: %s", err)
+				}
+				return
+			}
+			if test.shouldErr {
+				t.Fatalf("Expected an error, got %+v", classes)
+			}
+			if !reflect.DeepEqual(classes, test.expected) {
+				t.Errorf("Expected %+v, got %+v", test.expected, classes)
+			}
+		})
+	}
+}
+
+func TestFormatStorageClasses(t *testing.T) {
+	spec := "fast:dir=/data/fast,reclaimPolicy=Retain,default=true;slow"
+	classes, err := ParseStorageClasses(spec)
+	if err != nil {
+		t.Fatalf("Unexpected error: %s", err)
+	}
+	if formatted := FormatStorageClasses(classes); formatted != spec {
+		t.Errorf("Expected %q, got %q", spec, formatted)
+	}
+	if formatted := FormatStorageClasses(nil); formatted != "" {
+		t.Errorf("Expected no storage classes, got %q", formatted)
+	}
+}
+
+func TestGenerateManifest(t *testing.T) {
+	classes, err := ParseStorageClasses("fast:reclaimPolicy=Retain,default=true;slow")
+	if err != nil {
+		t.Fatalf("Error parsing storage classes: %s", err)
+	}
+	manifest, err := GenerateManifest(classes)
+	if err != nil {
+		t.Fatalf("Error generating manifest: %s", err)
+	}
+
+	for _, expected := range []string{
+		"  name: fast\n  annotations:\n    storageclass.beta.kubernetes.io/is-default-class: \"true\"",
+		"  name: slow\n  annotations:\n    storageclass.beta.kubernetes.io/is-default-class: \"false\"",
+		"  reclaimPolicy: Retain",
+		"provisioner: k8s.io/minikube-hostpath",
+	} {
+		if !strings.Contains(string(manifest), expected) {
+			t.Errorf("Expected manifest to contain %q, got:\n%s", expected, manifest)
+		}
+	}
+	if n := strings.Count(string(manifest), "kind: StorageClass"); n != 2 {
+		t.Errorf("Expected 2 storage classes, got %d", n)
+	}
+}
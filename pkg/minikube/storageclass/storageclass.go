@@ -18,6 +18,7 @@ package storageclass
 
 import (
 	"github.com/pkg/errors"
+	apierrors "k8s.io/apimachinery/pkg/api/errors"
 	meta_v1 "k8s.io/apimachinery/pkg/apis/meta/v1"
 	"k8s.io/client-go/kubernetes"
 
@@ -25,18 +26,26 @@ import (
 	"k8s.io/minikube/pkg/minikube/constants"
 )
 
-// DisableDefaultStorageClass disables the default storage class provisioner
-// The addon-manager and kubectl apply cannot delete storageclasses
-func DisableDefaultStorageClass() error {
+func getClient() (*kubernetes.Clientset, error) {
 	loadingRules := clientcmd.NewDefaultClientConfigLoadingRules()
 	kubeConfig := clientcmd.NewNonInteractiveDeferredLoadingClientConfig(loadingRules, &clientcmd.ConfigOverrides{})
 	config, err := kubeConfig.ClientConfig()
 	if err != nil {
-		return errors.Wrap(err, "Error creating kubeConfig")
+		return nil, errors.Wrap(err, "Error creating kubeConfig")
 	}
 	client, err := kubernetes.NewForConfig(config)
 	if err != nil {
-		return errors.Wrap(err, "Error creating new client from kubeConfig.ClientConfig()")
+		return nil, errors.Wrap(err, "Error creating new client from kubeConfig.ClientConfig()")
+	}
+	return client, nil
+}
+
+// DisableDefaultStorageClass disables the default storage class provisioner
+// The addon-manager and kubectl apply cannot delete storageclasses
+func DisableDefaultStorageClass() error {
+	client, err := getClient()
+	if err != nil {
+		return err
 	}
 
 	err = client.Storage().StorageClasses().Delete(constants.DefaultStorageClassProvisioner, &meta_v1.DeleteOptions{})
@@ -46,3 +55,19 @@ func DisableDefaultStorageClass() error {
 
 	return nil
 }
+
+// DeleteStorageClasses deletes the storage classes of classes that exist.
+func DeleteStorageClasses(classes []StorageClassConfig) error {
+	client, err := getClient()
+	if err != nil {
+		return err
+	}
+
+	for _, c := range classes {
+		err := client.Storage().StorageClasses().Delete(c.Name, &meta_v1.DeleteOptions{})
+		if err != nil && !apierrors.IsNotFound(err) {
+			return errors.Wrapf(err, "Error deleting storage class %s", c.Name)
+		}
+	}
+	return nil
+}
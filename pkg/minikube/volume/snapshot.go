@@ -0,0 +1,169 @@
+/*
+Copyright 2016 The Kubernetes Authors All rights reserved.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package volume
+
+import (
+	"encoding/json"
+	"sort"
+	"time"
+
+	"github.com/pkg/errors"
+	apierrors "k8s.io/apimachinery/pkg/api/errors"
+	"k8s.io/apimachinery/pkg/api/resource"
+	meta_v1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	corev1 "k8s.io/client-go/kubernetes/typed/core/v1"
+	"k8s.io/client-go/pkg/api/v1"
+	"k8s.io/kubernetes/pkg/client/retry"
+	"k8s.io/minikube/pkg/util"
+)
+
+var (
+	// snapshotTimeout is how long TakeSnapshot waits for the storage provisioner to copy a volume.
+	snapshotTimeout = 5 * time.Minute
+	// snapshotInterval is how often TakeSnapshot checks whether the snapshot was taken.
+	snapshotInterval = time.Second
+)
+
+// TakeSnapshot asks the storage provisioner for a snapshot named name of the volume of the claim,
+// and waits until the snapshot is taken.
+func TakeSnapshot(client corev1.CoreV1Interface, namespace, claim, name string) error {
+	existing, err := GetSnapshot(client, name)
+	if err != nil {
+		return err
+	}
+	if existing != nil {
+		return errors.Errorf("Snapshot %s already exists", name)
+	}
+
+	claims := client.PersistentVolumeClaims(namespace)
+	// Controllers may update the claim meanwhile, so the request is added to its latest version
+	err = retry.RetryOnConflict(retry.DefaultRetry, func() error {
+		pvc, err := claims.Get(claim, meta_v1.GetOptions{})
+		if err != nil {
+			return err
+		}
+		if pvc.Annotations == nil {
+			pvc.Annotations = map[string]string{}
+		}
+		pvc.Annotations[util.VolumeSnapshotRequestAnnotation] = name
+		_, err = claims.Update(pvc)
+		return err
+	})
+	if err != nil {
+		return errors.Wrapf(err, "Error requesting snapshot of claim %s", claim)
+	}
+
+	for deadline := time.Now().Add(snapshotTimeout); time.Now().Before(deadline); time.Sleep(snapshotInterval) {
+		pvc, err := claims.Get(claim, meta_v1.GetOptions{})
+		if err != nil {
+			return errors.Wrapf(err, "Error getting claim %s", claim)
+		}
+		if _, ok := pvc.Annotations[util.VolumeSnapshotRequestAnnotation]; ok {
+			continue
+		}
+		if pvc.Annotations[util.VolumeSnapshotTakenAnnotation] != name {
+			return errors.Errorf("Error taking snapshot %s: %s", name, pvc.Annotations[util.VolumeSnapshotErrorAnnotation])
+		}
+		return nil
+	}
+	return errors.Errorf("Timed out waiting for snapshot %s of claim %s", name, claim)
+}
+
+// GetSnapshot returns the snapshot named name, or nil if there is none.
+func GetSnapshot(client corev1.CoreV1Interface, name string) (*util.VolumeSnapshot, error) {
+	snapshots, err := ListSnapshots(client)
+	if err != nil {
+		return nil, err
+	}
+	for _, s := range snapshots {
+		if s.Name == name {
+			return &s, nil
+		}
+	}
+	return nil, nil
+}
+
+// ListSnapshots returns the snapshots taken by the storage provisioner, oldest first.
+func ListSnapshots(client corev1.CoreV1Interface) ([]util.VolumeSnapshot, error) {
+	cm, err := client.ConfigMaps(meta_v1.NamespaceSystem).Get(util.VolumeSnapshotsConfigMap, meta_v1.GetOptions{})
+	if apierrors.IsNotFound(err) {
+		return nil, nil
+	}
+	if err != nil {
+		return nil, errors.Wrap(err, "Error getting volume snapshots")
+	}
+
+	snapshots := []util.VolumeSnapshot{}
+	for name, data := range cm.Data {
+		s := util.VolumeSnapshot{}
+		if err := json.Unmarshal([]byte(data), &s); err != nil {
+			return nil, errors.Wrapf(err, "Error decoding snapshot %s", name)
+		}
+		snapshots = append(snapshots, s)
+	}
+	sort.Slice(snapshots, func(i, j int) bool {
+		return snapshots[i].Time.Before(snapshots[j].Time)
+	})
+	return snapshots, nil
+}
+
+// RestoreSnapshot creates the claim in namespace, whose volume the storage provisioner populates
+// from the snapshot named name. The claim requests the capacity and access modes of the claim the
+// snapshot was taken of, and its storage class unless storageClass is set.
+func RestoreSnapshot(client corev1.CoreV1Interface, name, namespace, claim, storageClass string) error {
+	snapshot, err := GetSnapshot(client, name)
+	if err != nil {
+		return err
+	}
+	if snapshot == nil {
+		return errors.Errorf("Snapshot %s not found", name)
+	}
+	capacity, err := resource.ParseQuantity(snapshot.Capacity)
+	if err != nil {
+		return errors.Wrapf(err, "Error parsing capacity of snapshot %s", name)
+	}
+
+	pvc := &v1.PersistentVolumeClaim{
+		ObjectMeta: meta_v1.ObjectMeta{
+			Name:        claim,
+			Namespace:   namespace,
+			Annotations: map[string]string{util.VolumeRestoreAnnotation: name},
+		},
+		Spec: v1.PersistentVolumeClaimSpec{
+			Resources: v1.ResourceRequirements{
+				Requests: v1.ResourceList{v1.ResourceStorage: capacity},
+			},
+		},
+	}
+	for _, m := range snapshot.AccessModes {
+		pvc.Spec.AccessModes = append(pvc.Spec.AccessModes, v1.PersistentVolumeAccessMode(m))
+	}
+	if len(pvc.Spec.AccessModes) == 0 {
+		pvc.Spec.AccessModes = []v1.PersistentVolumeAccessMode{v1.ReadWriteOnce}
+	}
+	if storageClass == "" {
+		storageClass = snapshot.StorageClass
+	}
+	if storageClass != "" {
+		pvc.Spec.StorageClassName = &storageClass
+	}
+
+	if _, err := client.PersistentVolumeClaims(namespace).Create(pvc); err != nil {
+		return errors.Wrapf(err, "Error creating claim %s", claim)
+	}
+	return nil
+}
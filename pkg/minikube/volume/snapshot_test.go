@@ -0,0 +1,202 @@
+/*
+Copyright 2016 The Kubernetes Authors All rights reserved.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package volume
+
+import (
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"sync"
+	"testing"
+	"time"
+
+	meta_v1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/client-go/kubernetes"
+	corev1 "k8s.io/client-go/kubernetes/typed/core/v1"
+	"k8s.io/client-go/pkg/api/v1"
+	"k8s.io/client-go/rest"
+	"k8s.io/minikube/pkg/util"
+)
+
+const (
+	snapshotsPath = "/api/v1/namespaces/kube-system/configmaps/" + util.VolumeSnapshotsConfigMap
+	claimPath     = "/api/v1/namespaces/default/persistentvolumeclaims/data"
+)
+
+func encodeSnapshot(t *testing.T, s util.VolumeSnapshot) string {
+	b, err := json.Marshal(s)
+	if err != nil {
+		t.Fatalf("Error encoding snapshot: %s", err)
+	}
+	return string(b)
+}
+
+// newTestClient returns a client for a fake apiserver serving the snapshots config map and the
+// claim "data" in the default namespace. A snapshot request on the claim is handled like the
+// storage provisioner would, failing for the snapshot named "fails".
+func newTestClient(t *testing.T, snapshots map[string]string, created *[]v1.PersistentVolumeClaim) (corev1.CoreV1Interface, func()) {
+	return newTestClientFor(t, newTestHandler(t, snapshots, created))
+}
+
+// newTestClientFor returns a client for a fake apiserver serving requests with handler.
+func newTestClientFor(t *testing.T, handler http.Handler) (corev1.CoreV1Interface, func()) {
+	server := httptest.NewServer(handler)
+	clientset, err := kubernetes.NewForConfig(&rest.Config{Host: server.URL})
+	if err != nil {
+		t.Fatalf("Error creating client: %s", err)
+	}
+	return clientset.CoreV1(), server.Close
+}
+
+// newTestHandler returns the handler of the fake apiserver of newTestClient.
+func newTestHandler(t *testing.T, snapshots map[string]string, created *[]v1.PersistentVolumeClaim) http.Handler {
+	var lock sync.Mutex
+	claim := v1.PersistentVolumeClaim{ObjectMeta: meta_v1.ObjectMeta{Name: "data", Namespace: "default"}}
+	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		lock.Lock()
+		defer lock.Unlock()
+		w.Header().Set("Content-Type", "application/json")
+		switch {
+		case r.Method == "GET" && r.URL.Path == snapshotsPath:
+			json.NewEncoder(w).Encode(v1.ConfigMap{Data: snapshots})
+		case r.Method == "GET" && r.URL.Path == claimPath:
+			json.NewEncoder(w).Encode(claim)
+		case r.Method == "PUT" && r.URL.Path == claimPath:
+			if err := json.NewDecoder(r.Body).Decode(&claim); err != nil {
+				t.Errorf("Error decoding claim: %s", err)
+			}
+			name := claim.Annotations[util.VolumeSnapshotRequestAnnotation]
+			delete(claim.Annotations, util.VolumeSnapshotRequestAnnotation)
+			if name == "fails" {
+				claim.Annotations[util.VolumeSnapshotErrorAnnotation] = "copy failed"
+			} else {
+				claim.Annotations[util.VolumeSnapshotTakenAnnotation] = name
+			}
+			json.NewEncoder(w).Encode(claim)
+		case r.Method == "POST" && r.URL.Path == "/api/v1/namespaces/test/persistentvolumeclaims":
+			pvc := v1.PersistentVolumeClaim{}
+			if err := json.NewDecoder(r.Body).Decode(&pvc); err != nil {
+				t.Errorf("Error decoding claim: %s", err)
+			}
+			*created = append(*created, pvc)
+			json.NewEncoder(w).Encode(pvc)
+		default:
+			t.Errorf("Unexpected request %s %s", r.Method, r.URL)
+		}
+	})
+}
+
+func TestTakeSnapshot(t *testing.T) {
+	snapshotInterval = time.Millisecond
+	client, closeServer := newTestClient(t, map[string]string{
+		"existing": encodeSnapshot(t, util.VolumeSnapshot{Name: "existing"}),
+	}, nil)
+	defer closeServer()
+
+	if err := TakeSnapshot(client, "default", "data", "seeded"); err != nil {
+		t.Errorf("Error taking snapshot: %s", err)
+	}
+	if err := TakeSnapshot(client, "default", "data", "fails"); err == nil {
+		t.Errorf("Expected an error when the provisioner fails")
+	}
+	if err := TakeSnapshot(client, "default", "data", "existing"); err == nil {
+		t.Errorf("Expected an error for an existing snapshot")
+	}
+}
+
+func TestTakeSnapshotRetriesConflicts(t *testing.T) {
+	snapshotInterval = time.Millisecond
+	handler := newTestHandler(t, nil, nil)
+	conflicts := 0
+	client, closeServer := newTestClientFor(t, http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		// Reject the first update of the claim, as if a controller updated it meanwhile
+		if r.Method == "PUT" && r.URL.Path == claimPath && conflicts == 0 {
+			conflicts++
+			w.Header().Set("Content-Type", "application/json")
+			w.WriteHeader(http.StatusConflict)
+			json.NewEncoder(w).Encode(meta_v1.Status{
+				TypeMeta: meta_v1.TypeMeta{Kind: "Status", APIVersion: "v1"},
+				Status:   meta_v1.StatusFailure,
+				Reason:   meta_v1.StatusReasonConflict,
+				Code:     http.StatusConflict,
+			})
+			return
+		}
+		handler.ServeHTTP(w, r)
+	}))
+	defer closeServer()
+
+	if err := TakeSnapshot(client, "default", "data", "retried"); err != nil {
+		t.Errorf("Error taking snapshot: %s", err)
+	}
+	if conflicts != 1 {
+		t.Errorf("Expected the claim update to conflict once, got %d conflicts", conflicts)
+	}
+}
+
+func TestListSnapshots(t *testing.T) {
+	now := time.Now()
+	client, closeServer := newTestClient(t, map[string]string{
+		"new": encodeSnapshot(t, util.VolumeSnapshot{Name: "new", Time: now}),
+		"old": encodeSnapshot(t, util.VolumeSnapshot{Name: "old", Time: now.Add(-time.Hour)}),
+	}, nil)
+	defer closeServer()
+
+	snapshots, err := ListSnapshots(client)
+	if err != nil {
+		t.Fatalf("Error listing snapshots: %s", err)
+	}
+	if len(snapshots) != 2 || snapshots[0].Name != "old" || snapshots[1].Name != "new" {
+		t.Errorf("Expected snapshots old and new, got %+v", snapshots)
+	}
+}
+
+func TestRestoreSnapshot(t *testing.T) {
+	created := []v1.PersistentVolumeClaim{}
+	client, closeServer := newTestClient(t, map[string]string{
+		"seeded": encodeSnapshot(t, util.VolumeSnapshot{
+			Name:         "seeded",
+			StorageClass: "fast",
+			Capacity:     "2Gi",
+			AccessModes:  []string{"ReadWriteMany"},
+		}),
+	}, &created)
+	defer closeServer()
+
+	if err := RestoreSnapshot(client, "seeded", "test", "copy", ""); err != nil {
+		t.Fatalf("Error restoring snapshot: %s", err)
+	}
+	if err := RestoreSnapshot(client, "seeded", "test", "slow-copy", "slow"); err != nil {
+		t.Fatalf("Error restoring snapshot: %s", err)
+	}
+	if err := RestoreSnapshot(client, "missing", "test", "copy", ""); err == nil {
+		t.Errorf("Expected an error restoring a missing snapshot")
+	}
+
+	if len(created) != 2 {
+		t.Fatalf("Expected 2 claims to be created, got %d", len(created))
+	}
+	pvc := created[0]
+	capacity := pvc.Spec.Resources.Requests[v1.ResourceStorage]
+	if pvc.Name != "copy" || pvc.Annotations[util.VolumeRestoreAnnotation] != "seeded" || capacity.String() != "2Gi" ||
+		*pvc.Spec.StorageClassName != "fast" || pvc.Spec.AccessModes[0] != v1.ReadWriteMany {
+		t.Errorf("Unexpected claim %+v", pvc)
+	}
+	if *created[1].Spec.StorageClassName != "slow" {
+		t.Errorf("Expected storage class slow, got %s", *created[1].Spec.StorageClassName)
+	}
+}
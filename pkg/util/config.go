@@ -20,6 +20,7 @@ import (
 	"fmt"
 	"net"
 	"reflect"
+	"sort"
 	"strconv"
 	"strings"
 	"time"
@@ -104,6 +105,19 @@ func setElement(e reflect.Value, v string) error {
 	return nil
 }
 
+// canSetType returns whether setElement can set elements of type t.
+func canSetType(t reflect.Type) bool {
+	switch reflect.Zero(t).Interface().(type) {
+	case int, int32, int64, string, float32, float64, bool, net.IP, net.IPNet, utilnet.PortRange, time.Duration, []string:
+		return true
+	}
+	switch t.Kind() {
+	case reflect.Int, reflect.Int32, reflect.Int64, reflect.String, reflect.Float32, reflect.Float64, reflect.Bool:
+		return true
+	}
+	return false
+}
+
 func convertInt(e reflect.Value, v string) error {
 	i, err := strconv.Atoi(v)
 	if err != nil {
@@ -144,3 +158,171 @@ func FindAndSet(path string, c interface{}, value string) error {
 	}
 	return setElement(elem, value)
 }
+
+func indirectType(t reflect.Type) reflect.Type {
+	for t.Kind() == reflect.Ptr {
+		t = t.Elem()
+	}
+	return t
+}
+
+// findNestedType is the counterpart of findNestedElement for types: it returns the type of the
+// element corresponding to the dot-separated string parameter.
+func findNestedType(s string, t reflect.Type) (reflect.Type, error) {
+	for _, field := range strings.Split(s, ".") {
+		t = indirectType(t)
+		if t.Kind() != reflect.Struct {
+			return nil, fmt.Errorf("Unable to find field by name: %s", field)
+		}
+		f, ok := t.FieldByName(field)
+		if !ok || f.PkgPath != "" {
+			return nil, fmt.Errorf("Unable to find field by name: %s", field)
+		}
+		t = f.Type
+	}
+	return indirectType(t), nil
+}
+
+// FieldPath is the dot-separated path of a nested value FindAndSet can set, and its type.
+type FieldPath struct {
+	Path string
+	Type string
+	Kind reflect.Kind
+}
+
+// fieldTypes are the types setElement sets by type rather than by kind, by their name.
+var fieldTypes = map[string]reflect.Type{
+	"net.IP":        reflect.TypeOf(net.IP{}),
+	"net.IPNet":     reflect.TypeOf(net.IPNet{}),
+	"net.PortRange": reflect.TypeOf(utilnet.PortRange{}),
+	"time.Duration": reflect.TypeOf(time.Duration(0)),
+	"[]string":      reflect.TypeOf([]string{}),
+}
+
+// kindTypes are the types setElement sets the other fields it can set as, by their kind.
+var kindTypes = map[reflect.Kind]reflect.Type{
+	reflect.Int:     reflect.TypeOf(int(0)),
+	reflect.Int32:   reflect.TypeOf(int32(0)),
+	reflect.Int64:   reflect.TypeOf(int64(0)),
+	reflect.String:  reflect.TypeOf(""),
+	reflect.Float32: reflect.TypeOf(float32(0)),
+	reflect.Float64: reflect.TypeOf(float64(0)),
+	reflect.Bool:    reflect.TypeOf(false),
+}
+
+// ValidateField returns an error if FindAndSet can't set the nested value at path to value, given
+// the fields of the configuration as listed by ListFields. Only the listed type and kind of the
+// field are looked at, so the configuration type itself isn't needed.
+func ValidateField(path string, fields []FieldPath, value string) error {
+	for _, f := range fields {
+		if f.Path != path {
+			continue
+		}
+		t, ok := fieldTypes[f.Type]
+		if !ok {
+			t, ok = kindTypes[f.Kind]
+		}
+		if !ok {
+			return fmt.Errorf("Unable to set type %s.", f.Type)
+		}
+		return setElement(reflect.New(t).Elem(), value)
+	}
+	return fmt.Errorf("Unable to find field by name: %s", path)
+}
+
+// ListFields returns every nested value of c, a struct or a pointer to one, that FindAndSet can
+// set, sorted by path. The fields of embedded structs are listed by their promoted path.
+func ListFields(c interface{}) []FieldPath {
+	root := reflect.TypeOf(c)
+	fields := []FieldPath{}
+	listFields(indirectType(root), "", map[reflect.Type]bool{}, func(path string, t reflect.Type) {
+		// Skip the fields hidden by others with the same name, and ambiguous promoted fields
+		if found, err := findNestedType(path, root); err == nil && found == t {
+			fields = append(fields, FieldPath{Path: path, Type: t.String(), Kind: t.Kind()})
+		}
+	})
+	sort.Slice(fields, func(i, j int) bool { return fields[i].Path < fields[j].Path })
+	return fields
+}
+
+func listFields(t reflect.Type, prefix string, visiting map[reflect.Type]bool, add func(string, reflect.Type)) {
+	// Recursive types would be listed forever
+	if visiting[t] {
+		return
+	}
+	visiting[t] = true
+	defer delete(visiting, t)
+
+	for i := 0; i < t.NumField(); i++ {
+		f := t.Field(i)
+		if f.PkgPath != "" {
+			continue
+		}
+		ft := indirectType(f.Type)
+		switch {
+		case canSetType(ft):
+			add(prefix+f.Name, ft)
+		case ft.Kind() == reflect.Struct && f.Anonymous:
+			listFields(ft, prefix, visiting, add)
+		case ft.Kind() == reflect.Struct:
+			listFields(ft, prefix+f.Name+".", visiting, add)
+		}
+	}
+}
+
+// SuggestFieldPaths returns up to three of the paths of fields that path may be a misspelling
+// of, closest first. Paths ending in the same field name, ignoring case, are the closest, and
+// only they are returned if there are any.
+func SuggestFieldPaths(path string, fields []FieldPath) []string {
+	type suggestion struct {
+		path     string
+		distance int
+	}
+	name := path[strings.LastIndex(path, ".")+1:]
+	maxDistance := len(path)/4 + 1
+	suggestions := []suggestion{}
+	for _, f := range fields {
+		d := levenshtein(strings.ToLower(path), strings.ToLower(f.Path))
+		if strings.EqualFold(f.Path[strings.LastIndex(f.Path, ".")+1:], name) {
+			d = 0
+		}
+		if d <= maxDistance {
+			suggestions = append(suggestions, suggestion{f.Path, d})
+		}
+	}
+	sort.SliceStable(suggestions, func(i, j int) bool { return suggestions[i].distance < suggestions[j].distance })
+	paths := []string{}
+	for i := 0; i < len(suggestions) && i < 3; i++ {
+		if suggestions[0].distance == 0 && suggestions[i].distance > 0 {
+			break
+		}
+		paths = append(paths, suggestions[i].path)
+	}
+	return paths
+}
+
+// levenshtein returns the number of single byte edits that turn s into t.
+func levenshtein(s, t string) int {
+	prev := make([]int, len(t)+1)
+	cur := make([]int, len(t)+1)
+	for j := range prev {
+		prev[j] = j
+	}
+	for i := 1; i <= len(s); i++ {
+		cur[0] = i
+		for j := 1; j <= len(t); j++ {
+			cur[j] = prev[j-1]
+			if s[i-1] != t[j-1] {
+				cur[j]++
+			}
+			if prev[j]+1 < cur[j] {
+				cur[j] = prev[j] + 1
+			}
+			if cur[j-1]+1 < cur[j] {
+				cur[j] = cur[j-1] + 1
+			}
+		}
+		prev, cur = cur, prev
+	}
+	return prev[len(t)]
+}
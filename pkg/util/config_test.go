@@ -195,3 +195,81 @@ func TestSetElement(t *testing.T) {
 
 	}
 }
+
+func TestValidateField(t *testing.T) {
+	fields := ListFields(&testConfig{})
+	for _, tc := range []struct {
+		path   string
+		value  string
+		hasErr bool
+	}{
+		{"A", "newstring", false},
+		{"E.K", "17", false},
+		{"D.I.V", "5s", false},
+		{"D.I.U", "11.22.0.0/16", false},
+		{"D.I.R", "7-11", false},
+		{"D.I.T", "foo", false},
+		{"D.I.V", "5 seconds", true},
+		{"D.I.Q", "11.22.33", true},
+		{"E.K", "seventeen", true},
+		{"D.X", "foo", true},
+		{"A.B", "foo", true},
+		{"D", "foo", true},
+	} {
+		err := ValidateField(tc.path, fields, tc.value)
+		if err != nil && !tc.hasErr {
+			t.Errorf("Unexpected error validating %s=%s: %s", tc.path, tc.value, err)
+		}
+		if err == nil && tc.hasErr {
+			t.Errorf("Expected an error validating %s=%s", tc.path, tc.value)
+		}
+	}
+}
+
+func TestListFields(t *testing.T) {
+	type embedded struct {
+		X int
+		A bool
+	}
+	type withEmbedded struct {
+		embedded
+		A    string
+		Next *withEmbedded
+		m    int
+	}
+
+	var paths []string
+	for _, f := range ListFields(&testConfig{}) {
+		paths = append(paths, f.Path+" "+f.Type)
+	}
+	expected := []string{"A string", "B int", "C float32", "D.F string", "D.G int", "D.H float32",
+		"D.I.M string", "D.I.N int", "D.I.O float32", "D.I.P bool", "D.I.Q net.IP", "D.I.R net.PortRange",
+		"D.I.S []string", "D.I.T util.aliasedString", "D.I.U net.IPNet", "D.I.V time.Duration",
+		"E.J string", "E.K int", "E.L float32"}
+	if !reflect.DeepEqual(paths, expected) {
+		t.Errorf("Expected fields %v, got %v", expected, paths)
+	}
+
+	// Unexported fields, hidden promoted fields and recursive types are skipped
+	expectedFields := []FieldPath{{Path: "A", Type: "string", Kind: reflect.String}}
+	if fields := ListFields(withEmbedded{}); !reflect.DeepEqual(fields, expectedFields) {
+		t.Errorf("Expected fields %v, got %v", expectedFields, fields)
+	}
+}
+
+func TestSuggestFieldPaths(t *testing.T) {
+	fields := ListFields(&testConfig{})
+	for _, tc := range []struct {
+		path     string
+		expected []string
+	}{
+		{"D.I.s", []string{"D.I.S"}},
+		{"E.k", []string{"E.K"}},
+		{"D.I.Prt", []string{"D.I.P", "D.I.R", "D.I.T"}},
+		{"Nothing.Like.It", []string{}},
+	} {
+		if suggestions := SuggestFieldPaths(tc.path, fields); !reflect.DeepEqual(suggestions, tc.expected) {
+			t.Errorf("Expected suggestions %v for %s, got %v", tc.expected, tc.path, suggestions)
+		}
+	}
+}
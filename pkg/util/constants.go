@@ -21,12 +21,68 @@ const (
 	APIServerPort             = 8443
 	DefaultLocalkubeDirectory = "/var/lib/localkube"
 	DefaultCertPath           = DefaultLocalkubeDirectory + "/certs/"
-	DefaultServiceClusterIP   = "10.0.0.1"
+	DefaultServiceCIDR        = "10.0.0.0/24"
+	DefaultPodCIDR            = "10.180.1.0/24"
 	DefaultDNSDomain          = "cluster.local"
 	DefaultDNSIP              = "10.0.0.10"
 	DefaultInsecureRegistry   = "10.0.0.0/24"
+
+	DefaultLocalkubeStatusAddress = "127.0.0.1:10260"
+	LocalkubeStatusPath           = "/status"
+	LocalkubeHealthzPath          = "/healthz"
+	LocalkubeEtcdSnapshotPath     = "/etcd/snapshot"
+	LocalkubeMetricsPath          = "/metrics"
+	LocalkubeAuditPath            = "/audit"
+
+	// LocalkubeStatusAddressFile is the file in the localkube directory localkube writes the
+	// address it serves its status on to, so that it can be found when --status-address is set.
+	LocalkubeStatusAddressFile = "status-address"
+
+	// MetricsComponentLabel is the label of the metrics served on LocalkubeMetricsPath naming
+	// the localkube component they belong to.
+	MetricsComponentLabel = "component"
+
+	// EtcdRestoreFile is the file in the localkube directory holding a snapshot that localkube
+	// restores into an empty etcd the next time it starts.
+	EtcdRestoreFile = "etcd-restore.json"
+
+	// DefaultHostPathProvisionerDir is where the storage provisioner creates volumes, unless another directory is set.
+	DefaultHostPathProvisionerDir = "/tmp/hostpath-provisioner"
+
+	// VolumeSnapshotRequestAnnotation is set on a claim to ask the storage provisioner for a
+	// snapshot of its volume with the given name. The provisioner removes it once it is done.
+	VolumeSnapshotRequestAnnotation = "minikube.k8s.io/snapshot-request"
+	// VolumeSnapshotTakenAnnotation holds the name of the last snapshot taken of a claim's volume.
+	VolumeSnapshotTakenAnnotation = "minikube.k8s.io/snapshot-taken"
+	// VolumeSnapshotErrorAnnotation holds the error of the last snapshot request of a claim.
+	VolumeSnapshotErrorAnnotation = "minikube.k8s.io/snapshot-error"
+	// VolumeRestoreAnnotation is set on a new claim to provision its volume from a snapshot.
+	VolumeRestoreAnnotation = "minikube.k8s.io/restore-snapshot"
+	// VolumeCloneAnnotation is set on a new claim to provision its volume from a copy of the
+	// volume of another claim in the same namespace.
+	VolumeCloneAnnotation = "minikube.k8s.io/clone-from"
+	// VolumeSnapshotsConfigMap is the config map in kube-system holding the volume snapshots, keyed by name.
+	VolumeSnapshotsConfigMap = "minikube-volume-snapshots"
 )
 
+// ProxyModes are the kube-proxy modes minikube and localkube accept.
+var ProxyModes = []string{DefaultProxyMode, "userspace"}
+
+const DefaultProxyMode = "iptables"
+
+// LogComponents are the tags localkube prefixes its log lines with: the servers it runs, and
+// localkube itself for the lines of code shared by the servers.
+var LogComponents = []string{"apiserver", "controller-manager", "scheduler", "kubelet", "proxy", "etcd", "rbac-bootstrap", "storage-provisioner", "localkube"}
+
+// DefaultAdmissionControl is the admission plugins the apiserver runs, in order, unless others are set.
+var DefaultAdmissionControl = []string{
+	"NamespaceLifecycle",
+	"LimitRanger",
+	"ServiceAccount",
+	"DefaultStorageClass",
+	"ResourceQuota",
+}
+
 func GetAlternateDNS(domain string) []string {
 	return []string{"kubernetes.default.svc." + domain, "kubernetes.default.svc", "kubernetes.default", "kubernetes"}
 }
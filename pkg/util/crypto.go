@@ -28,11 +28,34 @@ import (
 	"net"
 	"os"
 	"path/filepath"
+	"strings"
 	"time"
 
 	"github.com/pkg/errors"
+	"k8s.io/apimachinery/pkg/util/validation"
 )
 
+// CertExpiryWarningPeriod is how long before its expiry a certificate is reported as expiring.
+const CertExpiryWarningPeriod = 30 * 24 * time.Hour
+
+// LoadCert reads the PEM encoded certificate at certPath.
+func LoadCert(certPath string) (*x509.Certificate, error) {
+	contents, err := ioutil.ReadFile(certPath)
+	if err != nil {
+		return nil, err
+	}
+	decoded, _ := pem.Decode(contents)
+	if decoded == nil {
+		return nil, errors.New("Unable to decode certificate.")
+	}
+	return x509.ParseCertificate(decoded.Bytes)
+}
+
+// CertExpiresSoon returns whether cert expires within CertExpiryWarningPeriod, or has expired.
+func CertExpiresSoon(cert *x509.Certificate) bool {
+	return time.Now().Add(CertExpiryWarningPeriod).After(cert.NotAfter)
+}
+
 func GenerateCACert(certPath, keyPath string, name string) error {
 	priv, err := rsa.GenerateKey(rand.Reader, 2048)
 	if err != nil {
@@ -56,38 +79,49 @@ func GenerateCACert(certPath, keyPath string, name string) error {
 	return writeCertsAndKeys(&template, certPath, priv, keyPath, &template, priv)
 }
 
+// ParseSANs splits extra subject alternative names for the apiserver certificate into IPs and
+// DNS names, which may be wildcards like *.example.com. Empty entries are skipped.
+func ParseSANs(sans []string) ([]net.IP, []string, error) {
+	ips := []net.IP{}
+	names := []string{}
+	for _, san := range sans {
+		san = strings.TrimSpace(san)
+		if san == "" {
+			continue
+		}
+		if ip := net.ParseIP(san); ip != nil {
+			ips = append(ips, ip)
+			continue
+		}
+		errs := validation.IsDNS1123Subdomain(san)
+		if strings.HasPrefix(san, "*.") {
+			errs = validation.IsWildcardDNS1123Subdomain(san)
+		}
+		if len(errs) > 0 {
+			return nil, nil, errors.Errorf("Invalid subject alternative name %q, must be an IP or a DNS name: %s", san, strings.Join(errs, ", "))
+		}
+		names = append(names, san)
+	}
+	return ips, names, nil
+}
+
 // You may also specify additional subject alt names (either ip or dns names) for the certificate
 // The certificate will be created with file mode 0644. The key will be created with file mode 0600.
 // If the certificate or key files already exist, they will be overwritten.
 // Any parent directories of the certPath or keyPath will be created as needed with file mode 0755.
 func GenerateSignedCert(certPath, keyPath string, ips []net.IP, alternateDNS []string, signerCertPath, signerKeyPath string) error {
-	signerCertBytes, err := ioutil.ReadFile(signerCertPath)
-	if err != nil {
-		return errors.Wrap(err, "Error reading file: signerCertPath")
-	}
-	decodedSignerCert, _ := pem.Decode(signerCertBytes)
-	if decodedSignerCert == nil {
-		return errors.New("Unable to decode certificate.")
-	}
-	signerCert, err := x509.ParseCertificate(decodedSignerCert.Bytes)
-	if err != nil {
-		return errors.Wrap(err, "Error parsing certificate: decodedSignerCert.Bytes")
-	}
-	signerKeyBytes, err := ioutil.ReadFile(signerKeyPath)
+	signerCert, signerKey, err := loadSigner(signerCertPath, signerKeyPath)
 	if err != nil {
-		return errors.Wrap(err, "Error reading file: signerKeyPath")
+		return err
 	}
-	decodedSignerKey, _ := pem.Decode(signerKeyBytes)
-	if decodedSignerKey == nil {
-		return errors.New("Unable to decode key.")
-	}
-	signerKey, err := x509.ParsePKCS1PrivateKey(decodedSignerKey.Bytes)
+
+	serialNumber, err := newSerialNumber()
 	if err != nil {
-		return errors.Wrap(err, "Error parsing prive key: decodedSignerKey.Bytes")
+		return err
 	}
 
 	template := x509.Certificate{
-		SerialNumber: big.NewInt(2),
+		SerialNumber: serialNumber,
 		Subject: pkix.Name{
 			CommonName:   "minikube",
 			Organization: []string{"system:masters"},
@@ -111,6 +145,80 @@ func GenerateSignedCert(certPath, keyPath string, ips []net.IP, alternateDNS []s
 	return writeCertsAndKeys(&template, certPath, priv, keyPath, signerCert, signerKey)
 }
 
+// GenerateClientCert creates a client certificate for the common name and organizations, which
+// the apiserver authenticates as the user and groups, signed by the given signer.
+// The certificate will be created with file mode 0644. The key will be created with file mode 0600.
+func GenerateClientCert(certPath, keyPath string, commonName string, organization []string, signerCertPath, signerKeyPath string) error {
+	signerCert, signerKey, err := loadSigner(signerCertPath, signerKeyPath)
+	if err != nil {
+		return err
+	}
+
+	serialNumber, err := newSerialNumber()
+	if err != nil {
+		return err
+	}
+
+	template := x509.Certificate{
+		SerialNumber: serialNumber,
+		Subject: pkix.Name{
+			CommonName:   commonName,
+			Organization: organization,
+		},
+		NotBefore: time.Now(),
+		NotAfter:  time.Now().Add(time.Hour * 24 * 365),
+
+		KeyUsage:              x509.KeyUsageKeyEncipherment | x509.KeyUsageDigitalSignature,
+		ExtKeyUsage:           []x509.ExtKeyUsage{x509.ExtKeyUsageClientAuth},
+		BasicConstraintsValid: true,
+	}
+
+	priv, err := loadOrGeneratePrivateKey(keyPath)
+	if err != nil {
+		return errors.Wrap(err, "Error loading or generating private key: keyPath")
+	}
+
+	return writeCertsAndKeys(&template, certPath, priv, keyPath, signerCert, signerKey)
+}
+
+func loadSigner(signerCertPath, signerKeyPath string) (*x509.Certificate, *rsa.PrivateKey, error) {
+	signerCertBytes, err := ioutil.ReadFile(signerCertPath)
+	if err != nil {
+		return nil, nil, errors.Wrap(err, "Error reading file: signerCertPath")
+	}
+	decodedSignerCert, _ := pem.Decode(signerCertBytes)
+	if decodedSignerCert == nil {
+		return nil, nil, errors.New("Unable to decode certificate.")
+	}
+	signerCert, err := x509.ParseCertificate(decodedSignerCert.Bytes)
+	if err != nil {
+		return nil, nil, errors.Wrap(err, "Error parsing certificate: decodedSignerCert.Bytes")
+	}
+	signerKeyBytes, err := ioutil.ReadFile(signerKeyPath)
+	if err != nil {
+		return nil, nil, errors.Wrap(err, "Error reading file: signerKeyPath")
+	}
+	decodedSignerKey, _ := pem.Decode(signerKeyBytes)
+	if decodedSignerKey == nil {
+		return nil, nil, errors.New("Unable to decode key.")
+	}
+	signerKey, err := x509.ParsePKCS1PrivateKey(decodedSignerKey.Bytes)
+	if err != nil {
+		return nil, nil, errors.Wrap(err, "Error parsing prive key: decodedSignerKey.Bytes")
+	}
+	return signerCert, signerKey, nil
+}
+
+// newSerialNumber returns a random serial number, which tells a renewed certificate apart from the
+// one it replaces.
+func newSerialNumber() (*big.Int, error) {
+	serialNumber, err := rand.Int(rand.Reader, new(big.Int).Lsh(big.NewInt(1), 128))
+	if err != nil {
+		return nil, errors.Wrap(err, "Error generating serial number")
+	}
+	return serialNumber, nil
+}
+
 func loadOrGeneratePrivateKey(keyPath string) (*rsa.PrivateKey, error) {
 	keyBytes, err := ioutil.ReadFile(keyPath)
 	if err == nil {
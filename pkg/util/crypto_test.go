@@ -17,13 +17,18 @@ limitations under the License.
 package util
 
 import (
+	"crypto/rand"
+	"crypto/rsa"
 	"crypto/x509"
 	"encoding/pem"
 	"io/ioutil"
+	"math/big"
 	"net"
 	"os"
 	"path/filepath"
+	"reflect"
 	"testing"
+	"time"
 
 	"k8s.io/minikube/pkg/minikube/constants"
 )
@@ -145,3 +150,107 @@ func TestGenerateSignedCert(t *testing.T) {
 		})
 	}
 }
+
+func TestGenerateClientCert(t *testing.T) {
+	tmpDir, err := ioutil.TempDir("", "")
+	if err != nil {
+		t.Fatalf("Error generating tmpdir: %v", err)
+	}
+	defer os.RemoveAll(tmpDir)
+
+	signerCertPath := filepath.Join(tmpDir, "ca.crt")
+	signerKeyPath := filepath.Join(tmpDir, "ca.key")
+	if err := GenerateCACert(signerCertPath, signerKeyPath, constants.APIServerName); err != nil {
+		t.Fatalf("Error generating signer cert: %v", err)
+	}
+	certPath := filepath.Join(tmpDir, "node.crt")
+	keyPath := filepath.Join(tmpDir, "node.key")
+
+	if err := GenerateClientCert(certPath, keyPath, "system:node:minikube-node1", []string{"system:nodes"}, signerCertPath, ""); err == nil {
+		t.Fatalf("GenerateClientCert() should have returned error for a missing signer key, but didn't")
+	}
+	if err := GenerateClientCert(certPath, keyPath, "system:node:minikube-node1", []string{"system:nodes"}, signerCertPath, signerKeyPath); err != nil {
+		t.Fatalf("GenerateClientCert() error = %v", err)
+	}
+	c, err := LoadCert(certPath)
+	if err != nil {
+		t.Fatalf("Error loading certificate: %v", err)
+	}
+	if c.Subject.CommonName != "system:node:minikube-node1" || !reflect.DeepEqual(c.Subject.Organization, []string{"system:nodes"}) {
+		t.Errorf("Unexpected subject %+v", c.Subject)
+	}
+	if !reflect.DeepEqual(c.ExtKeyUsage, []x509.ExtKeyUsage{x509.ExtKeyUsageClientAuth}) {
+		t.Errorf("Expected a client certificate, got ext key usage %v", c.ExtKeyUsage)
+	}
+	signer, err := LoadCert(signerCertPath)
+	if err != nil {
+		t.Fatalf("Error loading signer certificate: %v", err)
+	}
+	if err := c.CheckSignatureFrom(signer); err != nil {
+		t.Errorf("Certificate is not signed by the CA: %v", err)
+	}
+}
+
+func TestCertExpiresSoon(t *testing.T) {
+	tmpDir, err := ioutil.TempDir("", "")
+	if err != nil {
+		t.Fatalf("Error generating tmpdir: %v", err)
+	}
+	defer os.RemoveAll(tmpDir)
+
+	caCertPath := filepath.Join(tmpDir, "ca.crt")
+	if err := GenerateCACert(caCertPath, filepath.Join(tmpDir, "ca.key"), constants.APIServerName); err != nil {
+		t.Fatalf("GenerateCACert() error = %v", err)
+	}
+	ca, err := LoadCert(caCertPath)
+	if err != nil {
+		t.Fatalf("Error loading CA cert: %v", err)
+	}
+	if CertExpiresSoon(ca) {
+		t.Errorf("Expected a new CA cert not to expire soon, it expires on %s", ca.NotAfter)
+	}
+
+	priv, err := rsa.GenerateKey(rand.Reader, 2048)
+	if err != nil {
+		t.Fatalf("Error generating key: %v", err)
+	}
+	template := x509.Certificate{
+		SerialNumber: big.NewInt(1),
+		NotBefore:    time.Now(),
+		NotAfter:     time.Now().Add(24 * time.Hour),
+	}
+	certPath := filepath.Join(tmpDir, "expiring.crt")
+	if err := writeCertsAndKeys(&template, certPath, priv, filepath.Join(tmpDir, "expiring.key"), &template, priv); err != nil {
+		t.Fatalf("Error writing cert: %v", err)
+	}
+	cert, err := LoadCert(certPath)
+	if err != nil {
+		t.Fatalf("Error loading cert: %v", err)
+	}
+	if !CertExpiresSoon(cert) {
+		t.Errorf("Expected a cert expiring on %s to expire soon", cert.NotAfter)
+	}
+
+	if _, err := LoadCert(filepath.Join(tmpDir, "ca.key")); err == nil {
+		t.Errorf("Expected an error loading a key as a certificate")
+	}
+}
+
+func TestParseSANs(t *testing.T) {
+	ips, names, err := ParseSANs([]string{"localhost", " 127.0.0.1", "", "minikube.example.com", "*.example.com", "::1"})
+	if err != nil {
+		t.Fatalf("Unexpected error parsing SANs: %v", err)
+	}
+	if len(ips) != 2 || !ips[0].Equal(net.ParseIP("127.0.0.1")) || !ips[1].Equal(net.IPv6loopback) {
+		t.Errorf("Unexpected IPs %v", ips)
+	}
+	if !reflect.DeepEqual(names, []string{"localhost", "minikube.example.com", "*.example.com"}) {
+		t.Errorf("Unexpected DNS names %v", names)
+	}
+
+	for _, san := range []string{"Not_A_Name", "10.0.0.0/24", "example.com:8443"} {
+		if _, _, err := ParseSANs([]string{san}); err == nil {
+			t.Errorf("Expected an error parsing %q", san)
+		}
+	}
+}
@@ -0,0 +1,62 @@
+/*
+Copyright 2016 The Kubernetes Authors All rights reserved.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package util
+
+import (
+	"net/url"
+	"os"
+
+	"github.com/pkg/errors"
+)
+
+// ValidateEtcdConfig checks the external etcd servers are http or https URLs, and that the TLS
+// files are only set along with servers and exist. minikube start checks its flags with it on the
+// host and localkube checks them again in the VM.
+func ValidateEtcdConfig(servers []string, caFile, certFile, keyFile string) error {
+	for _, s := range servers {
+		u, err := url.Parse(s)
+		if err != nil {
+			return errors.Wrapf(err, "Invalid etcd server %q", s)
+		}
+		if (u.Scheme != "http" && u.Scheme != "https") || u.Host == "" {
+			return errors.Errorf("Invalid etcd server %q, must be an http or https URL", s)
+		}
+	}
+
+	files := []struct {
+		flag, path string
+	}{
+		{"--etcd-cafile", caFile},
+		{"--etcd-certfile", certFile},
+		{"--etcd-keyfile", keyFile},
+	}
+	for _, f := range files {
+		if f.path == "" {
+			continue
+		}
+		if len(servers) == 0 {
+			return errors.Errorf("%s can only be used with --etcd-servers", f.flag)
+		}
+		if _, err := os.Stat(f.path); err != nil {
+			return errors.Wrapf(err, "Error reading %s", f.flag)
+		}
+	}
+	if (certFile == "") != (keyFile == "") {
+		return errors.New("--etcd-certfile and --etcd-keyfile must be used together")
+	}
+	return nil
+}
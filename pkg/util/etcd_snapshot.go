@@ -0,0 +1,36 @@
+/*
+Copyright 2016 The Kubernetes Authors All rights reserved.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package util
+
+import "time"
+
+// EtcdSnapshot is a copy of the keys in localkube's etcd, as served by localkube on
+// LocalkubeEtcdSnapshotPath. The keys of the v2 store and of the v3 keyspace are each read at
+// a single point in time.
+type EtcdSnapshot struct {
+	Time time.Time         `json:"time"`
+	V2   []EtcdSnapshotKey `json:"v2"`
+	V3   []EtcdSnapshotKey `json:"v3"`
+}
+
+// EtcdSnapshotKey is a single key in an EtcdSnapshot. TTL is the number of seconds the key had
+// left to live when the snapshot was taken, or 0 if it doesn't expire.
+type EtcdSnapshotKey struct {
+	Key   string `json:"key"`
+	Value []byte `json:"value"`
+	TTL   int64  `json:"ttl,omitempty"`
+}
@@ -0,0 +1,69 @@
+/*
+Copyright 2016 The Kubernetes Authors All rights reserved.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package util
+
+import (
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"testing"
+)
+
+func TestValidateEtcdConfig(t *testing.T) {
+	dir, err := ioutil.TempDir("", "etcd-tls")
+	if err != nil {
+		t.Fatalf("Error creating temp dir: %s", err)
+	}
+	defer os.RemoveAll(dir)
+	ca, cert, key := filepath.Join(dir, "ca.crt"), filepath.Join(dir, "client.crt"), filepath.Join(dir, "client.key")
+	for _, f := range []string{ca, cert, key} {
+		if err := ioutil.WriteFile(f, []byte{}, 0600); err != nil {
+			t.Fatalf("Error writing %s: %s", f, err)
+		}
+	}
+	servers := []string{"https://10.0.0.5:2379"}
+
+	var tests = []struct {
+		description               string
+		servers                   []string
+		caFile, certFile, keyFile string
+		shouldErr                 bool
+	}{
+		{description: "embedded etcd"},
+		{description: "external etcd", servers: servers},
+		{description: "external etcd with TLS", servers: servers, caFile: ca, certFile: cert, keyFile: key},
+		{description: "external etcd without client certificate", servers: []string{"http://127.0.0.1:2379"}, caFile: ca},
+		{description: "server is not a URL", servers: []string{"10.0.0.5:2379"}, shouldErr: true},
+		{description: "server is not http", servers: []string{"unix:///var/run/etcd.sock"}, shouldErr: true},
+		{description: "TLS files without servers", caFile: ca, shouldErr: true},
+		{description: "certificate without key", servers: servers, certFile: cert, shouldErr: true},
+		{description: "key without certificate", servers: servers, keyFile: key, shouldErr: true},
+		{description: "missing CA file", servers: servers, caFile: filepath.Join(dir, "missing.crt"), shouldErr: true},
+	}
+
+	for _, test := range tests {
+		t.Run(test.description, func(t *testing.T) {
+			err := ValidateEtcdConfig(test.servers, test.caFile, test.certFile, test.keyFile)
+			if err != nil && !test.shouldErr {
+				t.Errorf("Unexpected error: %s", err)
+			}
+			if err == nil && test.shouldErr {
+				t.Errorf("Expected an error")
+			}
+		})
+	}
+}
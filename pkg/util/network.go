@@ -0,0 +1,84 @@
+/*
+Copyright 2016 The Kubernetes Authors All rights reserved.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package util
+
+import (
+	"net"
+
+	"github.com/pkg/errors"
+)
+
+// GetServiceClusterIP returns the IP of the kubernetes service, which the apiserver takes from
+// the start of the service cluster IP range.
+func GetServiceClusterIP(serviceCIDR net.IPNet) net.IP {
+	ip := make(net.IP, len(serviceCIDR.IP))
+	copy(ip, serviceCIDR.IP.Mask(serviceCIDR.Mask))
+	for i := len(ip) - 1; i >= 0; i-- {
+		ip[i]++
+		if ip[i] != 0 {
+			break
+		}
+	}
+	return ip
+}
+
+// GetDefaultDNSIP returns the cluster IP of the kube-dns service unless another one is set, which
+// is the tenth IP of the service cluster IP range, e.g. 10.0.0.10 for 10.0.0.0/24.
+func GetDefaultDNSIP(serviceCIDR net.IPNet) net.IP {
+	ip := make(net.IP, len(serviceCIDR.IP))
+	copy(ip, serviceCIDR.IP.Mask(serviceCIDR.Mask))
+	ip[len(ip)-1] += 10
+	return ip
+}
+
+// CIDRsOverlap returns whether the ranges a and b have IPs in common.
+func CIDRsOverlap(a, b net.IPNet) bool {
+	return a.Contains(b.IP) || b.Contains(a.IP)
+}
+
+// ValidateNetworkRanges checks that the pod CIDR and the service cluster IP range don't overlap,
+// and that the DNS IP is in the service cluster IP range without being the kubernetes service IP.
+func ValidateNetworkRanges(podCIDR, serviceCIDR net.IPNet, dnsIP net.IP) error {
+	if CIDRsOverlap(podCIDR, serviceCIDR) {
+		return errors.Errorf("The pod CIDR %s overlaps the service cluster IP range %s", podCIDR.String(), serviceCIDR.String())
+	}
+	if !serviceCIDR.Contains(dnsIP) {
+		return errors.Errorf("The DNS IP %s is not in the service cluster IP range %s", dnsIP, serviceCIDR.String())
+	}
+	if dnsIP.Equal(serviceCIDR.IP.Mask(serviceCIDR.Mask)) || dnsIP.Equal(GetServiceClusterIP(serviceCIDR)) {
+		return errors.Errorf("The DNS IP %s is reserved, the first two IPs of the service cluster IP range can't be used", dnsIP)
+	}
+	return nil
+}
+
+// ValidateProxyConfig checks the proxy mode is one of ProxyModes and, if set, that the cluster
+// CIDR parses.
+func ValidateProxyConfig(mode, clusterCIDR string) error {
+	valid := false
+	for _, m := range ProxyModes {
+		valid = valid || m == mode
+	}
+	if !valid {
+		return errors.Errorf("Invalid proxy mode %q, must be one of %v", mode, ProxyModes)
+	}
+	if clusterCIDR != "" {
+		if _, _, err := net.ParseCIDR(clusterCIDR); err != nil {
+			return errors.Wrap(err, "Error parsing --cluster-cidr")
+		}
+	}
+	return nil
+}
@@ -0,0 +1,113 @@
+/*
+Copyright 2016 The Kubernetes Authors All rights reserved.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package util
+
+import (
+	"net"
+	"testing"
+)
+
+func parseCIDR(t *testing.T, cidr string) net.IPNet {
+	_, n, err := net.ParseCIDR(cidr)
+	if err != nil {
+		t.Fatalf("Error parsing %s: %s", cidr, err)
+	}
+	return *n
+}
+
+func TestGetServiceClusterIP(t *testing.T) {
+	var tests = []struct {
+		serviceCIDR string
+		expected    string
+	}{
+		{"10.0.0.0/24", "10.0.0.1"},
+		{"10.0.0.5/24", "10.0.0.1"},
+		{"172.31.255.0/16", "172.31.0.1"},
+	}
+	for _, test := range tests {
+		if ip := GetServiceClusterIP(parseCIDR(t, test.serviceCIDR)); ip.String() != test.expected {
+			t.Errorf("Expected service IP %s for %s, got %s", test.expected, test.serviceCIDR, ip)
+		}
+	}
+}
+
+func TestGetDefaultDNSIP(t *testing.T) {
+	var tests = []struct {
+		serviceCIDR string
+		expected    string
+	}{
+		{DefaultServiceCIDR, DefaultDNSIP},
+		{"172.31.0.0/24", "172.31.0.10"},
+		{"172.31.255.0/16", "172.31.0.10"},
+	}
+	for _, test := range tests {
+		if ip := GetDefaultDNSIP(parseCIDR(t, test.serviceCIDR)); ip.String() != test.expected {
+			t.Errorf("Expected DNS IP %s for %s, got %s", test.expected, test.serviceCIDR, ip)
+		}
+	}
+}
+
+func TestValidateNetworkRanges(t *testing.T) {
+	var tests = []struct {
+		podCIDR     string
+		serviceCIDR string
+		dnsIP       string
+		shouldErr   bool
+	}{
+		{DefaultPodCIDR, DefaultServiceCIDR, DefaultDNSIP, false},
+		{"172.30.0.0/16", "172.31.0.0/24", "172.31.0.53", false},
+		{"10.0.0.0/8", "10.0.0.0/24", "10.0.0.10", true},
+		{"10.0.0.128/25", "10.0.0.0/24", "10.0.0.10", true},
+		{DefaultPodCIDR, DefaultServiceCIDR, "10.0.1.10", true},
+		{DefaultPodCIDR, DefaultServiceCIDR, "10.0.0.0", true},
+		{DefaultPodCIDR, DefaultServiceCIDR, "10.0.0.1", true},
+	}
+	for _, test := range tests {
+		err := ValidateNetworkRanges(parseCIDR(t, test.podCIDR), parseCIDR(t, test.serviceCIDR), net.ParseIP(test.dnsIP))
+		if err != nil && !test.shouldErr {
+			t.Errorf("Unexpected error for %+v: %s", test, err)
+		}
+		if err == nil && test.shouldErr {
+			t.Errorf("Expected an error for %+v", test)
+		}
+	}
+}
+
+func TestValidateProxyConfig(t *testing.T) {
+	var tests = []struct {
+		mode      string
+		cidr      string
+		shouldErr bool
+	}{
+		{mode: "iptables"},
+		{mode: "userspace", cidr: "10.180.0.0/16"},
+		{mode: "ipvs", shouldErr: true},
+		{mode: "iptables", cidr: "10.180.0.0", shouldErr: true},
+	}
+
+	for _, test := range tests {
+		t.Run(test.mode, func(t *testing.T) {
+			err := ValidateProxyConfig(test.mode, test.cidr)
+			if err != nil && !test.shouldErr {
+				t.Errorf("Unexpected error: %s", err)
+			}
+			if err == nil && test.shouldErr {
+				t.Errorf("Expected an error")
+			}
+		})
+	}
+}
@@ -0,0 +1,45 @@
+/*
+Copyright 2016 The Kubernetes Authors All rights reserved.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package util
+
+import "fmt"
+
+// ComponentStatus is the health of a single localkube component, as served by localkube
+// on LocalkubeStatusPath.
+type ComponentStatus struct {
+	Name      string `json:"name"`
+	Ready     bool   `json:"ready"`
+	Failed    bool   `json:"failed"`
+	Restarts  int    `json:"restarts"`
+	Uptime    string `json:"uptime"`
+	LastError string `json:"lastError,omitempty"`
+}
+
+// Summary returns a one line description of the component's health.
+func (c ComponentStatus) Summary() string {
+	health := "Not Ready"
+	if c.Failed {
+		health = "Failed"
+	} else if c.Ready {
+		health = "Ready"
+	}
+	summary := fmt.Sprintf("%s (restarts: %d, uptime: %s)", health, c.Restarts, c.Uptime)
+	if c.LastError != "" {
+		summary += fmt.Sprintf(", last error: %s", c.LastError)
+	}
+	return summary
+}
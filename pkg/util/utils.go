@@ -18,7 +18,6 @@ package util
 
 import (
 	"fmt"
-	"io"
 	"io/ioutil"
 	"net/http"
 	"net/url"
@@ -42,28 +41,6 @@ type RetriableError struct {
 
 func (r RetriableError) Error() string { return "Temporary Error: " + r.Err.Error() }
 
-// Until endlessly loops the provided function until a message is received on the done channel.
-// The function will wait the duration provided in sleep between function calls. Errors will be sent on provider Writer.
-func Until(fn func() error, w io.Writer, name string, sleep time.Duration, done <-chan struct{}) {
-	var exitErr error
-	for {
-		select {
-		case <-done:
-			return
-		default:
-			exitErr = fn()
-			if exitErr == nil {
-				fmt.Fprintf(w, Pad("%s: Exited with no errors.\n"), name)
-			} else {
-				fmt.Fprintf(w, Pad("%s: Exit with error: %v"), name, exitErr)
-			}
-
-			// wait provided duration before trying again
-			time.Sleep(sleep)
-		}
-	}
-}
-
 func Pad(str string) string {
 	return fmt.Sprintf("\n%s\n", str)
 }
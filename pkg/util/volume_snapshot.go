@@ -0,0 +1,32 @@
+/*
+Copyright 2016 The Kubernetes Authors All rights reserved.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package util
+
+import "time"
+
+// VolumeSnapshot is a copy of the volume of a claim taken by the storage provisioner, as recorded
+// in the VolumeSnapshotsConfigMap. Capacity and AccessModes are those of the claim, so a claim
+// restoring the snapshot can request the same.
+type VolumeSnapshot struct {
+	Name         string    `json:"name"`
+	Namespace    string    `json:"namespace"`
+	Claim        string    `json:"claim"`
+	StorageClass string    `json:"storageClass,omitempty"`
+	Capacity     string    `json:"capacity"`
+	AccessModes  []string  `json:"accessModes"`
+	Time         time.Time `json:"time"`
+}